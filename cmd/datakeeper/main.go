@@ -8,6 +8,7 @@ import (
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/auth"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 	"google.golang.org/grpc"
@@ -42,15 +43,18 @@ func main() {
 	// init ctx
 	ctx := context.Background()
 
-	// auth service client
-	authServer := c.AuthService.Host + ":" + strconv.Itoa(c.AuthService.Port)
-	authn, err := grpc.NewClient(
-		authServer,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithIdleTimeout(c.AuthService.Timeout*time.Second),
-	)
-	if err != nil {
-		panic(err)
+	// auth service client, local auth provider needs no auth service
+	var authn *grpc.ClientConn
+	if c.AuthService.Provider == auth.ProviderGRPC {
+		authServer := c.AuthService.Host + ":" + strconv.Itoa(c.AuthService.Port)
+		authn, err = grpc.NewClient(
+			authServer,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithIdleTimeout(c.AuthService.Timeout*time.Second),
+		)
+		if err != nil {
+			panic(err)
+		}
 	}
 	logger := logger()
 
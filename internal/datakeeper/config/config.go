@@ -2,8 +2,10 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -19,6 +21,18 @@ type Config struct {
 	Postgres Postgres
 	// AuthService auth service config
 	AuthService AuthService
+	// Session access and refresh token config
+	Session Session
+	// KDF master password key derivation config
+	KDF KDF
+	// KeyStore user key store config
+	KeyStore KeyStore
+	// Vault auto-lock config
+	Vault Vault
+	// Cipher data encryption config
+	Cipher Cipher
+	// ZeroKnowledge zero-knowledge mode config
+	ZeroKnowledge ZeroKnowledge
 }
 
 // Postgres postgres config
@@ -36,6 +50,88 @@ type AuthService struct {
 	Port    int
 	Timeout time.Duration
 	JWTKey  string
+	// JWTAlgorithms accepted signing algorithms, tokens signed with any other algorithm are rejected
+	JWTAlgorithms []string
+	// JWTIssuer required "iss" claim, not checked if empty
+	JWTIssuer string
+	// JWTAudience required "aud" claim, not checked if empty
+	JWTAudience string
+	// JWTLeeway allowed clock skew for "exp", "nbf" and "iat"
+	JWTLeeway time.Duration
+	// JWKS file path or http(s) URL of JSON Web Key Set with RS256/ES256 verification keys
+	JWKS string
+	// JWKSRefresh reload interval of JSON Web Key Set, unknown key id reloads it earlier
+	JWKSRefresh time.Duration
+	// Provider checks login and password: grpc auth service or local users table
+	Provider string
+}
+
+// Session access and refresh token config, access tokens are signed with auth service JWT key
+type Session struct {
+	// AccessTokenTTL lifetime of access token
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL lifetime of refresh token, session ends if it is not refreshed for this time
+	RefreshTokenTTL time.Duration
+}
+
+// KDF Argon2id parameters for new users, existing users keep parameters stored with their key
+type KDF struct {
+	// Time number of passes
+	Time int
+	// MemoryMB memory in MiB
+	MemoryMB int
+	// Threads parallelism
+	Threads int
+}
+
+// Validate Argon2id parameters, zero passes or parallelism out of uint8 range would panic on first key derivation
+func (k KDF) Validate() error {
+	if k.Time < 1 {
+		return fmt.Errorf("kdf time must be at least 1, got %d", k.Time)
+	}
+	if k.MemoryMB < 1 {
+		return fmt.Errorf("kdf memory must be at least 1 MiB, got %d", k.MemoryMB)
+	}
+	if k.Threads < 1 || k.Threads > 255 {
+		return fmt.Errorf("kdf threads must be between 1 and 255, got %d", k.Threads)
+	}
+	return nil
+}
+
+// KeyStore user key store config, user keys are stored wrapped by server key-encryption key
+type KeyStore struct {
+	// Provider key-encryption key provider: file or transit
+	Provider string
+	// KeyFile path to file with hex encoded key-encryption key
+	KeyFile string
+	// TransitAddr address of transit encryption API
+	TransitAddr string
+	// TransitKey name of key in transit encryption API
+	TransitKey string
+	// TransitToken token of transit encryption API
+	TransitToken string
+	// TransitTimeout transit encryption API timeout
+	TransitTimeout time.Duration
+}
+
+// Vault auto-lock config, zero timeout is disabled
+type Vault struct {
+	// IdleTimeout vault is locked if key is not used for this time
+	IdleTimeout time.Duration
+	// MaxLifetime vault is locked after this time since unlock
+	MaxLifetime time.Duration
+}
+
+// Cipher data encryption config, data encrypted with any known suite stays readable
+type Cipher struct {
+	// Suite cipher suite for new data: aes-256-gcm or xchacha20-poly1305
+	Suite string
+}
+
+// ZeroKnowledge zero-knowledge mode config, blobs are encrypted by client and server never decrypts them
+type ZeroKnowledge struct {
+	// Enabled blob endpoints are served
+	Enabled bool
 }
 
 // LoadConfig load config
@@ -51,10 +147,32 @@ func LoadConfig() (*Config, error) {
 	postgresDB := flag.String("postgres_db", getEnv("POSTGRES_DB", "postgres"), "Postgres database")
 	postgresUser := flag.String("postgres_user", getEnv("POSTGRES_USER", "postgres"), "Postgres user")
 	postgresPassword := flag.String("postgres_password", getEnv("POSTGRES_PASSWORD", ""), "Postgres password")
+	authProvider := flag.String("auth_provider", getEnv("AUTH_PROVIDER", "grpc"), "Auth provider: grpc or local")
 	authHost := flag.String("auth_host", getEnv("AUTH_HOST", "localhost"), "Auth host")
 	authPort := flag.Int("auth_port", getEnvAsInt("AUTH_PORT", 50051), "Auth port")
 	authTimeout := flag.Duration("auth_timeout", getEnvAsDuration("AUTH_TIMEOUT", 30*time.Second), "Auth service timeout")
 	authJWTKey := flag.String("auth_jwt_key", getEnv("AUTH_JWT_KEY", ""), "Auth service JWT key")
+	authJWTAlgorithms := flag.String("auth_jwt_algorithms", getEnv("AUTH_JWT_ALGORITHMS", "HS256"), "Accepted JWT signing algorithms, comma separated")
+	authJWTIssuer := flag.String("auth_jwt_issuer", getEnv("AUTH_JWT_ISSUER", ""), "Required JWT issuer")
+	authJWTAudience := flag.String("auth_jwt_audience", getEnv("AUTH_JWT_AUDIENCE", ""), "Required JWT audience")
+	authJWTLeeway := flag.Duration("auth_jwt_leeway", getEnvAsDuration("AUTH_JWT_LEEWAY", 30*time.Second), "Allowed JWT clock skew")
+	authJWKS := flag.String("auth_jwks", getEnv("AUTH_JWKS", ""), "JSON Web Key Set file or URL")
+	authJWKSRefresh := flag.Duration("auth_jwks_refresh", getEnvAsDuration("AUTH_JWKS_REFRESH", 10*time.Minute), "JSON Web Key Set reload interval")
+	accessTokenTTL := flag.Duration("access_token_ttl", getEnvAsDuration("ACCESS_TOKEN_TTL", 15*time.Minute), "Access token lifetime")
+	refreshTokenTTL := flag.Duration("refresh_token_ttl", getEnvAsDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour), "Refresh token lifetime")
+	kdfTime := flag.Int("kdf_time", getEnvAsInt("KDF_TIME", 3), "Argon2id number of passes")
+	kdfMemory := flag.Int("kdf_memory_mb", getEnvAsInt("KDF_MEMORY_MB", 64), "Argon2id memory in MiB")
+	kdfThreads := flag.Int("kdf_threads", getEnvAsInt("KDF_THREADS", 4), "Argon2id parallelism")
+	vaultIdleTimeout := flag.Duration("vault_idle_timeout", getEnvAsDuration("VAULT_IDLE_TIMEOUT", 15*time.Minute), "Vault idle timeout")
+	vaultMaxLifetime := flag.Duration("vault_max_lifetime", getEnvAsDuration("VAULT_MAX_LIFETIME", 12*time.Hour), "Vault absolute lifetime")
+	kekProvider := flag.String("kek_provider", getEnv("KEK_PROVIDER", "file"), "Key-encryption key provider: file or transit")
+	kekFile := flag.String("kek_file", getEnv("KEK_FILE", ""), "Key-encryption key file")
+	kekTransitAddr := flag.String("kek_transit_addr", getEnv("KEK_TRANSIT_ADDR", ""), "Transit encryption API address")
+	kekTransitKey := flag.String("kek_transit_key", getEnv("KEK_TRANSIT_KEY", ""), "Transit encryption API key name")
+	kekTransitToken := flag.String("kek_transit_token", getEnv("KEK_TRANSIT_TOKEN", ""), "Transit encryption API token")
+	kekTransitTimeout := flag.Duration("kek_transit_timeout", getEnvAsDuration("KEK_TRANSIT_TIMEOUT", 5*time.Second), "Transit encryption API timeout")
+	zeroKnowledge := flag.Bool("zero_knowledge", getEnvAsBool("ZERO_KNOWLEDGE", false), "Enable zero-knowledge blob endpoints")
+	cipherSuite := flag.String("cipher_suite", getEnv("CIPHER_SUITE", "aes-256-gcm"), "Cipher suite for new data: aes-256-gcm or xchacha20-poly1305")
 
 	// Parse flags
 	flag.Parse()
@@ -71,11 +189,49 @@ func LoadConfig() (*Config, error) {
 			Password: *postgresPassword,
 		},
 		AuthService: AuthService{
-			Host:    *authHost,
-			Port:    *authPort,
-			Timeout: *authTimeout,
-			JWTKey:  *authJWTKey,
+			Provider:      *authProvider,
+			Host:          *authHost,
+			Port:          *authPort,
+			Timeout:       *authTimeout,
+			JWTKey:        *authJWTKey,
+			JWTAlgorithms: splitList(*authJWTAlgorithms),
+			JWTIssuer:     *authJWTIssuer,
+			JWTAudience:   *authJWTAudience,
+			JWTLeeway:     *authJWTLeeway,
+			JWKS:          *authJWKS,
+			JWKSRefresh:   *authJWKSRefresh,
+		},
+		Session: Session{
+			AccessTokenTTL:  *accessTokenTTL,
+			RefreshTokenTTL: *refreshTokenTTL,
+		},
+		KDF: KDF{
+			Time:     *kdfTime,
+			MemoryMB: *kdfMemory,
+			Threads:  *kdfThreads,
+		},
+		KeyStore: KeyStore{
+			Provider:       *kekProvider,
+			KeyFile:        *kekFile,
+			TransitAddr:    *kekTransitAddr,
+			TransitKey:     *kekTransitKey,
+			TransitToken:   *kekTransitToken,
+			TransitTimeout: *kekTransitTimeout,
+		},
+		Vault: Vault{
+			IdleTimeout: *vaultIdleTimeout,
+			MaxLifetime: *vaultMaxLifetime,
 		},
+		Cipher: Cipher{
+			Suite: *cipherSuite,
+		},
+		ZeroKnowledge: ZeroKnowledge{
+			Enabled: *zeroKnowledge,
+		},
+	}
+
+	if err := config.KDF.Validate(); err != nil {
+		return nil, err
 	}
 
 	return config, nil
@@ -107,6 +263,17 @@ func getEnvAsBool(name string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// splitList split comma separated list, empty items are skipped
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getEnvAsDuration gets the environment variable value as duration or returns a default value
 func getEnvAsDuration(name string, defaultVal time.Duration) time.Duration {
 	valStr := getEnv(name, "")
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKDF_Validate(t *testing.T) {
+	assert.NoError(t, KDF{Time: 3, MemoryMB: 64, Threads: 4}.Validate())
+	assert.NoError(t, KDF{Time: 1, MemoryMB: 1, Threads: 255}.Validate())
+
+	assert.Error(t, KDF{Time: 0, MemoryMB: 64, Threads: 4}.Validate())
+	assert.Error(t, KDF{Time: 3, MemoryMB: 0, Threads: 4}.Validate())
+	assert.Error(t, KDF{Time: 3, MemoryMB: 64, Threads: 0}.Validate())
+	// uint8 would wrap to 0
+	assert.Error(t, KDF{Time: 3, MemoryMB: 64, Threads: 256}.Validate())
+}
@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
 
 // ContentType Data type
 type ContentType string
@@ -8,8 +12,30 @@ type ContentType string
 const (
 	// LogPass Login/password
 	LogPass ContentType = "LOG_PASS"
-	// File Binary/Text file (file size < 5 MB)
+	// File Binary/Text file (file size < 1 GB)
 	File ContentType = "File"
+	// Card Bank card
+	Card ContentType = "CARD"
+	// Note Secure text note (note size < 64 KB)
+	Note ContentType = "NOTE"
+	// OTP TOTP secret
+	OTP ContentType = "OTP"
+	// SSHKey SSH key pair
+	SSHKey ContentType = "SSH_KEY"
+	// Template User-defined record type
+	Template ContentType = "TEMPLATE"
+	// Record Record of user-defined type
+	Record ContentType = "RECORD"
+	// SeedPhrase Crypto wallet BIP-39 recovery phrase
+	SeedPhrase ContentType = "SEED_PHRASE"
+	// APIToken API token or secret with expiry
+	APIToken ContentType = "API_TOKEN"
+	// Identity Identity document (passport, ID card, driver license)
+	Identity ContentType = "IDENTITY"
+	// WiFi Wi-Fi network credentials
+	WiFi ContentType = "WIFI"
+	// Blob Opaque blob encrypted by client, server never decrypts it
+	Blob ContentType = "BLOB"
 )
 
 // Data User's stored data
@@ -18,10 +44,20 @@ type Data struct {
 	UUID string
 	// Content data
 	Content []byte
+	// DataKey data key wrapped by user key, nil if content is encrypted with user key
+	DataKey []byte
 	// ContentType content type
 	ContentType ContentType
+	// Metadata client-supplied metadata of blob, stored as is
+	Metadata []byte
 	// CreatedAt Created at time
 	CreatedAt time.Time
 	// CreatedBy User who created this data
 	CreatedBy string
 }
+
+// AssociatedData identity of data authenticated together with its content,
+// content moved to another row, user or content type fails to decrypt
+func (d Data) AssociatedData() []byte {
+	return lib.AssociatedData(d.UUID, d.CreatedBy, string(d.ContentType))
+}
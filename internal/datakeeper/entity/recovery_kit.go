@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// RecoveryKit recovery kit of user key, shares themselves are never stored
+type RecoveryKit struct {
+	// Login user
+	Login string
+	// KitID random id written to every share, shares of replaced kit are rejected
+	KitID []byte
+	// Threshold number of shares to rebuild key
+	Threshold int
+	// Shares number of issued shares
+	Shares int
+	// CheckValue key check value, used to reject wrong shares
+	CheckValue []byte
+	// CreatedAt when created
+	CreatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// TokenFamily session of user, all refresh tokens rotated from one sign in belong to one family
+type TokenFamily struct {
+	// ID family id, written to access tokens of session
+	ID string
+	// Login user
+	Login string
+	// CreatedAt when signed in
+	CreatedAt time.Time
+	// RevokedAt when revoked by logout or reuse of refresh token, nil for active session
+	RevokedAt *time.Time
+}
+
+// RefreshToken one-time refresh token, only hash of token is stored
+type RefreshToken struct {
+	// Hash SHA-256 of token
+	Hash []byte
+	// FamilyID session of token
+	FamilyID string
+	// Login user
+	Login string
+	// ExpiresAt when expires
+	ExpiresAt time.Time
+	// CreatedAt when issued
+	CreatedAt time.Time
+	// UsedAt when exchanged for new tokens, nil for unused token
+	UsedAt *time.Time
+}
@@ -1,5 +1,17 @@
 package entity
 
+import "time"
+
 type USER string
 
 const UserEmail USER = "User"
+
+// User account of local auth provider
+type User struct {
+	// Login user
+	Login string
+	// PasswordHash Argon2id hash of password in PHC string format
+	PasswordHash string
+	// CreatedAt when registered
+	CreatedAt time.Time
+}
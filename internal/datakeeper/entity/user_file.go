@@ -1,15 +1,32 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
 
 // FileRepo stored file
 type FileRepo struct {
 	// UUID
 	UUID string
-	// File content
+	// File content, empty if content is stored in chunks
 	Content []byte
+	// Chunked content is stream ciphertext stored in file chunks
+	Chunked bool
+	// DataKey data key wrapped by user key, nil if content is encrypted with user key
+	DataKey []byte
 	// CreatedAt when created
 	CreatedAt time.Time
 	// CreatedBy created by user
 	CreatedBy string
 }
+
+// fileContentLabel separates file content from file metadata stored in Data with same uuid
+const fileContentLabel = "FILE_CONTENT"
+
+// AssociatedData identity of file authenticated together with its content,
+// content moved to another file or user fails to decrypt
+func (f FileRepo) AssociatedData() []byte {
+	return lib.AssociatedData(f.UUID, f.CreatedBy, fileContentLabel)
+}
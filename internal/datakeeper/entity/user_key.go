@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// KeyParams parameters to derive user key from master password
+type KeyParams struct {
+	// Login user
+	Login string
+	// Salt random per-user Argon2id salt
+	Salt []byte
+	// Time Argon2id number of passes
+	Time uint32
+	// Memory Argon2id memory in KiB
+	Memory uint32
+	// Threads Argon2id parallelism
+	Threads uint8
+	// CheckValue key check value, used to reject wrong master password
+	CheckValue []byte
+	// CreatedAt when created
+	CreatedAt time.Time
+}
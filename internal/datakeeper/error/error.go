@@ -1,10 +1,63 @@
 package customerr
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
 
 const INVALID_TOKEN = "invalid token"
 const NO_USER_IN_CONTEXT = "no user in context"
 const NO_KEY_IN_CONTEXT = "no key in context"
+const INVALID_CARD_NUMBER = "invalid card number"
+const INVALID_CARD_EXPIRY = "invalid card expiry date"
+const CARD_EXPIRED = "card expired"
+const CARD_NOT_FOUND = "card not found"
+const NOTE_TOO_LARGE = "note is too large"
+const NOTE_NOT_FOUND = "note not found"
+const INVALID_OTP_URI = "invalid otpauth uri"
+const INVALID_OTP_SECRET = "invalid otp secret"
+const INVALID_OTP_PARAMS = "invalid otp algorithm, digits or period"
+const INVALID_SSH_KEY = "invalid ssh private key"
+const SSH_KEY_PASSPHRASE_REQUIRED = "ssh private key is protected by passphrase"
+const INVALID_SSH_KEY_PASSPHRASE = "invalid ssh key passphrase"
+const UNSUPPORTED_SSH_KEY_TYPE = "unsupported ssh key type or size"
+const SSH_KEY_NOT_FOUND = "ssh key not found"
+const INVALID_TEMPLATE = "invalid template"
+const INVALID_RECORD = "invalid record"
+const TEMPLATE_NOT_FOUND = "template not found"
+const TEMPLATE_IN_USE = "template is used by records"
+const INVALID_SEED_PHRASE = "invalid seed phrase"
+const API_TOKEN_REQUIRED = "api token is required"
+const API_TOKEN_NOT_FOUND = "api token not found"
+const INVALID_EXPIRY_FILTER = "expiring within days must not be negative"
+const INVALID_IDENTITY = "invalid identity document"
+const SCAN_NOT_FOUND = "attached scan not found"
+const INVALID_WIFI = "invalid wifi credentials"
+const INVALID_QR_SIZE = "qr code size must be between 128 and 1024"
+const INVALID_OLD_KEY = "old key does not match current key"
+const INVALID_NEW_KEY = "new key must be at least 16 characters and differ from old key"
+const WEAK_MASTER_PASSWORD = "master password must be at least 8 characters"
+const INVALID_MASTER_PASSWORD = "invalid master password"
+const KEY_REQUIRED = "key is required for account without master password"
+const VAULT_LOCKED = "vault locked"
+const INVALID_BLOB = "invalid blob: content is required, uuid and metadata must be valid"
+const BLOB_TOO_LARGE = "blob is too large"
+const BLOB_NOT_FOUND = "blob not found"
+const INVALID_RECOVERY_KIT = "invalid recovery kit: threshold must be at least 2 and not greater than number of shares, at most 16 shares"
+const RECOVERY_KIT_NOT_FOUND = "recovery kit not found"
+const INVALID_RECOVERY_SHARE = "invalid recovery share"
+const RECOVERY_FAILED = "recovery shares do not rebuild user key"
+const INTEGRITY_CHECK_FAILED = "integrity check failed, record was modified, moved to another row or relabeled"
+const INVALID_REFRESH_TOKEN = "invalid or expired refresh token"
+const REFRESH_TOKEN_REUSED = "refresh token was already used, session is revoked"
+const TOKEN_REVOKED = "token revoked"
+const TOKEN_REQUIRED = "access token is required in Authorization header or cookie"
+const INVALID_CREDENTIALS = "invalid login or password"
+const USER_ALREADY_EXISTS = "user already exists"
+const INVALID_REGISTRATION = "login is required and password must be at least 8 characters"
+const INVALID_AUTHORIZATION_HEADER = "authorization header must be \"Bearer <token>\""
 
 // Custom error
 type CustomError struct {
@@ -37,3 +90,20 @@ func AddDeveloperMessage(err error, message string) *CustomError {
 func Error(message string) *CustomError {
 	return &CustomError{Err: errors.New(message), Message: message}
 }
+
+// ErrorWithCode error with http status code
+func ErrorWithCode(message string, code int) *CustomError {
+	return &CustomError{Err: errors.New(message), Message: message, Code: code}
+}
+
+// IntegrityError report content which does not belong to its row, other errors are returned as is
+func IntegrityError(uuid string, err error) error {
+	if !errors.Is(err, lib.ErrIntegrity) {
+		return err
+	}
+	return &CustomError{
+		Message: INTEGRITY_CHECK_FAILED + ": " + uuid,
+		Code:    http.StatusInternalServerError,
+		Err:     err,
+	}
+}
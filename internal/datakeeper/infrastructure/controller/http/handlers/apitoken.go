@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// APITokenService store api tokens with expiry for user
+type APITokenService interface {
+	// Create save api token
+	Create(ctx context.Context, r CreateAPITokenRequest) (*CreateAPITokenResponse, error)
+	// Update update api token
+	Update(ctx context.Context, r UpdateAPITokenRequest) (*UpdateAPITokenResponse, error)
+	// Delete delete api token
+	Delete(ctx context.Context, r DeleteAPITokenRequest) (*DeleteAPITokenResponse, error)
+	// GetAll get api tokens for user sorted by expiry
+	GetAll(ctx context.Context, r GetAllAPITokensRequest) (*GetAllAPITokensResponse, error)
+}
+
+// CreateAPITokenRequest Create api token request
+type CreateAPITokenRequest struct {
+	// Name token name
+	Name string `json:"name"`
+	// Token token value
+	Token string `json:"token"`
+	// Issuer service which issued the token
+	Issuer string `json:"issuer"`
+	// Scopes token scopes
+	Scopes []string `json:"scopes"`
+	// ExpiresAt token expiry time in RFC 3339 format, empty for tokens without expiry
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPITokenResponse Create api token response
+type CreateAPITokenResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateAPITokenRequest Update api token request
+type UpdateAPITokenRequest struct {
+	UUID      string     `json:"uuid"`
+	Name      *string    `json:"name"`
+	Token     *string    `json:"token"`
+	Issuer    *string    `json:"issuer"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateAPITokenResponse Update api token response
+type UpdateAPITokenResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteAPITokenRequest Delete api token request
+type DeleteAPITokenRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteAPITokenResponse Delete api token response
+type DeleteAPITokenResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllAPITokensRequest Get all api tokens request
+type GetAllAPITokensRequest struct {
+	// ExpiringWithinDays return only tokens expiring within N days (expired included), 0 returns all tokens
+	ExpiringWithinDays int `query:"expiring_within_days"`
+}
+
+// GetAllAPITokensResponse Get all api tokens response
+type GetAllAPITokensResponse struct {
+	Items []GetAllAPITokensResponseItem `json:"items"`
+}
+
+// GetAllAPITokensResponseItem Get all api tokens response item
+type GetAllAPITokensResponseItem struct {
+	UUID      string     `json:"uuid"`
+	Name      string     `json:"name"`
+	Token     string     `json:"token"`
+	Issuer    string     `json:"issuer"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	// Expired token is already expired
+	Expired bool `json:"expired"`
+}
+
+// APITokenHandler API token handler
+type APITokenHandler struct {
+	service      APITokenService
+	ctxConverter ctxConverter
+}
+
+// NewAPITokenHandler create new api token handler
+func NewAPITokenHandler(service APITokenService, ctxConverter ctxConverter) *APITokenHandler {
+	return &APITokenHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateAPIToken create api token
+// @Summary Create api token
+// @Description Create a new api token for the user
+// @Tags api-tokens
+// @Accept json
+// @Produce json
+// @Param token body CreateAPITokenRequest true "API token request body"
+// @Success 201 {object} CreateAPITokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens [post]
+func (h *APITokenHandler) CreateAPIToken(c echo.Context) error {
+	req := new(CreateAPITokenRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateAPIToken update api token
+// @Summary Update api token
+// @Description Update an existing api token for the user
+// @Tags api-tokens
+// @Accept json
+// @Produce json
+// @Param token body UpdateAPITokenRequest true "API token request body"
+// @Success 200 {object} UpdateAPITokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens [patch]
+func (h *APITokenHandler) UpdateAPIToken(c echo.Context) error {
+	req := new(UpdateAPITokenRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Update(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteAPIToken delete api token
+// @Summary Delete api token
+// @Description Delete an existing api token for the user
+// @Tags api-tokens
+// @Accept json
+// @Produce json
+// @Param token body DeleteAPITokenRequest true "API token request body"
+// @Success 200 {object} DeleteAPITokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens [delete]
+func (h *APITokenHandler) DeleteAPIToken(c echo.Context) error {
+	req := new(DeleteAPITokenRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllAPITokens get api tokens sorted by expiry
+// @Summary Get all api tokens
+// @Description Get api tokens for the user, soonest expiring first, tokens without expiry last
+// @Tags api-tokens
+// @Produce json
+// @Param expiring_within_days query int false "Only tokens expiring within N days"
+// @Success 200 {object} GetAllAPITokensResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens [get]
+func (h *APITokenHandler) GetAllAPITokens(c echo.Context) error {
+	req := new(GetAllAPITokensRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPITokenHandler_GetAllAPITokens(t *testing.T) {
+	mockService := new(mockAPITokenService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewAPITokenHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.GET("/tokens", handler.GetAllAPITokens)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	response := &GetAllAPITokensResponse{Items: []GetAllAPITokensResponseItem{
+		{UUID: uuid.NewString(), Name: "ci", Token: "ghp_123"},
+	}}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("GetAll", mock.Anything, GetAllAPITokensRequest{ExpiringWithinDays: 7}).Return(response, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.GET("/tokens").
+		WithQuery("expiring_within_days", 7).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("items").Array().Length().IsEqual(1)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
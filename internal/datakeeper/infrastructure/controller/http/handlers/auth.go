@@ -17,20 +17,41 @@ type AuthService interface {
 	SignUp(ctx context.Context, r RegisterRequest) (*RegisterResponse, error)
 }
 
+// SessionService access and refresh tokens of signed in user
+type SessionService interface {
+	// Refresh exchange refresh token for new tokens
+	Refresh(ctx context.Context, r RefreshRequest) (*RefreshResponse, error)
+	// Logout revoke session of refresh token
+	Logout(ctx context.Context, r LogoutRequest) (*LogoutResponse, error)
+}
+
+// SessionTokens tokens of session
+type SessionTokens struct {
+	// Token short-lived access token
+	Token string `json:"token"`
+	// ExpiresAt when access token expires
+	ExpiresAt time.Time `json:"expires_at"`
+	// RefreshToken one-time token to get new tokens
+	RefreshToken string `json:"refresh_token"`
+	// RefreshExpiresAt when refresh token expires
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
 // LoginRequest Login request
 type LoginRequest struct {
 	// Login user
 	Login string `json:"login"`
 	// User password
 	Password string `json:"password"`
-	// Key cypher
-	Key string `json:"key"`
+	// MasterPassword password to derive cypher key
+	MasterPassword string `json:"master_password"`
+	// Key cypher, only for accounts registered before master password
+	Key string `json:"key,omitempty"`
 }
 
 // LoginResponse Login response
 type LoginResponse struct {
-	// Token user's token
-	Token string `json:"token"`
+	SessionTokens
 }
 
 // RegisterRequest Register request
@@ -39,33 +60,60 @@ type RegisterRequest struct {
 	Login string `json:"login"`
 	// Password user's password
 	Password string `json:"password"`
+	// MasterPassword password to derive cypher key, lost one is recovered only with recovery kit
+	MasterPassword string `json:"master_password"`
 }
 
 // RegisterResponse Register response
 type RegisterResponse struct {
-	// Key user's generated key
-	Key string `json:"key"`
+	// Login registered user
+	Login string `json:"login"`
+}
+
+// RefreshRequest Refresh request
+type RefreshRequest struct {
+	// RefreshToken refresh token, taken from cookie if empty
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshResponse Refresh response
+type RefreshResponse struct {
+	SessionTokens
+}
+
+// LogoutRequest Logout request
+type LogoutRequest struct {
+	// RefreshToken refresh token, taken from cookie if empty
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutResponse Logout response
+type LogoutResponse struct {
+	// LoggedOut session is revoked
+	LoggedOut bool `json:"logged_out"`
 }
 
 // AuthHandler Auth handler
 type AuthHandler struct {
-	authService AuthService
+	authService    AuthService
+	sessionService SessionService
 }
 
 // NewAuthHandler Create new auth handler
-func NewAuthHandler(authService AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService AuthService, sessionService SessionService) *AuthHandler {
+	return &AuthHandler{authService: authService, sessionService: sessionService}
 }
 
 // Login Authentication
 // @Summary Login user
-// @Description Authenticate user and get token
+// @Description Authenticate user and get access and refresh tokens
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param login body LoginRequest true "Login request"
 // @Success 200 {object} LoginResponse
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
@@ -76,21 +124,17 @@ func (h *AuthHandler) Login(c echo.Context) error {
 
 	res, err := h.authService.SignIn(c.Request().Context(), *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
-	c.SetCookie(&http.Cookie{
-		Name:    "User",
-		Value:   res.Token,
-		Expires: time.Now().Add(24 * time.Hour),
-	})
+	setSessionCookies(c, res.SessionTokens)
 
 	return c.JSON(http.StatusOK, res)
 }
 
 // Register Registration
 // @Summary Register user
-// @Description Register new user and get token
+// @Description Register new user, cypher key is derived from master password
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -107,7 +151,74 @@ func (h *AuthHandler) Register(c echo.Context) error {
 
 	res, err := h.authService.SignUp(c.Request().Context(), *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// Refresh Refresh tokens
+// @Summary Refresh tokens
+// @Description Exchange refresh token for new access and refresh tokens, used refresh token is not valid anymore.
+// @Description Reuse of refresh token revokes whole session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param refresh body RefreshRequest false "Refresh token, cookie is used if empty"
+// @Success 200 {object} RefreshResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	req := new(RefreshRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken = refreshTokenCookie(c)
+	}
+
+	res, err := h.sessionService.Refresh(c.Request().Context(), *req)
+	if err != nil {
+		// rejected session is over, client signs in again
+		if statusCode(err) == http.StatusUnauthorized {
+			clearSessionCookies(c)
+		}
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	setSessionCookies(c, res.SessionTokens)
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// Logout Logout
+// @Summary Logout user
+// @Description Revoke session, its access and refresh tokens are rejected
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param logout body LogoutRequest false "Refresh token, cookie is used if empty"
+// @Success 200 {object} LogoutResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	req := new(LogoutRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken = refreshTokenCookie(c)
+	}
+
+	clearSessionCookies(c)
+
+	res, err := h.sessionService.Logout(c.Request().Context(), *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -5,17 +5,20 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/mock"
 )
 
-func setupServer(mockAuthService *mockAuthService) *echo.Echo {
+func setupServer(mockAuthService *mockAuthService, mockSessionService *mockSessionService) *echo.Echo {
 	e := echo.New()
-	handler := NewAuthHandler(mockAuthService)
+	handler := NewAuthHandler(mockAuthService, mockSessionService)
 
 	e.POST("/login", handler.Login)
 	e.POST("/register", handler.Register)
+	e.POST("/refresh", handler.Refresh)
+	e.POST("/logout", handler.Logout)
 
 	return e
 }
@@ -27,11 +30,11 @@ func TestAuthHandler_Login(t *testing.T) {
 		Password: "password",
 		Key:      "key",
 	}
-	loginResponse := &LoginResponse{Token: "token"}
+	loginResponse := &LoginResponse{SessionTokens{Token: "token", RefreshToken: "refresh"}}
 
 	mockAuthService.On("SignIn", mock.Anything, loginRequest).Return(loginResponse, nil)
 
-	e := setupServer(mockAuthService)
+	e := setupServer(mockAuthService, new(mockSessionService))
 
 	server := httptest.NewServer(e)
 	defer server.Close()
@@ -45,27 +48,87 @@ func TestAuthHandler_Login(t *testing.T) {
 		"key":      loginRequest.Key,
 	}
 
-	// Perform request
-	expect.POST("/login").
+	// Perform request, browser gets cookie and client without cookies sends token from body as Bearer token
+	response := expect.POST("/login").
 		WithJSON(reqData).
 		Expect().
-		Status(http.StatusOK).
-		Cookie("User").Value().IsEqual("token")
+		Status(http.StatusOK)
+	response.Cookie("User").Value().IsEqual("token")
+	response.JSON().Object().HasValue("token", "token")
 
 	mockAuthService.AssertExpectations(t)
 }
 
+func TestAuthHandler_Refresh(t *testing.T) {
+	mockSessionService := new(mockSessionService)
+	refreshResponse := &RefreshResponse{SessionTokens{Token: "new_token", RefreshToken: "new_refresh"}}
+
+	mockSessionService.On("Refresh", mock.Anything, RefreshRequest{RefreshToken: "refresh"}).Return(refreshResponse, nil)
+	mockSessionService.On("Refresh", mock.Anything, RefreshRequest{RefreshToken: "used"}).
+		Return((*RefreshResponse)(nil), customerr.ErrorWithCode(customerr.REFRESH_TOKEN_REUSED, http.StatusUnauthorized))
+
+	e := setupServer(new(mockAuthService), mockSessionService)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	// token from cookie
+	response := expect.POST("/refresh").
+		WithCookie("Refresh", "refresh").
+		Expect().
+		Status(http.StatusOK)
+	response.Cookie("User").Value().IsEqual("new_token")
+	response.Cookie("Refresh").Value().IsEqual("new_refresh")
+	response.JSON().Object().HasValue("refresh_token", "new_refresh")
+
+	// token from body
+	expect.POST("/refresh").
+		WithJSON(RefreshRequest{RefreshToken: "used"}).
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().
+		HasValue("message", customerr.REFRESH_TOKEN_REUSED)
+
+	mockSessionService.AssertExpectations(t)
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	mockSessionService := new(mockSessionService)
+
+	mockSessionService.On("Logout", mock.Anything, LogoutRequest{RefreshToken: "refresh"}).Return(&LogoutResponse{LoggedOut: true}, nil)
+
+	e := setupServer(new(mockAuthService), mockSessionService)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	response := expect.POST("/logout").
+		WithCookie("Refresh", "refresh").
+		Expect().
+		Status(http.StatusOK)
+	response.Cookie("User").Value().IsEmpty()
+	response.Cookie("Refresh").Value().IsEmpty()
+	response.JSON().Object().HasValue("logged_out", true)
+
+	mockSessionService.AssertExpectations(t)
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	mockAuthService := new(mockAuthService)
 	registerRequest := RegisterRequest{
-		Login:    "test@example.com",
-		Password: "password",
+		Login:          "test@example.com",
+		Password:       "password",
+		MasterPassword: "master_password",
 	}
-	registerResponse := &RegisterResponse{Key: "key"}
+	registerResponse := &RegisterResponse{Login: "test@example.com"}
 
 	mockAuthService.On("SignUp", mock.Anything, registerRequest).Return(registerResponse, nil)
 
-	e := setupServer(mockAuthService)
+	e := setupServer(mockAuthService, new(mockSessionService))
 
 	server := httptest.NewServer(e)
 	defer server.Close()
@@ -74,8 +137,9 @@ func TestAuthHandler_Register(t *testing.T) {
 
 	// Prepare request data
 	reqData := map[string]interface{}{
-		"login":    registerRequest.Login,
-		"password": registerRequest.Password,
+		"login":           registerRequest.Login,
+		"password":        registerRequest.Password,
+		"master_password": registerRequest.MasterPassword,
 	}
 
 	// Perform request
@@ -86,3 +150,31 @@ func TestAuthHandler_Register(t *testing.T) {
 
 	mockAuthService.AssertExpectations(t)
 }
+
+func TestAuthHandler_LoginInvalidMasterPassword(t *testing.T) {
+	mockAuthService := new(mockAuthService)
+	loginRequest := LoginRequest{
+		Login:          "test@example.com",
+		Password:       "password",
+		MasterPassword: "wrong_password",
+	}
+
+	mockAuthService.On("SignIn", mock.Anything, loginRequest).
+		Return((*LoginResponse)(nil), customerr.ErrorWithCode(customerr.INVALID_MASTER_PASSWORD, http.StatusUnauthorized))
+
+	e := setupServer(mockAuthService, new(mockSessionService))
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/login").
+		WithJSON(loginRequest).
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().
+		HasValue("message", customerr.INVALID_MASTER_PASSWORD)
+
+	mockAuthService.AssertExpectations(t)
+}
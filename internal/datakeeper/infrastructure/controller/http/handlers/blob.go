@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// BlobService store blobs encrypted by client (zero-knowledge mode)
+type BlobService interface {
+	// Create save blob
+	Create(ctx context.Context, r CreateBlobRequest) (*CreateBlobResponse, error)
+	// Update replace blob
+	Update(ctx context.Context, r UpdateBlobRequest) (*UpdateBlobResponse, error)
+	// Delete delete blob
+	Delete(ctx context.Context, r DeleteBlobRequest) (*DeleteBlobResponse, error)
+	// GetAll get all blobs for user
+	GetAll(ctx context.Context, r GetAllBlobsRequest) (*GetAllBlobsResponse, error)
+}
+
+// CreateBlobRequest Create blob request
+type CreateBlobRequest struct {
+	// UUID chosen by client, generated by server if empty
+	UUID string `json:"uuid,omitempty"`
+	// Content ciphertext encrypted by client, base64 encoded
+	Content []byte `json:"content"`
+	// Metadata client-supplied JSON, not encrypted
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// UpdateBlobRequest Update blob request, content and metadata are replaced
+type UpdateBlobRequest struct {
+	UUID     string          `json:"uuid"`
+	Content  []byte          `json:"content"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// DeleteBlobRequest Delete blob request
+type DeleteBlobRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllBlobsRequest Get all blobs request
+type GetAllBlobsRequest struct{}
+
+// CreateBlobResponse Create blob response
+type CreateBlobResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateBlobResponse Update blob response
+type UpdateBlobResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteBlobResponse Delete blob response
+type DeleteBlobResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllBlobsResponse Get all blobs response
+type GetAllBlobsResponse struct {
+	Items []GetAllBlobsResponseItem `json:"items"`
+}
+
+// GetAllBlobsResponseItem Get all blobs response item
+type GetAllBlobsResponseItem struct {
+	UUID     string          `json:"uuid"`
+	Content  []byte          `json:"content"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// BlobHandler Blob handler
+type BlobHandler struct {
+	service      BlobService
+	ctxConverter ctxConverter
+}
+
+// NewBlobHandler create new blob handler
+func NewBlobHandler(service BlobService, ctxConverter ctxConverter) *BlobHandler {
+	return &BlobHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateBlob create a new blob
+// @Summary Create a new blob
+// @Description Store a blob encrypted by client, server never decrypts it
+// @Tags blobs
+// @Accept json
+// @Produce json
+// @Param blob body CreateBlobRequest true "Blob request body"
+// @Success 201 {object} CreateBlobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/blobs [post]
+func (h *BlobHandler) CreateBlob(c echo.Context) error {
+	req := new(CreateBlobRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateBlob replace an existing blob
+// @Summary Replace an existing blob
+// @Description Replace content and metadata of a blob encrypted by client
+// @Tags blobs
+// @Accept json
+// @Produce json
+// @Param blob body UpdateBlobRequest true "Blob request body"
+// @Success 200 {object} UpdateBlobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/blobs [patch]
+func (h *BlobHandler) UpdateBlob(c echo.Context) error {
+	req := new(UpdateBlobRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Update(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteBlob delete an existing blob
+// @Summary Delete an existing blob
+// @Description Delete a blob encrypted by client
+// @Tags blobs
+// @Accept json
+// @Produce json
+// @Param blob body DeleteBlobRequest true "Blob request body"
+// @Success 200 {object} DeleteBlobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/blobs [delete]
+func (h *BlobHandler) DeleteBlob(c echo.Context) error {
+	req := new(DeleteBlobRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllBlobs get all blobs for user
+// @Summary Get all blobs
+// @Description Get all blobs encrypted by client for the user
+// @Tags blobs
+// @Produce json
+// @Success 200 {object} GetAllBlobsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/blobs [get]
+func (h *BlobHandler) GetAllBlobs(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllBlobsRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
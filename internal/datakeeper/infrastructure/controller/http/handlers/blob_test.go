@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupBlobServer(mockService *mockBlobService, mockConverter *mockCtxConverter) *httptest.Server {
+	handler := NewBlobHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/blobs", handler.CreateBlob)
+	e.PATCH("/blobs", handler.UpdateBlob)
+	e.GET("/blobs", handler.GetAllBlobs)
+	e.DELETE("/blobs", handler.DeleteBlob)
+
+	return httptest.NewServer(e)
+}
+
+func TestBlobHandler_CreateBlob(t *testing.T) {
+	mockService := new(mockBlobService)
+	mockConverter := new(mockCtxConverter)
+	server := setupBlobServer(mockService, mockConverter)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	content := []byte{0x44, 0x4b, 0x43, 0x01, 0xff}
+	metadata := json.RawMessage(`{"kind":"login"}`)
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Create", mock.Anything, CreateBlobRequest{UUID: uuidStr, Content: content, Metadata: metadata}).
+		Return(&CreateBlobResponse{UUID: uuidStr}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	// content is sent as base64, metadata as JSON
+	expect.POST("/blobs").
+		WithJSON(map[string]interface{}{
+			"uuid":     uuidStr,
+			"content":  base64.StdEncoding.EncodeToString(content),
+			"metadata": metadata,
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		HasValue("uuid", uuidStr)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBlobHandler_UpdateBlob_NotFound(t *testing.T) {
+	mockService := new(mockBlobService)
+	mockConverter := new(mockCtxConverter)
+	server := setupBlobServer(mockService, mockConverter)
+	defer server.Close()
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Update", mock.Anything, mock.Anything).
+		Return((*UpdateBlobResponse)(nil), customerr.ErrorWithCode(customerr.BLOB_NOT_FOUND, http.StatusNotFound))
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.PATCH("/blobs").
+		WithJSON(map[string]interface{}{"uuid": uuid.NewString(), "content": "AQ=="}).
+		Expect().
+		Status(http.StatusNotFound).
+		JSON().Object().
+		HasValue("message", customerr.BLOB_NOT_FOUND)
+}
+
+func TestBlobHandler_GetAllBlobs(t *testing.T) {
+	mockService := new(mockBlobService)
+	mockConverter := new(mockCtxConverter)
+	server := setupBlobServer(mockService, mockConverter)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("GetAll", mock.Anything, GetAllBlobsRequest{}).Return(&GetAllBlobsResponse{
+		Items: []GetAllBlobsResponseItem{
+			{UUID: uuidStr, Content: []byte{0x01, 0x02}, Metadata: json.RawMessage(`{"kind":"note"}`)},
+		},
+	}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	item := expect.GET("/blobs").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("items").Array().Value(0).Object()
+	item.HasValue("uuid", uuidStr)
+	item.HasValue("content", "AQI=")
+	item.Value("metadata").Object().HasValue("kind", "note")
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// CardService store bank cards for user
+type CardService interface {
+	// Create save card
+	Create(ctx context.Context, r CreateCardRequest) (*CreateCardResponse, error)
+	// Update update card
+	Update(ctx context.Context, r UpdateCardRequest) (*UpdateCardResponse, error)
+	// Delete delete card
+	Delete(ctx context.Context, r DeleteCardRequest) (*DeleteCardResponse, error)
+	// GetAll get all cards for user
+	GetAll(ctx context.Context, r GetAllCardsRequest) (*GetAllCardsResponse, error)
+}
+
+// CreateCardRequest Create card request
+type CreateCardRequest struct {
+	// Name card name
+	Name string `json:"name"`
+	// Number card number
+	Number string `json:"number"`
+	// Holder card holder
+	Holder string `json:"holder"`
+	// Expiry expiry date in MM/YY format
+	Expiry string `json:"expiry"`
+	// CVV card verification value
+	CVV string `json:"cvv"`
+}
+
+// UpdateCardRequest Update card request
+type UpdateCardRequest struct {
+	UUID   string  `json:"uuid"`
+	Name   *string `json:"name"`
+	Number *string `json:"number"`
+	Holder *string `json:"holder"`
+	Expiry *string `json:"expiry"`
+	CVV    *string `json:"cvv"`
+}
+
+// DeleteCardRequest Delete card request
+type DeleteCardRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllCardsRequest Get all cards request
+type GetAllCardsRequest struct{}
+
+// CreateCardResponse Create card response
+type CreateCardResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateCardResponse Update card response
+type UpdateCardResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteCardResponse Delete card response
+type DeleteCardResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllCardsResponse Get all cards response
+type GetAllCardsResponse struct {
+	Items []GetAllCardsResponseItem `json:"items"`
+}
+
+// GetAllCardsResponseItem Get all cards response item
+type GetAllCardsResponseItem struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+	// Number masked card number
+	Number string `json:"number"`
+	Brand  string `json:"brand"`
+	Holder string `json:"holder"`
+	Expiry string `json:"expiry"`
+}
+
+// CardHandler Card handler
+type CardHandler struct {
+	service      CardService
+	ctxConverter ctxConverter
+}
+
+// NewCardHandler create new card handler
+func NewCardHandler(service CardService, ctxConverter ctxConverter) *CardHandler {
+	return &CardHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateCard create a new card
+// @Summary Create a new card
+// @Description Create a new bank card for the user
+// @Tags cards
+// @Accept json
+// @Produce json
+// @Param card body CreateCardRequest true "Card request body"
+// @Success 201 {object} CreateCardResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/cards [post]
+func (h *CardHandler) CreateCard(c echo.Context) error {
+	req := new(CreateCardRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateCard update an existing card
+// @Summary Update an existing card
+// @Description Update an existing bank card for the user
+// @Tags cards
+// @Accept json
+// @Produce json
+// @Param card body UpdateCardRequest true "Card request body"
+// @Success 200 {object} UpdateCardResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/cards [patch]
+func (h *CardHandler) UpdateCard(c echo.Context) error {
+	req := new(UpdateCardRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Update(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteCard delete an existing card
+// @Summary Delete an existing card
+// @Description Delete an existing bank card for the user
+// @Tags cards
+// @Accept json
+// @Produce json
+// @Param card body DeleteCardRequest true "Card request body"
+// @Success 200 {object} DeleteCardResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/cards [delete]
+func (h *CardHandler) DeleteCard(c echo.Context) error {
+	req := new(DeleteCardRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllCards get all cards for user
+// @Summary Get all cards
+// @Description Get all bank cards for the user with masked numbers
+// @Tags cards
+// @Produce json
+// @Success 200 {object} GetAllCardsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/cards [get]
+func (h *CardHandler) GetAllCards(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllCardsRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
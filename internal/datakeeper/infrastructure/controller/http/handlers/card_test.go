@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCardServer(mockCardService *mockCardService, mockConverter *mockCtxConverter) *echo.Echo {
+	e := echo.New()
+
+	handler := NewCardHandler(mockCardService, mockConverter)
+
+	e.POST("/cards", handler.CreateCard)
+	e.PATCH("/cards", handler.UpdateCard)
+	e.DELETE("/cards", handler.DeleteCard)
+	e.GET("/cards", handler.GetAllCards)
+
+	return e
+}
+
+func TestCardHandler_CreateCard(t *testing.T) {
+	mockCardService := new(mockCardService)
+	mockConverter := new(mockCtxConverter)
+	uuidStr := uuid.NewString()
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockCardService.On("Create", mock.Anything, mock.Anything).Return(&CreateCardResponse{UUID: uuidStr}, nil)
+
+	server := httptest.NewServer(setupCardServer(mockCardService, mockConverter))
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/cards").
+		WithJSON(map[string]string{
+			"name":   "corporate",
+			"number": "4111111111111111",
+			"holder": "IVAN IVANOV",
+			"expiry": "12/30",
+			"cvv":    "123",
+		}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		HasValue("uuid", uuidStr)
+
+	mockCardService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
+
+func TestCardHandler_CreateCard_InvalidNumber(t *testing.T) {
+	mockCardService := new(mockCardService)
+	mockConverter := new(mockCtxConverter)
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockCardService.On("Create", mock.Anything, mock.Anything).
+		Return((*CreateCardResponse)(nil), customerr.ErrorWithCode(customerr.INVALID_CARD_NUMBER, http.StatusBadRequest))
+
+	server := httptest.NewServer(setupCardServer(mockCardService, mockConverter))
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/cards").
+		WithJSON(map[string]string{"number": "4111111111111112"}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().
+		HasValue("message", customerr.INVALID_CARD_NUMBER)
+
+	mockCardService.AssertExpectations(t)
+}
+
+func TestCardHandler_GetAllCards(t *testing.T) {
+	mockCardService := new(mockCardService)
+	mockConverter := new(mockCtxConverter)
+	getAllCardsResponse := &GetAllCardsResponse{
+		Items: []GetAllCardsResponseItem{
+			{
+				UUID:   "123",
+				Name:   "corporate",
+				Number: "**** **** **** 1111",
+				Brand:  "VISA",
+				Holder: "IVAN IVANOV",
+				Expiry: "12/30",
+			},
+		},
+	}
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockCardService.On("GetAll", mock.Anything, mock.Anything).Return(getAllCardsResponse, nil)
+
+	server := httptest.NewServer(setupCardServer(mockCardService, mockConverter))
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.GET("/cards").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().Value("items").Array().Value(0).Object().
+		HasValue("number", "**** **** **** 1111")
+
+	mockCardService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
-	"mime/multipart"
+	"errors"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strings"
 
 	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
@@ -22,11 +24,15 @@ type FileService interface {
 	DownloadFile(ctx context.Context, r DownloadFileRequest) (*DownloadFileResponse, error)
 }
 
+// maxFileSize max size of uploaded request body
+const maxFileSize = 1024 * 1024 * 1024
+
 // UploadFileRequest Upload file request
 type UploadFileRequest struct {
 	Name   string
 	Format string
-	File   []byte
+	// File content, read by service while it is uploaded
+	File io.Reader
 }
 
 // UploadFileResponse Upload file response
@@ -69,7 +75,9 @@ type DownloadFileRequest struct {
 type DownloadFileResponse struct {
 	Name   string
 	Format string
-	File   []byte
+	Size   int
+	// File content, decrypted while it is read
+	File io.Reader
 }
 
 // FileHandler File handler
@@ -95,41 +103,45 @@ func NewFileHandler(fileService FileService, ctxConverter ctxConverter) *FileHan
 // @Failure 500 {object} map[string]string
 // @Router /files/upload [post]
 func (h *FileHandler) UploadFile(c echo.Context) error {
-	file, err := c.FormFile("file")
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
-	}
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxFileSize)
 
-	src, err := file.Open()
+	// file part is streamed to service without buffering whole file
+	reader, err := c.Request().MultipartReader()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
 	}
-	defer func(src multipart.File) {
-		err := src.Close()
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return c.JSON(http.StatusBadRequest, customerr.ToJson(http.ErrMissingFile.Error()))
+		}
 		if err != nil {
+			return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
 		}
-	}(src)
-
-	if file.Size > 5*1024*1024 {
-		return c.JSON(http.StatusBadRequest, customerr.ToJson("File is too large"))
-	}
-
-	buf := make([]byte, file.Size)
-	_, err = src.Read(buf)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		if part.FormName() == "file" {
+			defer part.Close()
+			return h.uploadFile(c, part.FileName(), part)
+		}
+		_ = part.Close()
 	}
+}
 
+func (h *FileHandler) uploadFile(c echo.Context, filename string, file io.Reader) error {
 	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
 	}
 
-	strs := strings.Split(file.Filename, ".")
-	req := UploadFileRequest{Name: strs[0], Format: strs[1], File: buf}
+	// format is empty for file without extension
+	ext := filepath.Ext(filename)
+	req := UploadFileRequest{Name: strings.TrimSuffix(filename, ext), Format: strings.TrimPrefix(ext, "."), File: file}
 	res, err := h.fileService.UploadFile(ctx, req)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson("File is too large"))
+	}
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusCreated, res)
@@ -159,7 +171,7 @@ func (h *FileHandler) DeleteFile(c echo.Context) error {
 
 	res, err := h.fileService.DeleteFile(ctx, *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -182,7 +194,7 @@ func (h *FileHandler) GetAllFiles(c echo.Context) error {
 
 	res, err := h.fileService.GetAllFiles(ctx, GetAllFilesRequest{})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -211,8 +223,8 @@ func (h *FileHandler) DownloadFile(c echo.Context) error {
 
 	res, err := h.fileService.DownloadFile(ctx, *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
-	return c.Blob(http.StatusOK, "application/octet-stream", res.File)
+	return c.Stream(http.StatusOK, "application/octet-stream", res.File)
 }
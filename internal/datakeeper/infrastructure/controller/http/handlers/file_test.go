@@ -3,10 +3,13 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gavv/httpexpect/v2"
@@ -34,7 +37,12 @@ func TestFileHandler_UploadFile(t *testing.T) {
 	uploadResponse := &UploadFileResponse{UUID: uuid.New().String()}
 
 	mockCtxConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(context.TODO(), nil)
-	mockFileService.On("UploadFile", mock.Anything, mock.Anything).Return(uploadResponse, nil)
+	var uploaded UploadFileRequest
+	var content []byte
+	mockFileService.On("UploadFile", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		uploaded = args.Get(1).(UploadFileRequest)
+		content, _ = io.ReadAll(uploaded.File)
+	}).Return(uploadResponse, nil)
 
 	e := setupFileServer(mockFileService, mockCtxConverter)
 
@@ -59,10 +67,81 @@ func TestFileHandler_UploadFile(t *testing.T) {
 		Status(http.StatusCreated).
 		JSON().Object().ContainsKey("uuid")
 
+	assert.Equal(t, "test", uploaded.Name)
+	assert.Equal(t, "txt", uploaded.Format)
+	assert.Equal(t, "test content", string(content))
+
 	mockFileService.AssertExpectations(t)
 	mockCtxConverter.AssertExpectations(t)
 }
 
+func TestFileHandler_UploadFileWithoutExtension(t *testing.T) {
+	mockFileService := new(mockFileService)
+	mockCtxConverter := new(mockCtxConverter)
+	uploadResponse := &UploadFileResponse{UUID: uuid.New().String()}
+
+	mockCtxConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(context.TODO(), nil)
+	var uploaded UploadFileRequest
+	mockFileService.On("UploadFile", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		uploaded = args.Get(1).(UploadFileRequest)
+	}).Return(uploadResponse, nil)
+
+	e := setupFileServer(mockFileService, mockCtxConverter)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	fw, err := w.CreateFormFile("file", "Makefile")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("test content"))
+	require.NoError(t, err)
+	err = w.Close()
+	require.NoError(t, err)
+
+	expect.POST("/files").
+		WithHeader("Content-Type", w.FormDataContentType()).
+		WithBytes(b.Bytes()).
+		Expect().
+		Status(http.StatusCreated)
+
+	assert.Equal(t, "Makefile", uploaded.Name)
+	assert.Empty(t, uploaded.Format)
+
+	mockFileService.AssertExpectations(t)
+	mockCtxConverter.AssertExpectations(t)
+}
+
+func TestFileHandler_UploadFileWithoutFile(t *testing.T) {
+	mockFileService := new(mockFileService)
+	mockCtxConverter := new(mockCtxConverter)
+
+	e := setupFileServer(mockFileService, mockCtxConverter)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	expect := httpexpect.Default(t, server.URL)
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	err := w.WriteField("name", "test")
+	require.NoError(t, err)
+	err = w.Close()
+	require.NoError(t, err)
+
+	expect.POST("/files").
+		WithHeader("Content-Type", w.FormDataContentType()).
+		WithBytes(b.Bytes()).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	mockFileService.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything)
+}
+
 func TestFileHandler_DeleteFile(t *testing.T) {
 	mockFileService := new(mockFileService)
 	mockCtxConverter := new(mockCtxConverter)
@@ -123,7 +202,7 @@ func TestFileHandler_GetAllFiles(t *testing.T) {
 func TestFileHandler_DownloadFile(t *testing.T) {
 	mockFileService := new(mockFileService)
 	mockCtxConverter := new(mockCtxConverter)
-	downloadResponse := &DownloadFileResponse{File: []byte("test content")}
+	downloadResponse := &DownloadFileResponse{File: strings.NewReader(strings.Repeat("test content", 10000))}
 
 	mockCtxConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
 	mockFileService.On("DownloadFile", mock.Anything, mock.Anything).Return(downloadResponse, nil)
@@ -141,7 +220,7 @@ func TestFileHandler_DownloadFile(t *testing.T) {
 		Expect().
 		Status(http.StatusOK).
 		HasContentType("application/octet-stream").
-		Body().IsEqual("test content")
+		Body().IsEqual(strings.Repeat("test content", 10000))
 
 	mockFileService.AssertExpectations(t)
 	mockCtxConverter.AssertExpectations(t)
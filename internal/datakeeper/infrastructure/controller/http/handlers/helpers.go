@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/labstack/echo/v4"
 )
 
@@ -28,3 +31,54 @@ func (c *CtxConverterImpl) ConvertEchoCtxToCtx(ctx echo.Context) (context.Contex
 	}
 	return context.WithValue(context.Background(), "User", user), nil
 }
+
+// statusCode get http status from custom error, 500 by default
+func statusCode(err error) int {
+	var customErr *customerr.CustomError
+	if errors.As(err, &customErr) && customErr.Code != 0 {
+		return customErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+const (
+	// accessCookie cookie with access token
+	accessCookie = "User"
+	// refreshCookie cookie with refresh token, it is sent only to auth endpoints
+	refreshCookie = "Refresh"
+	// refreshCookiePath path of auth endpoints
+	refreshCookiePath = "/api/auth"
+)
+
+// setSessionCookies set cookies with tokens of signed in user
+func setSessionCookies(c echo.Context, tokens SessionTokens) {
+	c.SetCookie(&http.Cookie{
+		Name:    accessCookie,
+		Value:   tokens.Token,
+		Path:    "/",
+		Expires: tokens.ExpiresAt,
+	})
+	c.SetCookie(&http.Cookie{
+		Name:     refreshCookie,
+		Value:    tokens.RefreshToken,
+		Path:     refreshCookiePath,
+		Expires:  tokens.RefreshExpiresAt,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// clearSessionCookies remove cookies with tokens
+func clearSessionCookies(c echo.Context) {
+	c.SetCookie(&http.Cookie{Name: accessCookie, Path: "/", MaxAge: -1})
+	c.SetCookie(&http.Cookie{Name: refreshCookie, Path: refreshCookiePath, MaxAge: -1, HttpOnly: true})
+}
+
+// refreshTokenCookie refresh token from cookie, empty if there is no cookie
+func refreshTokenCookie(c echo.Context) string {
+	cookie, err := c.Cookie(refreshCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
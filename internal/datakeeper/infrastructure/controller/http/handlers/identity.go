@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// IdentityService store identity documents for user
+type IdentityService interface {
+	// Create save identity document
+	Create(ctx context.Context, r CreateIdentityRequest) (*CreateIdentityResponse, error)
+	// Update update identity document
+	Update(ctx context.Context, r UpdateIdentityRequest) (*UpdateIdentityResponse, error)
+	// Delete delete identity document with attached scans
+	Delete(ctx context.Context, r DeleteIdentityRequest) (*DeleteIdentityResponse, error)
+	// GetAll get all identity documents for user
+	GetAll(ctx context.Context, r GetAllIdentitiesRequest) (*GetAllIdentitiesResponse, error)
+}
+
+// CreateIdentityRequest Create identity document request
+type CreateIdentityRequest struct {
+	// Kind passport, id_card or driver_license
+	Kind string `json:"kind"`
+	// Number document number
+	Number string `json:"number"`
+	// IssuingCountry ISO 3166-1 alpha-2 country code
+	IssuingCountry string `json:"issuing_country"`
+	// IssueDate issue date in YYYY-MM-DD format
+	IssueDate string `json:"issue_date"`
+	// ExpiryDate expiry date in YYYY-MM-DD format, empty for documents without expiry
+	ExpiryDate string `json:"expiry_date"`
+	// FullName full name of document holder
+	FullName string `json:"full_name"`
+	// Scans uuids of uploaded files with document scans
+	Scans []string `json:"scans"`
+}
+
+// CreateIdentityResponse Create identity document response
+type CreateIdentityResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateIdentityRequest Update identity document request, scans are replaced as a whole
+type UpdateIdentityRequest struct {
+	UUID           string   `json:"uuid"`
+	Kind           *string  `json:"kind"`
+	Number         *string  `json:"number"`
+	IssuingCountry *string  `json:"issuing_country"`
+	IssueDate      *string  `json:"issue_date"`
+	ExpiryDate     *string  `json:"expiry_date"`
+	FullName       *string  `json:"full_name"`
+	Scans          []string `json:"scans"`
+}
+
+// UpdateIdentityResponse Update identity document response
+type UpdateIdentityResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteIdentityRequest Delete identity document request
+type DeleteIdentityRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteIdentityResponse Delete identity document response
+type DeleteIdentityResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllIdentitiesRequest Get all identity documents request
+type GetAllIdentitiesRequest struct{}
+
+// GetAllIdentitiesResponse Get all identity documents response
+type GetAllIdentitiesResponse struct {
+	Items []GetAllIdentitiesResponseItem `json:"items"`
+}
+
+// GetAllIdentitiesResponseItem Get all identity documents response item
+type GetAllIdentitiesResponseItem struct {
+	UUID           string   `json:"uuid"`
+	Kind           string   `json:"kind"`
+	Number         string   `json:"number"`
+	IssuingCountry string   `json:"issuing_country"`
+	IssueDate      string   `json:"issue_date"`
+	ExpiryDate     string   `json:"expiry_date"`
+	FullName       string   `json:"full_name"`
+	Scans          []string `json:"scans"`
+}
+
+// IdentityHandler Identity document handler
+type IdentityHandler struct {
+	service      IdentityService
+	ctxConverter ctxConverter
+}
+
+// NewIdentityHandler create new identity document handler
+func NewIdentityHandler(service IdentityService, ctxConverter ctxConverter) *IdentityHandler {
+	return &IdentityHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateIdentity create identity document
+// @Summary Create identity document
+// @Description Create a new identity document for the user, scans must be uploaded as files first
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param identity body CreateIdentityRequest true "Identity request body"
+// @Success 201 {object} CreateIdentityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/identities [post]
+func (h *IdentityHandler) CreateIdentity(c echo.Context) error {
+	req := new(CreateIdentityRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateIdentity update identity document
+// @Summary Update identity document
+// @Description Update an existing identity document for the user
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param identity body UpdateIdentityRequest true "Identity request body"
+// @Success 200 {object} UpdateIdentityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/identities [patch]
+func (h *IdentityHandler) UpdateIdentity(c echo.Context) error {
+	req := new(UpdateIdentityRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Update(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteIdentity delete identity document
+// @Summary Delete identity document
+// @Description Delete an existing identity document with its attached scans
+// @Tags identities
+// @Accept json
+// @Produce json
+// @Param identity body DeleteIdentityRequest true "Identity request body"
+// @Success 200 {object} DeleteIdentityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/identities [delete]
+func (h *IdentityHandler) DeleteIdentity(c echo.Context) error {
+	req := new(DeleteIdentityRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllIdentities get all identity documents for user
+// @Summary Get all identity documents
+// @Description Get all identity documents for the user
+// @Tags identities
+// @Produce json
+// @Success 200 {object} GetAllIdentitiesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/identities [get]
+func (h *IdentityHandler) GetAllIdentities(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllIdentitiesRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
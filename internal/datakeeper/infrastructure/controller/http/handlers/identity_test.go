@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdentityHandler_DeleteIdentity(t *testing.T) {
+	mockService := new(mockIdentityService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewIdentityHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.DELETE("/identities", handler.DeleteIdentity)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Delete", mock.Anything, DeleteIdentityRequest{UUID: uuidStr}).Return(&DeleteIdentityResponse{UUID: uuidStr}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.DELETE("/identities").
+		WithJSON(map[string]string{"uuid": uuidStr}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("uuid", uuidStr)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
@@ -99,7 +99,7 @@ func (h *LogPassHandler) CreateLogPass(c echo.Context) error {
 
 	res, err := h.service.Create(ctx, *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusCreated, res)
@@ -130,7 +130,7 @@ func (h *LogPassHandler) UpdateLogPass(c echo.Context) error {
 
 	res, err := h.service.Update(ctx, *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -160,7 +160,7 @@ func (h *LogPassHandler) DeleteLogPass(c echo.Context) error {
 
 	res, err := h.service.Delete(ctx, *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, res)
@@ -185,7 +185,7 @@ func (h *LogPassHandler) GetAllLogPasses(c echo.Context) error {
 
 	res, err := h.service.GetAll(ctx, *req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, res)
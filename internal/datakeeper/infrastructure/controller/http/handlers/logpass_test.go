@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -46,3 +47,30 @@ func TestCreateLogPass(t *testing.T) {
 	mockService.AssertExpectations(t)
 	mockConverter.AssertExpectations(t)
 }
+
+func TestGetAllLogPassesVaultLocked(t *testing.T) {
+	mockService := new(mockLogPassService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewLogPassHandler(mockService, mockConverter)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := handler.GetAllLogPasses(echo.New().NewContext(r, w))
+		if err != nil {
+			return
+		}
+	}))
+	defer server.Close()
+
+	e := httpexpect.Default(t, server.URL)
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("GetAll", mock.Anything, mock.Anything).
+		Return((*GetAllLogPassesResponse)(nil), customerr.ErrorWithCode(customerr.VAULT_LOCKED, http.StatusLocked))
+
+	e.GET("/logpass").
+		Expect().
+		Status(http.StatusLocked).
+		JSON().Object().
+		HasValue("message", customerr.VAULT_LOCKED)
+
+	mockService.AssertExpectations(t)
+}
@@ -80,3 +80,329 @@ func (m *mockAuthService) SignUp(ctx context.Context, r RegisterRequest) (*Regis
 	args := m.Called(ctx, r)
 	return args.Get(0).(*RegisterResponse), args.Error(1)
 }
+
+type mockSessionService struct {
+	mock.Mock
+}
+
+func (m *mockSessionService) Refresh(ctx context.Context, r RefreshRequest) (*RefreshResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*RefreshResponse), args.Error(1)
+}
+
+func (m *mockSessionService) Logout(ctx context.Context, r LogoutRequest) (*LogoutResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*LogoutResponse), args.Error(1)
+}
+
+type mockCardService struct {
+	mock.Mock
+}
+
+func (m *mockCardService) Create(ctx context.Context, r CreateCardRequest) (*CreateCardResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateCardResponse), args.Error(1)
+}
+
+func (m *mockCardService) Update(ctx context.Context, r UpdateCardRequest) (*UpdateCardResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateCardResponse), args.Error(1)
+}
+
+func (m *mockCardService) Delete(ctx context.Context, r DeleteCardRequest) (*DeleteCardResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteCardResponse), args.Error(1)
+}
+
+func (m *mockCardService) GetAll(ctx context.Context, r GetAllCardsRequest) (*GetAllCardsResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllCardsResponse), args.Error(1)
+}
+
+type mockNoteService struct {
+	mock.Mock
+}
+
+func (m *mockNoteService) Create(ctx context.Context, r CreateNoteRequest) (*CreateNoteResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateNoteResponse), args.Error(1)
+}
+
+func (m *mockNoteService) Update(ctx context.Context, r UpdateNoteRequest) (*UpdateNoteResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateNoteResponse), args.Error(1)
+}
+
+func (m *mockNoteService) Delete(ctx context.Context, r DeleteNoteRequest) (*DeleteNoteResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteNoteResponse), args.Error(1)
+}
+
+func (m *mockNoteService) GetAll(ctx context.Context, r GetAllNotesRequest) (*GetAllNotesResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllNotesResponse), args.Error(1)
+}
+
+type mockOTPService struct {
+	mock.Mock
+}
+
+func (m *mockOTPService) Create(ctx context.Context, r CreateOTPRequest) (*CreateOTPResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateOTPResponse), args.Error(1)
+}
+
+func (m *mockOTPService) Delete(ctx context.Context, r DeleteOTPRequest) (*DeleteOTPResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteOTPResponse), args.Error(1)
+}
+
+func (m *mockOTPService) GetAll(ctx context.Context, r GetAllOTPRequest) (*GetAllOTPResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllOTPResponse), args.Error(1)
+}
+
+func (m *mockOTPService) GetCode(ctx context.Context, r GetOTPCodeRequest) (*GetOTPCodeResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetOTPCodeResponse), args.Error(1)
+}
+
+type mockSSHKeyService struct {
+	mock.Mock
+}
+
+func (m *mockSSHKeyService) Import(ctx context.Context, r ImportSSHKeyRequest) (*SSHKeyResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*SSHKeyResponse), args.Error(1)
+}
+
+func (m *mockSSHKeyService) Generate(ctx context.Context, r GenerateSSHKeyRequest) (*SSHKeyResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*SSHKeyResponse), args.Error(1)
+}
+
+func (m *mockSSHKeyService) Delete(ctx context.Context, r DeleteSSHKeyRequest) (*DeleteSSHKeyResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteSSHKeyResponse), args.Error(1)
+}
+
+func (m *mockSSHKeyService) GetAll(ctx context.Context, r GetAllSSHKeysRequest) (*GetAllSSHKeysResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllSSHKeysResponse), args.Error(1)
+}
+
+func (m *mockSSHKeyService) GetPrivateKey(ctx context.Context, r GetSSHPrivateKeyRequest) (*GetSSHPrivateKeyResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetSSHPrivateKeyResponse), args.Error(1)
+}
+
+type mockTemplateService struct {
+	mock.Mock
+}
+
+func (m *mockTemplateService) CreateTemplate(ctx context.Context, r CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateTemplateResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) UpdateTemplate(ctx context.Context, r UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateTemplateResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) DeleteTemplate(ctx context.Context, r DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteTemplateResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) GetAllTemplates(ctx context.Context, r GetAllTemplatesRequest) (*GetAllTemplatesResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllTemplatesResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) CreateRecord(ctx context.Context, r CreateRecordRequest) (*CreateRecordResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateRecordResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) UpdateRecord(ctx context.Context, r UpdateRecordRequest) (*UpdateRecordResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateRecordResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) DeleteRecord(ctx context.Context, r DeleteRecordRequest) (*DeleteRecordResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteRecordResponse), args.Error(1)
+}
+
+func (m *mockTemplateService) GetAllRecords(ctx context.Context, r GetAllRecordsRequest) (*GetAllRecordsResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllRecordsResponse), args.Error(1)
+}
+
+type mockSeedPhraseService struct {
+	mock.Mock
+}
+
+func (m *mockSeedPhraseService) Create(ctx context.Context, r CreateSeedPhraseRequest) (*CreateSeedPhraseResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateSeedPhraseResponse), args.Error(1)
+}
+
+func (m *mockSeedPhraseService) Delete(ctx context.Context, r DeleteSeedPhraseRequest) (*DeleteSeedPhraseResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteSeedPhraseResponse), args.Error(1)
+}
+
+func (m *mockSeedPhraseService) GetAll(ctx context.Context, r GetAllSeedPhrasesRequest) (*GetAllSeedPhrasesResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllSeedPhrasesResponse), args.Error(1)
+}
+
+func (m *mockSeedPhraseService) Reveal(ctx context.Context, r RevealSeedPhraseRequest) (*RevealSeedPhraseResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*RevealSeedPhraseResponse), args.Error(1)
+}
+
+type mockAPITokenService struct {
+	mock.Mock
+}
+
+func (m *mockAPITokenService) Create(ctx context.Context, r CreateAPITokenRequest) (*CreateAPITokenResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateAPITokenResponse), args.Error(1)
+}
+
+func (m *mockAPITokenService) Update(ctx context.Context, r UpdateAPITokenRequest) (*UpdateAPITokenResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateAPITokenResponse), args.Error(1)
+}
+
+func (m *mockAPITokenService) Delete(ctx context.Context, r DeleteAPITokenRequest) (*DeleteAPITokenResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteAPITokenResponse), args.Error(1)
+}
+
+func (m *mockAPITokenService) GetAll(ctx context.Context, r GetAllAPITokensRequest) (*GetAllAPITokensResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllAPITokensResponse), args.Error(1)
+}
+
+type mockIdentityService struct {
+	mock.Mock
+}
+
+func (m *mockIdentityService) Create(ctx context.Context, r CreateIdentityRequest) (*CreateIdentityResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateIdentityResponse), args.Error(1)
+}
+
+func (m *mockIdentityService) Update(ctx context.Context, r UpdateIdentityRequest) (*UpdateIdentityResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateIdentityResponse), args.Error(1)
+}
+
+func (m *mockIdentityService) Delete(ctx context.Context, r DeleteIdentityRequest) (*DeleteIdentityResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteIdentityResponse), args.Error(1)
+}
+
+func (m *mockIdentityService) GetAll(ctx context.Context, r GetAllIdentitiesRequest) (*GetAllIdentitiesResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllIdentitiesResponse), args.Error(1)
+}
+
+type mockWiFiService struct {
+	mock.Mock
+}
+
+func (m *mockWiFiService) Create(ctx context.Context, r CreateWiFiRequest) (*CreateWiFiResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateWiFiResponse), args.Error(1)
+}
+
+func (m *mockWiFiService) Update(ctx context.Context, r UpdateWiFiRequest) (*UpdateWiFiResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateWiFiResponse), args.Error(1)
+}
+
+func (m *mockWiFiService) Delete(ctx context.Context, r DeleteWiFiRequest) (*DeleteWiFiResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteWiFiResponse), args.Error(1)
+}
+
+func (m *mockWiFiService) GetAll(ctx context.Context, r GetAllWiFiRequest) (*GetAllWiFiResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllWiFiResponse), args.Error(1)
+}
+
+func (m *mockWiFiService) GetQRCode(ctx context.Context, r GetWiFiQRCodeRequest) (*GetWiFiQRCodeResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetWiFiQRCodeResponse), args.Error(1)
+}
+
+type mockReencryptService struct {
+	mock.Mock
+}
+
+func (m *mockReencryptService) Upgrade(ctx context.Context, r UpgradeEncryptionRequest) (*UpgradeEncryptionResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpgradeEncryptionResponse), args.Error(1)
+}
+
+func (m *mockReencryptService) Rotate(ctx context.Context, r RotateKeyRequest) (*RotateKeyResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*RotateKeyResponse), args.Error(1)
+}
+
+type mockVaultService struct {
+	mock.Mock
+}
+
+func (m *mockVaultService) Lock(ctx context.Context, r LockVaultRequest) (*LockVaultResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*LockVaultResponse), args.Error(1)
+}
+
+func (m *mockVaultService) Unlock(ctx context.Context, r UnlockVaultRequest) (*UnlockVaultResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UnlockVaultResponse), args.Error(1)
+}
+
+type mockBlobService struct {
+	mock.Mock
+}
+
+func (m *mockBlobService) Create(ctx context.Context, r CreateBlobRequest) (*CreateBlobResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateBlobResponse), args.Error(1)
+}
+
+func (m *mockBlobService) Update(ctx context.Context, r UpdateBlobRequest) (*UpdateBlobResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*UpdateBlobResponse), args.Error(1)
+}
+
+func (m *mockBlobService) Delete(ctx context.Context, r DeleteBlobRequest) (*DeleteBlobResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*DeleteBlobResponse), args.Error(1)
+}
+
+func (m *mockBlobService) GetAll(ctx context.Context, r GetAllBlobsRequest) (*GetAllBlobsResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*GetAllBlobsResponse), args.Error(1)
+}
+
+type mockRecoveryService struct {
+	mock.Mock
+}
+
+func (m *mockRecoveryService) CreateKit(ctx context.Context, r CreateRecoveryKitRequest) (*CreateRecoveryKitResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*CreateRecoveryKitResponse), args.Error(1)
+}
+
+func (m *mockRecoveryService) Recover(ctx context.Context, r RecoverKeyRequest) (*RecoverKeyResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(*RecoverKeyResponse), args.Error(1)
+}
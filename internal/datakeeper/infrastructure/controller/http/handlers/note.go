@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// NoteService store secure text notes for user
+type NoteService interface {
+	// Create save note
+	Create(ctx context.Context, r CreateNoteRequest) (*CreateNoteResponse, error)
+	// Update update note
+	Update(ctx context.Context, r UpdateNoteRequest) (*UpdateNoteResponse, error)
+	// Delete delete note
+	Delete(ctx context.Context, r DeleteNoteRequest) (*DeleteNoteResponse, error)
+	// GetAll get all notes for user
+	GetAll(ctx context.Context, r GetAllNotesRequest) (*GetAllNotesResponse, error)
+}
+
+// CreateNoteRequest Create note request
+type CreateNoteRequest struct {
+	// Title note title
+	Title string `json:"title"`
+	// Text note text
+	Text string `json:"text"`
+}
+
+// UpdateNoteRequest Update note request
+type UpdateNoteRequest struct {
+	UUID  string  `json:"uuid"`
+	Title *string `json:"title"`
+	Text  *string `json:"text"`
+}
+
+// DeleteNoteRequest Delete note request
+type DeleteNoteRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllNotesRequest Get all notes request
+type GetAllNotesRequest struct{}
+
+// CreateNoteResponse Create note response
+type CreateNoteResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateNoteResponse Update note response
+type UpdateNoteResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteNoteResponse Delete note response
+type DeleteNoteResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllNotesResponse Get all notes response
+type GetAllNotesResponse struct {
+	Items []GetAllNotesResponseItem `json:"items"`
+}
+
+// GetAllNotesResponseItem Get all notes response item
+type GetAllNotesResponseItem struct {
+	UUID  string `json:"uuid"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// NoteHandler Note handler
+type NoteHandler struct {
+	service      NoteService
+	ctxConverter ctxConverter
+}
+
+// NewNoteHandler create new note handler
+func NewNoteHandler(service NoteService, ctxConverter ctxConverter) *NoteHandler {
+	return &NoteHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateNote create a new note
+// @Summary Create a new note
+// @Description Create a new secure note for the user
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param note body CreateNoteRequest true "Note request body"
+// @Success 201 {object} CreateNoteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/notes [post]
+func (h *NoteHandler) CreateNote(c echo.Context) error {
+	req := new(CreateNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateNote update an existing note
+// @Summary Update an existing note
+// @Description Update an existing secure note for the user
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param note body UpdateNoteRequest true "Note request body"
+// @Success 200 {object} UpdateNoteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/notes [patch]
+func (h *NoteHandler) UpdateNote(c echo.Context) error {
+	req := new(UpdateNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Update(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteNote delete an existing note
+// @Summary Delete an existing note
+// @Description Delete an existing secure note for the user
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param note body DeleteNoteRequest true "Note request body"
+// @Success 200 {object} DeleteNoteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/notes [delete]
+func (h *NoteHandler) DeleteNote(c echo.Context) error {
+	req := new(DeleteNoteRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllNotes get all notes for user
+// @Summary Get all notes
+// @Description Get all secure notes for the user
+// @Tags notes
+// @Produce json
+// @Success 200 {object} GetAllNotesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/notes [get]
+func (h *NoteHandler) GetAllNotes(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllNotesRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNoteHandler_UpdateNote(t *testing.T) {
+	mockService := new(mockNoteService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewNoteHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.PATCH("/notes", handler.UpdateNote)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	title := "recovery"
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Update", mock.Anything, UpdateNoteRequest{UUID: uuidStr, Title: &title}).
+		Return(&UpdateNoteResponse{UUID: uuidStr}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.PATCH("/notes").
+		WithJSON(map[string]string{"uuid": uuidStr, "title": title}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("uuid", uuidStr)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
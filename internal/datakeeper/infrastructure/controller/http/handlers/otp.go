@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// OTPService store TOTP secrets for user
+type OTPService interface {
+	// Create save otp secret
+	Create(ctx context.Context, r CreateOTPRequest) (*CreateOTPResponse, error)
+	// Delete delete otp secret
+	Delete(ctx context.Context, r DeleteOTPRequest) (*DeleteOTPResponse, error)
+	// GetAll get all otp entries for user
+	GetAll(ctx context.Context, r GetAllOTPRequest) (*GetAllOTPResponse, error)
+	// GetCode get current code
+	GetCode(ctx context.Context, r GetOTPCodeRequest) (*GetOTPCodeResponse, error)
+}
+
+// CreateOTPRequest Create otp request, either URI or Secret must be set
+type CreateOTPRequest struct {
+	// Name otp name
+	Name string `json:"name"`
+	// URI otpauth://totp/... uri
+	URI string `json:"uri"`
+	// Issuer service issuer
+	Issuer string `json:"issuer"`
+	// Account account name
+	Account string `json:"account"`
+	// Secret base32 secret
+	Secret string `json:"secret"`
+	// Algorithm SHA1, SHA256 or SHA512 (SHA1 by default)
+	Algorithm string `json:"algorithm"`
+	// Digits code length (6 by default)
+	Digits int `json:"digits"`
+	// Period code period in seconds (30 by default)
+	Period int `json:"period"`
+}
+
+// CreateOTPResponse Create otp response
+type CreateOTPResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteOTPRequest Delete otp request
+type DeleteOTPRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteOTPResponse Delete otp response
+type DeleteOTPResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllOTPRequest Get all otp request
+type GetAllOTPRequest struct{}
+
+// GetAllOTPResponse Get all otp response
+type GetAllOTPResponse struct {
+	Items []GetAllOTPResponseItem `json:"items"`
+}
+
+// GetAllOTPResponseItem Get all otp response item
+type GetAllOTPResponseItem struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period"`
+}
+
+// GetOTPCodeRequest Get otp code request
+type GetOTPCodeRequest struct {
+	UUID string `param:"uuid"`
+}
+
+// GetOTPCodeResponse Get otp code response
+type GetOTPCodeResponse struct {
+	// Code current code
+	Code string `json:"code"`
+	// ExpiresIn seconds until code expires
+	ExpiresIn int `json:"expires_in"`
+	// Period code period in seconds
+	Period int `json:"period"`
+}
+
+// OTPHandler OTP handler
+type OTPHandler struct {
+	service      OTPService
+	ctxConverter ctxConverter
+}
+
+// NewOTPHandler create new otp handler
+func NewOTPHandler(service OTPService, ctxConverter ctxConverter) *OTPHandler {
+	return &OTPHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateOTP create a new otp secret
+// @Summary Create a new otp secret
+// @Description Save otpauth:// uri or raw base32 secret for the user
+// @Tags otp
+// @Accept json
+// @Produce json
+// @Param otp body CreateOTPRequest true "OTP request body"
+// @Success 201 {object} CreateOTPResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/otp [post]
+func (h *OTPHandler) CreateOTP(c echo.Context) error {
+	req := new(CreateOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// DeleteOTP delete an existing otp secret
+// @Summary Delete an existing otp secret
+// @Description Delete an existing otp secret for the user
+// @Tags otp
+// @Accept json
+// @Produce json
+// @Param otp body DeleteOTPRequest true "OTP request body"
+// @Success 200 {object} DeleteOTPResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/otp [delete]
+func (h *OTPHandler) DeleteOTP(c echo.Context) error {
+	req := new(DeleteOTPRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllOTP get all otp entries for user
+// @Summary Get all otp entries
+// @Description Get all otp entries for the user without secrets
+// @Tags otp
+// @Produce json
+// @Success 200 {object} GetAllOTPResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/otp [get]
+func (h *OTPHandler) GetAllOTP(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllOTPRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetOTPCode get current otp code
+// @Summary Get current otp code
+// @Description Generate current code and its remaining validity
+// @Tags otp
+// @Produce json
+// @Param uuid path string true "OTP uuid"
+// @Success 200 {object} GetOTPCodeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/otp/{uuid}/code [get]
+func (h *OTPHandler) GetOTPCode(c echo.Context) error {
+	req := new(GetOTPCodeRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetCode(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOTPHandler_GetOTPCode(t *testing.T) {
+	mockService := new(mockOTPService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewOTPHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.GET("/otp/:uuid/code", handler.GetOTPCode)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("GetCode", mock.Anything, GetOTPCodeRequest{UUID: uuidStr}).
+		Return(&GetOTPCodeResponse{Code: "123456", ExpiresIn: 12, Period: 30}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.GET("/otp/"+uuidStr+"/code").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("code", "123456").
+		HasValue("expires_in", 12)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
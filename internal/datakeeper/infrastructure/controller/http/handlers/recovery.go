@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// RecoveryService recovery kit of user key
+type RecoveryService interface {
+	// CreateKit split user key into shares
+	CreateKit(ctx context.Context, r CreateRecoveryKitRequest) (*CreateRecoveryKitResponse, error)
+	// Recover rebuild user key from shares and set new master password
+	Recover(ctx context.Context, r RecoverKeyRequest) (*RecoverKeyResponse, error)
+}
+
+// CreateRecoveryKitRequest Create recovery kit request
+type CreateRecoveryKitRequest struct {
+	// Shares number of shares, at most 16
+	Shares int `json:"shares"`
+	// Threshold number of shares to rebuild key, at least 2
+	Threshold int `json:"threshold"`
+}
+
+// CreateRecoveryKitResponse Create recovery kit response
+type CreateRecoveryKitResponse struct {
+	// Threshold number of shares to rebuild key
+	Threshold int `json:"threshold"`
+	// Items shares, they are not stored and can not be shown again
+	Items []RecoveryShare `json:"items"`
+}
+
+// RecoveryShare share of user key
+type RecoveryShare struct {
+	// Index number of share
+	Index int `json:"index"`
+	// Text printable share
+	Text string `json:"text"`
+	// QR share text as PNG QR code
+	QR []byte `json:"qr"`
+}
+
+// RecoverKeyRequest Recover key request
+type RecoverKeyRequest struct {
+	// Login user
+	Login string `json:"login"`
+	// Password user's password
+	Password string `json:"password"`
+	// Shares share texts, at least threshold of kit
+	Shares []string `json:"shares"`
+	// NewMasterPassword password to derive new cypher key
+	NewMasterPassword string `json:"new_master_password"`
+}
+
+// RecoverKeyResponse Recover key response
+type RecoverKeyResponse struct {
+	SessionTokens
+}
+
+// RecoveryHandler Recovery handler
+type RecoveryHandler struct {
+	service      RecoveryService
+	ctxConverter ctxConverter
+}
+
+// NewRecoveryHandler create new recovery handler
+func NewRecoveryHandler(service RecoveryService, ctxConverter ctxConverter) *RecoveryHandler {
+	return &RecoveryHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateRecoveryKit create recovery kit
+// @Summary Create recovery kit
+// @Description Split cypher key into Shamir shares, any threshold shares rebuild key. New kit replaces previous one
+// @Tags recovery
+// @Accept json
+// @Produce json
+// @Param request body CreateRecoveryKitRequest true "Number of shares and threshold"
+// @Success 200 {object} CreateRecoveryKitResponse
+// @Failure 400 {object} map[string]string
+// @Failure 423 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/recovery/kit [post]
+func (h *RecoveryHandler) CreateRecoveryKit(c echo.Context) error {
+	var req CreateRecoveryKitRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.CreateKit(ctx, req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// RecoverKey recover cypher key
+// @Summary Recover key
+// @Description Rebuild cypher key from recovery shares, re-encrypt data with key derived from new master password and sign in
+// @Tags recovery
+// @Accept json
+// @Produce json
+// @Param request body RecoverKeyRequest true "Credentials, shares and new master password"
+// @Success 200 {object} RecoverKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/recovery/recover [post]
+func (h *RecoveryHandler) RecoverKey(c echo.Context) error {
+	var req RecoverKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Recover(c.Request().Context(), req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	setSessionCookies(c, res.SessionTokens)
+
+	return c.JSON(http.StatusOK, res)
+}
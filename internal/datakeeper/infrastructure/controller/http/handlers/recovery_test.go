@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecoveryHandler_CreateRecoveryKit(t *testing.T) {
+	mockService := new(mockRecoveryService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewRecoveryHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/recovery/kit", handler.CreateRecoveryKit)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("CreateKit", mock.Anything, CreateRecoveryKitRequest{Shares: 3, Threshold: 2}).
+		Return(&CreateRecoveryKitResponse{Threshold: 2, Items: []RecoveryShare{
+			{Index: 1, Text: "DKR-AAAAA", QR: []byte("png")},
+		}}, nil)
+	mockService.On("CreateKit", mock.Anything, CreateRecoveryKitRequest{Shares: 3, Threshold: 1}).
+		Return((*CreateRecoveryKitResponse)(nil), customerr.ErrorWithCode(customerr.INVALID_RECOVERY_KIT, http.StatusBadRequest))
+
+	expect := httpexpect.Default(t, server.URL)
+
+	items := expect.POST("/recovery/kit").
+		WithJSON(CreateRecoveryKitRequest{Shares: 3, Threshold: 2}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("threshold", 2).
+		Value("items").Array()
+	items.Length().IsEqual(1)
+	// png is base64 in json
+	items.Value(0).Object().
+		HasValue("text", "DKR-AAAAA").
+		HasValue("qr", "cG5n")
+
+	expect.POST("/recovery/kit").
+		WithJSON(CreateRecoveryKitRequest{Shares: 3, Threshold: 1}).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().
+		HasValue("message", customerr.INVALID_RECOVERY_KIT)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRecoveryHandler_RecoverKey(t *testing.T) {
+	mockService := new(mockRecoveryService)
+	handler := NewRecoveryHandler(mockService, new(mockCtxConverter))
+
+	e := echo.New()
+	e.POST("/recovery/recover", handler.RecoverKey)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	request := RecoverKeyRequest{
+		Login:             "test@example.com",
+		Password:          "password",
+		Shares:            []string{"DKR-AAAAA", "DKR-BBBBB"},
+		NewMasterPassword: "new master password",
+	}
+	mockService.On("Recover", mock.Anything, request).Return(&RecoverKeyResponse{SessionTokens{Token: "token", RefreshToken: "refresh"}}, nil)
+	wrong := request
+	wrong.Shares = []string{"DKR-CCCCC"}
+	mockService.On("Recover", mock.Anything, wrong).
+		Return((*RecoverKeyResponse)(nil), customerr.ErrorWithCode(customerr.RECOVERY_FAILED, http.StatusBadRequest))
+
+	expect := httpexpect.Default(t, server.URL)
+
+	// user is signed in with recovered vault
+	expect.POST("/recovery/recover").
+		WithJSON(request).
+		Expect().
+		Status(http.StatusOK).
+		Cookie("User").Value().IsEqual("token")
+
+	expect.POST("/recovery/recover").
+		WithJSON(wrong).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().Object().
+		HasValue("message", customerr.RECOVERY_FAILED)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// ReencryptService re-encrypt stored data of user
+type ReencryptService interface {
+	// Upgrade re-encrypt rows stored in legacy format
+	Upgrade(ctx context.Context, r UpgradeEncryptionRequest) (*UpgradeEncryptionResponse, error)
+	// Rotate re-encrypt rows with new key
+	Rotate(ctx context.Context, r RotateKeyRequest) (*RotateKeyResponse, error)
+}
+
+// UpgradeEncryptionRequest Upgrade encryption request
+type UpgradeEncryptionRequest struct{}
+
+// UpgradeEncryptionResponse Upgrade encryption response
+type UpgradeEncryptionResponse struct {
+	// Data number of upgraded user_data rows
+	Data int `json:"data"`
+	// Files number of upgraded file_repository rows
+	Files int `json:"files"`
+}
+
+// RotateKeyRequest Rotate key request, account with master password changes master password,
+// account registered before master password changes key
+type RotateKeyRequest struct {
+	// OldKey current key of user
+	OldKey string `json:"old_key,omitempty"`
+	// NewKey key to re-encrypt data with
+	NewKey string `json:"new_key,omitempty"`
+	// OldMasterPassword current master password of user
+	OldMasterPassword string `json:"old_master_password,omitempty"`
+	// NewMasterPassword master password to derive new key from
+	NewMasterPassword string `json:"new_master_password,omitempty"`
+}
+
+// RotateKeyResponse Rotate key response
+type RotateKeyResponse struct {
+	// Data number of rotated user_data rows
+	Data int `json:"data"`
+	// Files number of rotated file_repository rows
+	Files int `json:"files"`
+	// RecoveryKitDeleted recovery kit rebuilt old key and was deleted, new kit must be created
+	RecoveryKitDeleted bool `json:"recovery_kit_deleted"`
+}
+
+// ReencryptHandler Re-encryption handler
+type ReencryptHandler struct {
+	service      ReencryptService
+	ctxConverter ctxConverter
+}
+
+// NewReencryptHandler create new re-encryption handler
+func NewReencryptHandler(service ReencryptService, ctxConverter ctxConverter) *ReencryptHandler {
+	return &ReencryptHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// UpgradeEncryption re-encrypt legacy rows of user
+// @Summary Upgrade encryption
+// @Description Re-encrypt user data and files stored in legacy AES-CBC format with AES-256-GCM
+// @Tags keys
+// @Produce json
+// @Success 200 {object} UpgradeEncryptionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/keys/upgrade [post]
+func (h *ReencryptHandler) UpgradeEncryption(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Upgrade(ctx, UpgradeEncryptionRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// RotateKey re-encrypt all rows of user with new key
+// @Summary Rotate key
+// @Description Re-encrypt user data and files with new key in one transaction. Account with master password sends old and new master password, account without it sends old and new key and repeats request with same keys to resume interrupted rotation. Recovery kit rebuilds old key, so it is deleted and new kit must be created
+// @Tags keys
+// @Accept json
+// @Produce json
+// @Param request body RotateKeyRequest true "Old and new master password or key"
+// @Success 200 {object} RotateKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/keys/rotate [post]
+func (h *ReencryptHandler) RotateKey(c echo.Context) error {
+	var req RotateKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Rotate(ctx, req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
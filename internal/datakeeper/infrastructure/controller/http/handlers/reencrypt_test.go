@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReencryptHandler_UpgradeEncryption(t *testing.T) {
+	mockService := new(mockReencryptService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewReencryptHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/keys/upgrade", handler.UpgradeEncryption)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Upgrade", mock.Anything, UpgradeEncryptionRequest{}).Return(&UpgradeEncryptionResponse{Data: 3, Files: 1}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/keys/upgrade").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("data", 3).
+		HasValue("files", 1)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
+
+func TestReencryptHandler_RotateKey(t *testing.T) {
+	mockService := new(mockReencryptService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewReencryptHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/keys/rotate", handler.RotateKey)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	req := RotateKeyRequest{OldKey: "1234567890123456", NewKey: "6543210987654321"}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Rotate", mock.Anything, req).Return(&RotateKeyResponse{Data: 5, Files: 2}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/keys/rotate").
+		WithJSON(req).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("data", 5).
+		HasValue("files", 2)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
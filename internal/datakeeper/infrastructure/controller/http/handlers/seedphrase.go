@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// SeedPhraseService store wallet recovery phrases for user
+type SeedPhraseService interface {
+	// Create validate and save seed phrase
+	Create(ctx context.Context, r CreateSeedPhraseRequest) (*CreateSeedPhraseResponse, error)
+	// Delete delete seed phrase
+	Delete(ctx context.Context, r DeleteSeedPhraseRequest) (*DeleteSeedPhraseResponse, error)
+	// GetAll get all seed phrases for user without words
+	GetAll(ctx context.Context, r GetAllSeedPhrasesRequest) (*GetAllSeedPhrasesResponse, error)
+	// Reveal get words of seed phrase
+	Reveal(ctx context.Context, r RevealSeedPhraseRequest) (*RevealSeedPhraseResponse, error)
+}
+
+// CreateSeedPhraseRequest Create seed phrase request
+type CreateSeedPhraseRequest struct {
+	// Name wallet name
+	Name string `json:"name"`
+	// Phrase BIP-39 mnemonic, words separated by spaces
+	Phrase string `json:"phrase"`
+	// Passphrase optional BIP-39 passphrase
+	Passphrase string `json:"passphrase"`
+}
+
+// CreateSeedPhraseResponse Create seed phrase response
+type CreateSeedPhraseResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteSeedPhraseRequest Delete seed phrase request
+type DeleteSeedPhraseRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteSeedPhraseResponse Delete seed phrase response
+type DeleteSeedPhraseResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllSeedPhrasesRequest Get all seed phrases request
+type GetAllSeedPhrasesRequest struct{}
+
+// GetAllSeedPhrasesResponse Get all seed phrases response
+type GetAllSeedPhrasesResponse struct {
+	Items []GetAllSeedPhrasesResponseItem `json:"items"`
+}
+
+// GetAllSeedPhrasesResponseItem Get all seed phrases response item
+type GetAllSeedPhrasesResponseItem struct {
+	UUID      string `json:"uuid"`
+	Name      string `json:"name"`
+	WordCount int    `json:"word_count"`
+	// HasPassphrase seed phrase is protected by BIP-39 passphrase
+	HasPassphrase bool `json:"has_passphrase"`
+}
+
+// RevealSeedPhraseRequest Reveal seed phrase request
+type RevealSeedPhraseRequest struct {
+	UUID string `param:"uuid"`
+}
+
+// RevealSeedPhraseResponse Reveal seed phrase response
+type RevealSeedPhraseResponse struct {
+	Words      []string `json:"words"`
+	Passphrase string   `json:"passphrase"`
+}
+
+// SeedPhraseHandler Seed phrase handler
+type SeedPhraseHandler struct {
+	service      SeedPhraseService
+	ctxConverter ctxConverter
+}
+
+// NewSeedPhraseHandler create new seed phrase handler
+func NewSeedPhraseHandler(service SeedPhraseService, ctxConverter ctxConverter) *SeedPhraseHandler {
+	return &SeedPhraseHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateSeedPhrase create seed phrase
+// @Summary Create seed phrase
+// @Description Validate BIP-39 mnemonic against english wordlist and checksum and save it for the user
+// @Tags seed-phrases
+// @Accept json
+// @Produce json
+// @Param phrase body CreateSeedPhraseRequest true "Seed phrase request body"
+// @Success 201 {object} CreateSeedPhraseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seed-phrases [post]
+func (h *SeedPhraseHandler) CreateSeedPhrase(c echo.Context) error {
+	req := new(CreateSeedPhraseRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// DeleteSeedPhrase delete seed phrase
+// @Summary Delete seed phrase
+// @Description Delete an existing seed phrase for the user
+// @Tags seed-phrases
+// @Accept json
+// @Produce json
+// @Param phrase body DeleteSeedPhraseRequest true "Seed phrase request body"
+// @Success 200 {object} DeleteSeedPhraseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seed-phrases [delete]
+func (h *SeedPhraseHandler) DeleteSeedPhrase(c echo.Context) error {
+	req := new(DeleteSeedPhraseRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllSeedPhrases get all seed phrases for user
+// @Summary Get all seed phrases
+// @Description Get names and word counts of all seed phrases for the user, words are not returned
+// @Tags seed-phrases
+// @Produce json
+// @Success 200 {object} GetAllSeedPhrasesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seed-phrases [get]
+func (h *SeedPhraseHandler) GetAllSeedPhrases(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllSeedPhrasesRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// RevealSeedPhrase reveal seed phrase words
+// @Summary Reveal seed phrase
+// @Description Decrypt and return words and passphrase of seed phrase
+// @Tags seed-phrases
+// @Produce json
+// @Param uuid path string true "Seed phrase uuid"
+// @Success 200 {object} RevealSeedPhraseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/seed-phrases/{uuid}/reveal [post]
+func (h *SeedPhraseHandler) RevealSeedPhrase(c echo.Context) error {
+	req := new(RevealSeedPhraseRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Reveal(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSeedPhraseHandler_GetAllSeedPhrases(t *testing.T) {
+	mockService := new(mockSeedPhraseService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewSeedPhraseHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.GET("/seed-phrases", handler.GetAllSeedPhrases)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	response := &GetAllSeedPhrasesResponse{Items: []GetAllSeedPhrasesResponseItem{
+		{UUID: uuid.NewString(), Name: "treasury", WordCount: 24},
+	}}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("GetAll", mock.Anything, GetAllSeedPhrasesRequest{}).Return(response, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	item := expect.GET("/seed-phrases").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("items").Array().Value(0).Object()
+	item.HasValue("word_count", 24)
+	item.NotContainsKey("words")
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
+
+func TestSeedPhraseHandler_RevealSeedPhrase(t *testing.T) {
+	mockService := new(mockSeedPhraseService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewSeedPhraseHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/seed-phrases/:uuid/reveal", handler.RevealSeedPhrase)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	response := &RevealSeedPhraseResponse{Words: []string{"zoo", "wrong"}}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Reveal", mock.Anything, RevealSeedPhraseRequest{UUID: uuidStr}).Return(response, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/seed-phrases/{uuid}/reveal", uuidStr).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		Value("words").Array().ConsistsOf("zoo", "wrong")
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// SSHKeyService store ssh key pairs for user
+type SSHKeyService interface {
+	// Import save existing private key
+	Import(ctx context.Context, r ImportSSHKeyRequest) (*SSHKeyResponse, error)
+	// Generate generate new key pair
+	Generate(ctx context.Context, r GenerateSSHKeyRequest) (*SSHKeyResponse, error)
+	// Delete delete key pair
+	Delete(ctx context.Context, r DeleteSSHKeyRequest) (*DeleteSSHKeyResponse, error)
+	// GetAll get all key pairs for user
+	GetAll(ctx context.Context, r GetAllSSHKeysRequest) (*GetAllSSHKeysResponse, error)
+	// GetPrivateKey get private key
+	GetPrivateKey(ctx context.Context, r GetSSHPrivateKeyRequest) (*GetSSHPrivateKeyResponse, error)
+}
+
+// ImportSSHKeyRequest Import ssh key request
+type ImportSSHKeyRequest struct {
+	// Name key name
+	Name string `json:"name"`
+	// PrivateKey OpenSSH or PEM private key
+	PrivateKey string `json:"private_key"`
+	// Passphrase passphrase for protected key, stored key stays protected
+	Passphrase string `json:"passphrase"`
+	// Comment public key comment
+	Comment string `json:"comment"`
+}
+
+// GenerateSSHKeyRequest Generate ssh key request
+type GenerateSSHKeyRequest struct {
+	// Name key name
+	Name string `json:"name"`
+	// Type ed25519 (default) or rsa
+	Type string `json:"type"`
+	// Bits rsa key size (3072 by default)
+	Bits int `json:"bits"`
+	// Comment public key comment
+	Comment string `json:"comment"`
+	// Passphrase optional passphrase to protect private key
+	Passphrase string `json:"passphrase"`
+}
+
+// SSHKeyResponse Import/generate ssh key response
+type SSHKeyResponse struct {
+	UUID        string `json:"uuid"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// DeleteSSHKeyRequest Delete ssh key request
+type DeleteSSHKeyRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteSSHKeyResponse Delete ssh key response
+type DeleteSSHKeyResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllSSHKeysRequest Get all ssh keys request
+type GetAllSSHKeysRequest struct{}
+
+// GetAllSSHKeysResponse Get all ssh keys response
+type GetAllSSHKeysResponse struct {
+	Items []GetAllSSHKeysResponseItem `json:"items"`
+}
+
+// GetAllSSHKeysResponseItem Get all ssh keys response item
+type GetAllSSHKeysResponseItem struct {
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	// Encrypted private key is protected by passphrase
+	Encrypted bool `json:"encrypted"`
+}
+
+// GetSSHPrivateKeyRequest Get ssh private key request
+type GetSSHPrivateKeyRequest struct {
+	UUID string `param:"uuid"`
+}
+
+// GetSSHPrivateKeyResponse Get ssh private key response
+type GetSSHPrivateKeyResponse struct {
+	PrivateKey string `json:"private_key"`
+}
+
+// SSHKeyHandler SSH key handler
+type SSHKeyHandler struct {
+	service      SSHKeyService
+	ctxConverter ctxConverter
+}
+
+// NewSSHKeyHandler create new ssh key handler
+func NewSSHKeyHandler(service SSHKeyService, ctxConverter ctxConverter) *SSHKeyHandler {
+	return &SSHKeyHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// ImportSSHKey import existing private key
+// @Summary Import ssh key
+// @Description Import existing OpenSSH private key for the user
+// @Tags ssh-keys
+// @Accept json
+// @Produce json
+// @Param key body ImportSSHKeyRequest true "SSH key request body"
+// @Success 201 {object} SSHKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/ssh-keys [post]
+func (h *SSHKeyHandler) ImportSSHKey(c echo.Context) error {
+	req := new(ImportSSHKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Import(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// GenerateSSHKey generate new key pair
+// @Summary Generate ssh key
+// @Description Generate new ed25519 or rsa key pair for the user
+// @Tags ssh-keys
+// @Accept json
+// @Produce json
+// @Param key body GenerateSSHKeyRequest true "SSH key request body"
+// @Success 201 {object} SSHKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/ssh-keys/generate [post]
+func (h *SSHKeyHandler) GenerateSSHKey(c echo.Context) error {
+	req := new(GenerateSSHKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Generate(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// DeleteSSHKey delete key pair
+// @Summary Delete ssh key
+// @Description Delete an existing ssh key pair for the user
+// @Tags ssh-keys
+// @Accept json
+// @Produce json
+// @Param key body DeleteSSHKeyRequest true "SSH key request body"
+// @Success 200 {object} DeleteSSHKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/ssh-keys [delete]
+func (h *SSHKeyHandler) DeleteSSHKey(c echo.Context) error {
+	req := new(DeleteSSHKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllSSHKeys get all key pairs for user
+// @Summary Get all ssh keys
+// @Description Get public keys and SHA256 fingerprints of all ssh keys for the user
+// @Tags ssh-keys
+// @Produce json
+// @Success 200 {object} GetAllSSHKeysResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/ssh-keys [get]
+func (h *SSHKeyHandler) GetAllSSHKeys(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllSSHKeysRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetSSHPrivateKey get private key
+// @Summary Get ssh private key
+// @Description Decrypt and return private key in OpenSSH format
+// @Tags ssh-keys
+// @Produce json
+// @Param uuid path string true "SSH key uuid"
+// @Success 200 {object} GetSSHPrivateKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/ssh-keys/{uuid}/private [get]
+func (h *SSHKeyHandler) GetSSHPrivateKey(c echo.Context) error {
+	req := new(GetSSHPrivateKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetPrivateKey(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSSHKeyHandler_GenerateSSHKey(t *testing.T) {
+	mockService := new(mockSSHKeyService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewSSHKeyHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/ssh-keys/generate", handler.GenerateSSHKey)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	response := &SSHKeyResponse{
+		UUID:        uuid.NewString(),
+		PublicKey:   "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB deploy",
+		Fingerprint: "SHA256:abc",
+	}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Generate", mock.Anything, GenerateSSHKeyRequest{Name: "deploy", Type: "ed25519"}).Return(response, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/ssh-keys/generate").
+		WithJSON(map[string]string{"name": "deploy", "type": "ed25519"}).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		HasValue("public_key", response.PublicKey).
+		HasValue("fingerprint", response.Fingerprint)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
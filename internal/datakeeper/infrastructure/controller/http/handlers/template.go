@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// TemplateService store user-defined record types and records of these types
+type TemplateService interface {
+	// CreateTemplate save template
+	CreateTemplate(ctx context.Context, r CreateTemplateRequest) (*CreateTemplateResponse, error)
+	// UpdateTemplate update template
+	UpdateTemplate(ctx context.Context, r UpdateTemplateRequest) (*UpdateTemplateResponse, error)
+	// DeleteTemplate delete template without records
+	DeleteTemplate(ctx context.Context, r DeleteTemplateRequest) (*DeleteTemplateResponse, error)
+	// GetAllTemplates get all templates for user
+	GetAllTemplates(ctx context.Context, r GetAllTemplatesRequest) (*GetAllTemplatesResponse, error)
+	// CreateRecord save record validated against its template
+	CreateRecord(ctx context.Context, r CreateRecordRequest) (*CreateRecordResponse, error)
+	// UpdateRecord update record
+	UpdateRecord(ctx context.Context, r UpdateRecordRequest) (*UpdateRecordResponse, error)
+	// DeleteRecord delete record
+	DeleteRecord(ctx context.Context, r DeleteRecordRequest) (*DeleteRecordResponse, error)
+	// GetAllRecords get all records for user
+	GetAllRecords(ctx context.Context, r GetAllRecordsRequest) (*GetAllRecordsResponse, error)
+}
+
+// TemplateField Template field
+type TemplateField struct {
+	// Name field name
+	Name string `json:"name"`
+	// Kind text, hidden, url, date, number or boolean
+	Kind string `json:"kind"`
+	// Required field must be set in record
+	Required bool `json:"required"`
+}
+
+// CreateTemplateRequest Create template request
+type CreateTemplateRequest struct {
+	Name   string          `json:"name"`
+	Fields []TemplateField `json:"fields"`
+}
+
+// CreateTemplateResponse Create template response
+type CreateTemplateResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateTemplateRequest Update template request, fields are replaced as a whole
+type UpdateTemplateRequest struct {
+	UUID   string          `json:"uuid"`
+	Name   *string         `json:"name"`
+	Fields []TemplateField `json:"fields"`
+}
+
+// UpdateTemplateResponse Update template response
+type UpdateTemplateResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteTemplateRequest Delete template request
+type DeleteTemplateRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteTemplateResponse Delete template response
+type DeleteTemplateResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllTemplatesRequest Get all templates request
+type GetAllTemplatesRequest struct{}
+
+// GetAllTemplatesResponse Get all templates response
+type GetAllTemplatesResponse struct {
+	Items []GetAllTemplatesResponseItem `json:"items"`
+}
+
+// GetAllTemplatesResponseItem Get all templates response item
+type GetAllTemplatesResponseItem struct {
+	UUID   string          `json:"uuid"`
+	Name   string          `json:"name"`
+	Fields []TemplateField `json:"fields"`
+}
+
+// CreateRecordRequest Create record request
+type CreateRecordRequest struct {
+	TemplateUUID string                 `json:"template_uuid"`
+	Name         string                 `json:"name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+// CreateRecordResponse Create record response
+type CreateRecordResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateRecordRequest Update record request, values are merged, null value removes field
+type UpdateRecordRequest struct {
+	UUID   string                 `json:"uuid"`
+	Name   *string                `json:"name"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// UpdateRecordResponse Update record response
+type UpdateRecordResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteRecordRequest Delete record request
+type DeleteRecordRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteRecordResponse Delete record response
+type DeleteRecordResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllRecordsRequest Get all records request
+type GetAllRecordsRequest struct {
+	// TemplateUUID optional filter by template
+	TemplateUUID string `query:"template_uuid"`
+}
+
+// GetAllRecordsResponse Get all records response
+type GetAllRecordsResponse struct {
+	Items []GetAllRecordsResponseItem `json:"items"`
+}
+
+// GetAllRecordsResponseItem Get all records response item
+type GetAllRecordsResponseItem struct {
+	UUID         string                 `json:"uuid"`
+	TemplateUUID string                 `json:"template_uuid"`
+	Name         string                 `json:"name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+// TemplateHandler Template handler
+type TemplateHandler struct {
+	service      TemplateService
+	ctxConverter ctxConverter
+}
+
+// NewTemplateHandler create new template handler
+func NewTemplateHandler(service TemplateService, ctxConverter ctxConverter) *TemplateHandler {
+	return &TemplateHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateTemplate create a new template
+// @Summary Create a new template
+// @Description Create a new record type with typed fields
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body CreateTemplateRequest true "Template request body"
+// @Success 201 {object} CreateTemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/templates [post]
+func (h *TemplateHandler) CreateTemplate(c echo.Context) error {
+	req := new(CreateTemplateRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.CreateTemplate(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateTemplate update an existing template
+// @Summary Update an existing template
+// @Description Update name or fields of an existing template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body UpdateTemplateRequest true "Template request body"
+// @Success 200 {object} UpdateTemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/templates [patch]
+func (h *TemplateHandler) UpdateTemplate(c echo.Context) error {
+	req := new(UpdateTemplateRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.UpdateTemplate(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteTemplate delete an existing template
+// @Summary Delete an existing template
+// @Description Delete template which has no records
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body DeleteTemplateRequest true "Template request body"
+// @Success 200 {object} DeleteTemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/templates [delete]
+func (h *TemplateHandler) DeleteTemplate(c echo.Context) error {
+	req := new(DeleteTemplateRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.DeleteTemplate(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllTemplates get all templates for user
+// @Summary Get all templates
+// @Description Get all record types for the user
+// @Tags templates
+// @Produce json
+// @Success 200 {object} GetAllTemplatesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/templates [get]
+func (h *TemplateHandler) GetAllTemplates(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAllTemplates(ctx, GetAllTemplatesRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// CreateRecord create a new record
+// @Summary Create a new record
+// @Description Create a new record validated against its template
+// @Tags records
+// @Accept json
+// @Produce json
+// @Param record body CreateRecordRequest true "Record request body"
+// @Success 201 {object} CreateRecordResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/records [post]
+func (h *TemplateHandler) CreateRecord(c echo.Context) error {
+	req := new(CreateRecordRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.CreateRecord(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateRecord update an existing record
+// @Summary Update an existing record
+// @Description Merge values into an existing record and validate it against its template
+// @Tags records
+// @Accept json
+// @Produce json
+// @Param record body UpdateRecordRequest true "Record request body"
+// @Success 200 {object} UpdateRecordResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/records [patch]
+func (h *TemplateHandler) UpdateRecord(c echo.Context) error {
+	req := new(UpdateRecordRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.UpdateRecord(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteRecord delete an existing record
+// @Summary Delete an existing record
+// @Description Delete an existing record for the user
+// @Tags records
+// @Accept json
+// @Produce json
+// @Param record body DeleteRecordRequest true "Record request body"
+// @Success 200 {object} DeleteRecordResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/records [delete]
+func (h *TemplateHandler) DeleteRecord(c echo.Context) error {
+	req := new(DeleteRecordRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.DeleteRecord(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllRecords get all records for user
+// @Summary Get all records
+// @Description Get all records for the user, optionally filtered by template
+// @Tags records
+// @Produce json
+// @Param template_uuid query string false "Template uuid"
+// @Success 200 {object} GetAllRecordsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/records [get]
+func (h *TemplateHandler) GetAllRecords(c echo.Context) error {
+	req := new(GetAllRecordsRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAllRecords(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	mockService := new(mockTemplateService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewTemplateHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/templates", handler.CreateTemplate)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	request := CreateTemplateRequest{
+		Name:   "server",
+		Fields: []TemplateField{{Name: "host", Kind: "url", Required: true}},
+	}
+	response := &CreateTemplateResponse{UUID: uuid.NewString()}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("CreateTemplate", mock.Anything, request).Return(response, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/templates").
+		WithJSON(request).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().Object().
+		HasValue("uuid", response.UUID)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
+
+func TestTemplateHandler_DeleteTemplateInUse(t *testing.T) {
+	mockService := new(mockTemplateService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewTemplateHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.DELETE("/templates", handler.DeleteTemplate)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	request := DeleteTemplateRequest{UUID: uuid.NewString()}
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("DeleteTemplate", mock.Anything, request).
+		Return((*DeleteTemplateResponse)(nil), customerr.ErrorWithCode(customerr.TEMPLATE_IN_USE, http.StatusConflict))
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.DELETE("/templates").
+		WithJSON(request).
+		Expect().
+		Status(http.StatusConflict)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
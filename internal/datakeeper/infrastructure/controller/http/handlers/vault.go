@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// VaultService lock and unlock user vault
+type VaultService interface {
+	// Lock lock vault
+	Lock(ctx context.Context, r LockVaultRequest) (*LockVaultResponse, error)
+	// Unlock unlock vault
+	Unlock(ctx context.Context, r UnlockVaultRequest) (*UnlockVaultResponse, error)
+}
+
+// LockVaultRequest Lock vault request
+type LockVaultRequest struct{}
+
+// LockVaultResponse Lock vault response
+type LockVaultResponse struct {
+	// Locked vault is locked
+	Locked bool `json:"locked"`
+}
+
+// UnlockVaultRequest Unlock vault request
+type UnlockVaultRequest struct {
+	// MasterPassword password to derive cypher key
+	MasterPassword string `json:"master_password"`
+	// Key cypher, only for accounts registered before master password
+	Key string `json:"key,omitempty"`
+}
+
+// UnlockVaultResponse Unlock vault response
+type UnlockVaultResponse struct {
+	// Locked vault is locked
+	Locked bool `json:"locked"`
+}
+
+// VaultHandler Vault handler
+type VaultHandler struct {
+	service      VaultService
+	ctxConverter ctxConverter
+}
+
+// NewVaultHandler create new vault handler
+func NewVaultHandler(service VaultService, ctxConverter ctxConverter) *VaultHandler {
+	return &VaultHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// LockVault lock vault of user
+// @Summary Lock vault
+// @Description Forget cypher key of user, data endpoints return 423 "vault locked" until vault is unlocked
+// @Tags vault
+// @Produce json
+// @Success 200 {object} LockVaultResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/vault/lock [post]
+func (h *VaultHandler) LockVault(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Lock(ctx, LockVaultRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// UnlockVault unlock vault of user
+// @Summary Unlock vault
+// @Description Derive cypher key from master password, vault is locked again after idle timeout or absolute lifetime
+// @Tags vault
+// @Accept json
+// @Produce json
+// @Param request body UnlockVaultRequest true "Master password"
+// @Success 200 {object} UnlockVaultResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/vault/unlock [post]
+func (h *VaultHandler) UnlockVault(c echo.Context) error {
+	var req UnlockVaultRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Unlock(ctx, req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestVaultHandler_LockVault(t *testing.T) {
+	mockService := new(mockVaultService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewVaultHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/vault/lock", handler.LockVault)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Lock", mock.Anything, LockVaultRequest{}).Return(&LockVaultResponse{Locked: true}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/vault/lock").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("locked", true)
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
+
+func TestVaultHandler_UnlockVault(t *testing.T) {
+	mockService := new(mockVaultService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewVaultHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.POST("/vault/unlock", handler.UnlockVault)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("Unlock", mock.Anything, UnlockVaultRequest{MasterPassword: "master password"}).
+		Return(&UnlockVaultResponse{Locked: false}, nil)
+	mockService.On("Unlock", mock.Anything, UnlockVaultRequest{MasterPassword: "wrong password"}).
+		Return((*UnlockVaultResponse)(nil), customerr.ErrorWithCode(customerr.INVALID_MASTER_PASSWORD, http.StatusUnauthorized))
+
+	expect := httpexpect.Default(t, server.URL)
+
+	expect.POST("/vault/unlock").
+		WithJSON(UnlockVaultRequest{MasterPassword: "master password"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().
+		HasValue("locked", false)
+
+	expect.POST("/vault/unlock").
+		WithJSON(UnlockVaultRequest{MasterPassword: "wrong password"}).
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().Object().
+		HasValue("message", customerr.INVALID_MASTER_PASSWORD)
+
+	mockService.AssertExpectations(t)
+}
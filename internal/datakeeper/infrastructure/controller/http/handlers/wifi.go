@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/labstack/echo/v4"
+)
+
+// WiFiService store wifi credentials for user
+type WiFiService interface {
+	// Create save wifi credentials
+	Create(ctx context.Context, r CreateWiFiRequest) (*CreateWiFiResponse, error)
+	// Update update wifi credentials
+	Update(ctx context.Context, r UpdateWiFiRequest) (*UpdateWiFiResponse, error)
+	// Delete delete wifi credentials
+	Delete(ctx context.Context, r DeleteWiFiRequest) (*DeleteWiFiResponse, error)
+	// GetAll get all wifi networks for user without passphrases
+	GetAll(ctx context.Context, r GetAllWiFiRequest) (*GetAllWiFiResponse, error)
+	// GetQRCode render wifi credentials as QR code
+	GetQRCode(ctx context.Context, r GetWiFiQRCodeRequest) (*GetWiFiQRCodeResponse, error)
+}
+
+// CreateWiFiRequest Create wifi request
+type CreateWiFiRequest struct {
+	// SSID network name
+	SSID string `json:"ssid"`
+	// Security WPA (default), WEP or nopass
+	Security string `json:"security"`
+	// Passphrase network passphrase
+	Passphrase string `json:"passphrase"`
+	// Hidden network does not broadcast ssid
+	Hidden bool `json:"hidden"`
+}
+
+// CreateWiFiResponse Create wifi response
+type CreateWiFiResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// UpdateWiFiRequest Update wifi request
+type UpdateWiFiRequest struct {
+	UUID       string  `json:"uuid"`
+	SSID       *string `json:"ssid"`
+	Security   *string `json:"security"`
+	Passphrase *string `json:"passphrase"`
+	Hidden     *bool   `json:"hidden"`
+}
+
+// UpdateWiFiResponse Update wifi response
+type UpdateWiFiResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteWiFiRequest Delete wifi request
+type DeleteWiFiRequest struct {
+	UUID string `json:"uuid"`
+}
+
+// DeleteWiFiResponse Delete wifi response
+type DeleteWiFiResponse struct {
+	UUID string `json:"uuid"`
+}
+
+// GetAllWiFiRequest Get all wifi request
+type GetAllWiFiRequest struct{}
+
+// GetAllWiFiResponse Get all wifi response
+type GetAllWiFiResponse struct {
+	Items []GetAllWiFiResponseItem `json:"items"`
+}
+
+// GetAllWiFiResponseItem Get all wifi response item
+type GetAllWiFiResponseItem struct {
+	UUID     string `json:"uuid"`
+	SSID     string `json:"ssid"`
+	Security string `json:"security"`
+	Hidden   bool   `json:"hidden"`
+}
+
+// GetWiFiQRCodeRequest Get wifi qr code request
+type GetWiFiQRCodeRequest struct {
+	UUID string `param:"uuid"`
+	// Size image width and height in pixels (256 by default)
+	Size int `query:"size"`
+}
+
+// GetWiFiQRCodeResponse Get wifi qr code response
+type GetWiFiQRCodeResponse struct {
+	PNG []byte
+}
+
+// WiFiHandler WiFi handler
+type WiFiHandler struct {
+	service      WiFiService
+	ctxConverter ctxConverter
+}
+
+// NewWiFiHandler create new wifi handler
+func NewWiFiHandler(service WiFiService, ctxConverter ctxConverter) *WiFiHandler {
+	return &WiFiHandler{
+		service:      service,
+		ctxConverter: ctxConverter,
+	}
+}
+
+// CreateWiFi create wifi credentials
+// @Summary Create wifi credentials
+// @Description Create new wifi network credentials for the user
+// @Tags wifi
+// @Accept json
+// @Produce json
+// @Param wifi body CreateWiFiRequest true "WiFi request body"
+// @Success 201 {object} CreateWiFiResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wifi [post]
+func (h *WiFiHandler) CreateWiFi(c echo.Context) error {
+	req := new(CreateWiFiRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Create(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// UpdateWiFi update wifi credentials
+// @Summary Update wifi credentials
+// @Description Update existing wifi network credentials for the user
+// @Tags wifi
+// @Accept json
+// @Produce json
+// @Param wifi body UpdateWiFiRequest true "WiFi request body"
+// @Success 200 {object} UpdateWiFiResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wifi [patch]
+func (h *WiFiHandler) UpdateWiFi(c echo.Context) error {
+	req := new(UpdateWiFiRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Update(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteWiFi delete wifi credentials
+// @Summary Delete wifi credentials
+// @Description Delete existing wifi network credentials for the user
+// @Tags wifi
+// @Accept json
+// @Produce json
+// @Param wifi body DeleteWiFiRequest true "WiFi request body"
+// @Success 200 {object} DeleteWiFiResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wifi [delete]
+func (h *WiFiHandler) DeleteWiFi(c echo.Context) error {
+	req := new(DeleteWiFiRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.Delete(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetAllWiFi get all wifi networks for user
+// @Summary Get all wifi networks
+// @Description Get all wifi networks for the user, passphrases are not returned
+// @Tags wifi
+// @Produce json
+// @Success 200 {object} GetAllWiFiResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wifi [get]
+func (h *WiFiHandler) GetAllWiFi(c echo.Context) error {
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetAll(ctx, GetAllWiFiRequest{})
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetWiFiQRCode render wifi credentials as QR code
+// @Summary Get wifi qr code
+// @Description Render WIFI:T:..;S:..;P:..;; payload as PNG QR code for phones
+// @Tags wifi
+// @Produce png
+// @Param uuid path string true "WiFi uuid"
+// @Param size query int false "Image size in pixels"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wifi/{uuid}/qr [get]
+func (h *WiFiHandler) GetWiFiQRCode(c echo.Context) error {
+	req := new(GetWiFiQRCodeRequest)
+	if err := c.Bind(req); err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	ctx, err := h.ctxConverter.ConvertEchoCtxToCtx(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, customerr.ToJson(err.Error()))
+	}
+
+	res, err := h.service.GetQRCode(ctx, *req)
+	if err != nil {
+		return c.JSON(statusCode(err), customerr.ToJson(err.Error()))
+	}
+
+	return c.Blob(http.StatusOK, "image/png", res.PNG)
+}
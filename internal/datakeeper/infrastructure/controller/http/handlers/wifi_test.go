@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWiFiHandler_GetWiFiQRCode(t *testing.T) {
+	mockService := new(mockWiFiService)
+	mockConverter := new(mockCtxConverter)
+	handler := NewWiFiHandler(mockService, mockConverter)
+
+	e := echo.New()
+	e.GET("/wifi/:uuid/qr", handler.GetWiFiQRCode)
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	uuidStr := uuid.NewString()
+	png := []byte("\x89PNG\r\n\x1a\n")
+	mockConverter.On("ConvertEchoCtxToCtx", mock.Anything).Return(nil, nil)
+	mockService.On("GetQRCode", mock.Anything, GetWiFiQRCodeRequest{UUID: uuidStr, Size: 512}).Return(&GetWiFiQRCodeResponse{PNG: png}, nil)
+
+	expect := httpexpect.Default(t, server.URL)
+
+	resp := expect.GET("/wifi/{uuid}/qr", uuidStr).
+		WithQuery("size", 512).
+		Expect().
+		Status(http.StatusOK)
+	resp.Header("Content-Type").IsEqual("image/png")
+	resp.Body().IsEqual(string(png))
+
+	mockService.AssertExpectations(t)
+	mockConverter.AssertExpectations(t)
+}
@@ -1,15 +1,33 @@
 package http
 
 import (
+	"fmt"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/middlewares"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/kek"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres/repo"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/apitoken"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/auth"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/blob"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/card"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/file"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/identity"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/key"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/localauth"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/logpass"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/note"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/otp"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/recovery"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/reencrypt"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/seedphrase"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/session"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/sshkey"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/template"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/vault"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/wifi"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
 	securityservicev1 "github.com/GusevGrishaEm1/protos/gen/go/security_service"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -20,6 +38,7 @@ import (
 	"strconv"
 )
 
+// StartServer start http server, authClient is used only by grpc auth provider
 func StartServer(config config.Config, logger *slog.Logger, authClient *grpc.ClientConn, db *postgres.DB) error {
 	e := echo.New()
 
@@ -42,23 +61,61 @@ func StartServer(config config.Config, logger *slog.Logger, authClient *grpc.Cli
 	loggerMiddleware := middlewares.NewLoggerMiddleware(logger)
 	groupAPI.Use(loggerMiddleware.LoggerMiddleware)
 
+	// cipher suite of new data
+	suite, err := lib.ParseSuite(config.Cipher.Suite)
+	if err != nil {
+		return err
+	}
+	if err = lib.SetDefaultSuite(suite); err != nil {
+		return err
+	}
+
+	// key-encryption key provider
+	kekProvider, err := kek.NewProvider(config.KeyStore, logger)
+	if err != nil {
+		return err
+	}
+	// key store
+	keyStore := key.NewWrappedStore(repo.NewKeyStoreRepo(db), kekProvider)
 	// key service
-	keyService := key.NewKeyService()
+	keyService := key.NewKeyService(config.KDF, config.Vault, key.NewMapCache(), keyStore)
+	// key params repo
+	keyParamsRepo := repo.NewKeyParamsRepo(db)
+	// session service
+	sessionService := session.NewSessionService(repo.NewSessionRepo(db), config.AuthService, config.Session)
+	// auth provider selected in config
+	var authProvider auth.Provider
+	switch config.AuthService.Provider {
+	case auth.ProviderGRPC:
+		authProvider = auth.NewGRPCProvider(securityservicev1.NewAuthClient(authClient))
+	case auth.ProviderLocal:
+		authProvider, err = localauth.NewLocalAuthService(repo.NewUserRepo(db), config.AuthService, config.Session, config.KDF)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown auth provider %q", config.AuthService.Provider)
+	}
 	// auth service
-	authService, err := auth.NewAuthService(securityservicev1.NewAuthClient(authClient), keyService, logger)
+	authService, err := auth.NewAuthService(authProvider, keyService, keyParamsRepo, sessionService, logger)
 	if err != nil {
 		return err
 	}
 	// auth handler
-	authHandler := handlers.NewAuthHandler(authService)
+	authHandler := handlers.NewAuthHandler(authService, sessionService)
 
 	// mapping auth handlers
 	groupAuth := groupAPI.Group("/auth")
 	groupAuth.POST("/login", authHandler.Login)
 	groupAuth.POST("/register", authHandler.Register)
+	groupAuth.POST("/refresh", authHandler.Refresh)
+	groupAuth.POST("/logout", authHandler.Logout)
 
-	// auth middleware
-	authMiddleware := middlewares.NewAuthMiddleware(config)
+	// auth middleware, tokens of revoked sessions are rejected
+	authMiddleware, err := middlewares.NewAuthMiddleware(config, sessionService)
+	if err != nil {
+		return err
+	}
 
 	// data repo
 	dataRepo := repo.NewDataRepo(db)
@@ -93,6 +150,182 @@ func StartServer(config config.Config, logger *slog.Logger, authClient *grpc.Cli
 	groupFile.GET("", fileHandler.GetAllFiles)
 	groupFile.GET("/:uuid", fileHandler.DownloadFile)
 
+	// card service
+	cardService := card.NewCardService(dataRepo, keyService, authService)
+	// card handler
+	cardHandler := handlers.NewCardHandler(cardService, ctxConverter)
+
+	// mapping card handlers
+	groupCard := groupAPI.Group("/cards")
+	groupCard.Use(authMiddleware.AuthMiddleware)
+	groupCard.POST("", cardHandler.CreateCard)
+	groupCard.PATCH("", cardHandler.UpdateCard)
+	groupCard.GET("", cardHandler.GetAllCards)
+	groupCard.DELETE("", cardHandler.DeleteCard)
+
+	// note service
+	noteService := note.NewNoteService(dataRepo, keyService, authService)
+	// note handler
+	noteHandler := handlers.NewNoteHandler(noteService, ctxConverter)
+
+	// mapping note handlers
+	groupNote := groupAPI.Group("/notes")
+	groupNote.Use(authMiddleware.AuthMiddleware)
+	groupNote.POST("", noteHandler.CreateNote)
+	groupNote.PATCH("", noteHandler.UpdateNote)
+	groupNote.GET("", noteHandler.GetAllNotes)
+	groupNote.DELETE("", noteHandler.DeleteNote)
+
+	// otp service
+	otpService := otp.NewOTPService(dataRepo, keyService, authService)
+	// otp handler
+	otpHandler := handlers.NewOTPHandler(otpService, ctxConverter)
+
+	// mapping otp handlers
+	groupOTP := groupAPI.Group("/otp")
+	groupOTP.Use(authMiddleware.AuthMiddleware)
+	groupOTP.POST("", otpHandler.CreateOTP)
+	groupOTP.GET("", otpHandler.GetAllOTP)
+	groupOTP.GET("/:uuid/code", otpHandler.GetOTPCode)
+	groupOTP.DELETE("", otpHandler.DeleteOTP)
+
+	// ssh key service
+	sshKeyService := sshkey.NewSSHKeyService(dataRepo, keyService, authService)
+	// ssh key handler
+	sshKeyHandler := handlers.NewSSHKeyHandler(sshKeyService, ctxConverter)
+
+	// mapping ssh key handlers
+	groupSSHKey := groupAPI.Group("/ssh-keys")
+	groupSSHKey.Use(authMiddleware.AuthMiddleware)
+	groupSSHKey.POST("", sshKeyHandler.ImportSSHKey)
+	groupSSHKey.POST("/generate", sshKeyHandler.GenerateSSHKey)
+	groupSSHKey.GET("", sshKeyHandler.GetAllSSHKeys)
+	groupSSHKey.GET("/:uuid/private", sshKeyHandler.GetSSHPrivateKey)
+	groupSSHKey.DELETE("", sshKeyHandler.DeleteSSHKey)
+
+	// template service
+	templateService := template.NewTemplateService(dataRepo, keyService, authService)
+	// template handler
+	templateHandler := handlers.NewTemplateHandler(templateService, ctxConverter)
+
+	// mapping template handlers
+	groupTemplate := groupAPI.Group("/templates")
+	groupTemplate.Use(authMiddleware.AuthMiddleware)
+	groupTemplate.POST("", templateHandler.CreateTemplate)
+	groupTemplate.PATCH("", templateHandler.UpdateTemplate)
+	groupTemplate.GET("", templateHandler.GetAllTemplates)
+	groupTemplate.DELETE("", templateHandler.DeleteTemplate)
+
+	// mapping record handlers
+	groupRecord := groupAPI.Group("/records")
+	groupRecord.Use(authMiddleware.AuthMiddleware)
+	groupRecord.POST("", templateHandler.CreateRecord)
+	groupRecord.PATCH("", templateHandler.UpdateRecord)
+	groupRecord.GET("", templateHandler.GetAllRecords)
+	groupRecord.DELETE("", templateHandler.DeleteRecord)
+
+	// seed phrase service
+	seedPhraseService := seedphrase.NewSeedPhraseService(dataRepo, keyService, authService)
+	// seed phrase handler
+	seedPhraseHandler := handlers.NewSeedPhraseHandler(seedPhraseService, ctxConverter)
+
+	// mapping seed phrase handlers
+	groupSeedPhrase := groupAPI.Group("/seed-phrases")
+	groupSeedPhrase.Use(authMiddleware.AuthMiddleware)
+	groupSeedPhrase.POST("", seedPhraseHandler.CreateSeedPhrase)
+	groupSeedPhrase.GET("", seedPhraseHandler.GetAllSeedPhrases)
+	groupSeedPhrase.POST("/:uuid/reveal", seedPhraseHandler.RevealSeedPhrase)
+	groupSeedPhrase.DELETE("", seedPhraseHandler.DeleteSeedPhrase)
+
+	// api token service
+	apiTokenService := apitoken.NewAPITokenService(dataRepo, keyService, authService)
+	// api token handler
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService, ctxConverter)
+
+	// mapping api token handlers
+	groupAPIToken := groupAPI.Group("/tokens")
+	groupAPIToken.Use(authMiddleware.AuthMiddleware)
+	groupAPIToken.POST("", apiTokenHandler.CreateAPIToken)
+	groupAPIToken.PATCH("", apiTokenHandler.UpdateAPIToken)
+	groupAPIToken.GET("", apiTokenHandler.GetAllAPITokens)
+	groupAPIToken.DELETE("", apiTokenHandler.DeleteAPIToken)
+
+	// identity service
+	identityService := identity.NewIdentityService(dataRepo, fileService, keyService, authService)
+	// identity handler
+	identityHandler := handlers.NewIdentityHandler(identityService, ctxConverter)
+
+	// mapping identity handlers
+	groupIdentity := groupAPI.Group("/identities")
+	groupIdentity.Use(authMiddleware.AuthMiddleware)
+	groupIdentity.POST("", identityHandler.CreateIdentity)
+	groupIdentity.PATCH("", identityHandler.UpdateIdentity)
+	groupIdentity.GET("", identityHandler.GetAllIdentities)
+	groupIdentity.DELETE("", identityHandler.DeleteIdentity)
+
+	// wifi service
+	wifiService := wifi.NewWiFiService(dataRepo, keyService, authService)
+	// wifi handler
+	wifiHandler := handlers.NewWiFiHandler(wifiService, ctxConverter)
+
+	// mapping wifi handlers
+	groupWiFi := groupAPI.Group("/wifi")
+	groupWiFi.Use(authMiddleware.AuthMiddleware)
+	groupWiFi.POST("", wifiHandler.CreateWiFi)
+	groupWiFi.PATCH("", wifiHandler.UpdateWiFi)
+	groupWiFi.GET("", wifiHandler.GetAllWiFi)
+	groupWiFi.GET("/:uuid/qr", wifiHandler.GetWiFiQRCode)
+	groupWiFi.DELETE("", wifiHandler.DeleteWiFi)
+
+	// recovery kit repo
+	recoveryKitRepo := repo.NewRecoveryKitRepo(db)
+	// re-encryption service
+	reencryptService := reencrypt.NewReencryptService(dataRepo, fileRepo, keyParamsRepo, recoveryKitRepo, db, keyService, authService)
+	// re-encryption handler
+	reencryptHandler := handlers.NewReencryptHandler(reencryptService, ctxConverter)
+
+	// mapping key handlers
+	groupKeys := groupAPI.Group("/keys")
+	groupKeys.Use(authMiddleware.AuthMiddleware)
+	groupKeys.POST("/upgrade", reencryptHandler.UpgradeEncryption)
+	groupKeys.POST("/rotate", reencryptHandler.RotateKey)
+
+	// vault service
+	vaultService := vault.NewVaultService(keyParamsRepo, keyService, authService)
+	// vault handler
+	vaultHandler := handlers.NewVaultHandler(vaultService, ctxConverter)
+
+	// mapping vault handlers
+	groupVault := groupAPI.Group("/vault")
+	groupVault.Use(authMiddleware.AuthMiddleware)
+	groupVault.POST("/lock", vaultHandler.LockVault)
+	groupVault.POST("/unlock", vaultHandler.UnlockVault)
+
+	// recovery service
+	recoveryService := recovery.NewRecoveryService(recoveryKitRepo, keyParamsRepo, db, reencryptService, keyService, authService, sessionService)
+	// recovery handler
+	recoveryHandler := handlers.NewRecoveryHandler(recoveryService, ctxConverter)
+
+	// mapping recovery handlers, key is recovered by user who can not sign in
+	groupRecovery := groupAPI.Group("/recovery")
+	groupRecovery.POST("/kit", recoveryHandler.CreateRecoveryKit, authMiddleware.AuthMiddleware)
+	groupRecovery.POST("/recover", recoveryHandler.RecoverKey)
+
+	if config.ZeroKnowledge.Enabled {
+		// blob service, has no access to user key
+		blobService := blob.NewBlobService(dataRepo, authService)
+		// blob handler
+		blobHandler := handlers.NewBlobHandler(blobService, ctxConverter)
+
+		// mapping blob handlers
+		groupBlob := groupAPI.Group("/blobs")
+		groupBlob.Use(authMiddleware.AuthMiddleware)
+		groupBlob.POST("", blobHandler.CreateBlob)
+		groupBlob.PATCH("", blobHandler.UpdateBlob)
+		groupBlob.GET("", blobHandler.GetAllBlobs)
+		groupBlob.DELETE("", blobHandler.DeleteBlob)
+	}
+
 	logger.Info("server started")
 	err = e.Start(":" + strconv.Itoa(config.Port))
 	if err != nil {
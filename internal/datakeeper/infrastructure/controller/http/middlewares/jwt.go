@@ -1,55 +1,208 @@
 package middlewares
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
 	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/jwks"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// bearerScheme authorization scheme of access token
+	bearerScheme = "Bearer"
+	// tokenCookie cookie with access token, used if there is no Authorization header
+	tokenCookie = "User"
+	// realm protection space in WWW-Authenticate challenge
+	realm = "data-keeper"
+)
+
+// quoteEscaper keeps error description a valid quoted string of WWW-Authenticate header
+var quoteEscaper = strings.NewReplacer(`"`, "'", `\`, "/")
+
+// authError rejected credentials, RFC 6750 error code is empty if request has no credentials
+type authError struct {
+	status  int
+	code    string
+	message string
+}
+
+// RevocationChecker sessions revoked by logout or refresh token reuse
+type RevocationChecker interface {
+	// IsRevoked true if session is revoked
+	IsRevoked(ctx context.Context, familyID string) (bool, error)
+}
+
+// sessionAlgorithm algorithm of access tokens issued by data-keeper itself, it must always be accepted
+const sessionAlgorithm = "HS256"
+
+// hmacAlgorithms algorithms verified with shared JWT key
+var hmacAlgorithms = map[string]bool{"HS256": true, "HS384": true, "HS512": true}
+
+// keySetAlgorithms algorithms verified with public keys of JSON Web Key Set
+var keySetAlgorithms = map[string]bool{"RS256": true, "RS384": true, "RS512": true, "ES256": true, "ES384": true, "ES512": true}
+
+// accessClaims claims of access token, claims of wrong type make token malformed
+type accessClaims struct {
+	// Email user
+	Email string `json:"email"`
+	// SessionID token family of session, empty for tokens issued by auth service
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
 // AuthMiddleware auth middleware
 type AuthMiddleware struct {
-	jwtKey string
-	logger *slog.Logger
+	jwtKey      []byte
+	keys        *jwks.KeySet
+	parser      *jwt.Parser
+	revocations RevocationChecker
+	logger      *slog.Logger
 }
 
-// NewAuthMiddleware creates new auth middleware
-func NewAuthMiddleware(config config.Config) *AuthMiddleware {
-	return &AuthMiddleware{
-		jwtKey: config.AuthService.JWTKey,
-		logger: slog.Default(),
+// NewAuthMiddleware creates new auth middleware, only algorithms from config are accepted and HS256 must be one of them.
+// JSON Web Key Set is loaded if RS or ES algorithm is accepted
+func NewAuthMiddleware(config config.Config, revocations RevocationChecker) (*AuthMiddleware, error) {
+	c := config.AuthService
+	algorithms := c.JWTAlgorithms
+	if len(algorithms) == 0 {
+		algorithms = []string{sessionAlgorithm}
+	}
+	if !slices.Contains(algorithms, sessionAlgorithm) {
+		return nil, fmt.Errorf("JWT algorithm %s must be accepted, access tokens of sessions are signed with it", sessionAlgorithm)
+	}
+
+	m := &AuthMiddleware{
+		jwtKey:      []byte(c.JWTKey),
+		revocations: revocations,
+		logger:      slog.Default(),
+	}
+	for _, alg := range algorithms {
+		switch {
+		case hmacAlgorithms[alg]:
+			if c.JWTKey == "" {
+				return nil, fmt.Errorf("JWT key is required for %s", alg)
+			}
+		case keySetAlgorithms[alg]:
+			if m.keys != nil {
+				continue
+			}
+			keys, err := jwks.NewKeySet(c.JWKS, c.JWKSRefresh, c.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("JSON Web Key Set is required for %s: %w", alg, err)
+			}
+			m.keys = keys
+		default:
+			return nil, fmt.Errorf("unsupported JWT algorithm %q", alg)
+		}
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(algorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(c.JWTLeeway),
+	}
+	if c.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(c.JWTIssuer))
 	}
+	if c.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(c.JWTAudience))
+	}
+	m.parser = jwt.NewParser(options...)
+
+	return m, nil
 }
 
 // AuthMiddleware auth middleware
-// TODO: add refresh token
-// TODO: add logout
-// Get cookie from request and parse token
+// Take token from "Authorization: Bearer" header or, if there is no header, from cookie.
+// Header wins, so client that sends both uses token it passes explicitly; malformed header is not
+// replaced by cookie. Token must be signed with accepted algorithm, have "nbf" and "exp", be unexpired and issued
+// for this service, token of revoked session is rejected.
 // Get email from token and set it in request context
 func (m *AuthMiddleware) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		cookie, err := c.Cookie("User")
-		if err != nil {
-			return c.JSON(http.StatusUnauthorized, customerr.ToJson(err.Error()))
+		tokenString, authErr := bearerToken(c)
+		if authErr != nil {
+			return challenge(c, authErr)
 		}
 
-		token, err := jwt.ParseWithClaims(cookie.Value, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.jwtKey), nil
-		})
+		claims := &accessClaims{}
+		token, err := m.parser.ParseWithClaims(tokenString, claims, m.keyFunc(c.Request().Context()))
 		if err != nil {
-			return c.JSON(http.StatusUnauthorized, customerr.ToJson(err.Error()))
+			return invalidToken(c, err.Error())
+		}
+		// parser checks "nbf" only if it is present
+		if !token.Valid || claims.Email == "" || claims.NotBefore == nil {
+			return invalidToken(c, customerr.INVALID_TOKEN)
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			email := claims["email"].(string)
-			c.Set("User", email)
-		} else {
-			return c.JSON(http.StatusUnauthorized, customerr.INVALID_TOKEN)
+		// tokens issued by auth service have no session
+		if claims.SessionID != "" {
+			revoked, err := m.revocations.IsRevoked(c.Request().Context(), claims.SessionID)
+			if err != nil {
+				m.logger.Error(err.Error())
+				return c.JSON(http.StatusInternalServerError, customerr.ToJson(err.Error()))
+			}
+			if revoked {
+				return invalidToken(c, customerr.TOKEN_REVOKED)
+			}
 		}
 
+		c.Set("User", claims.Email)
+
 		return next(c)
 	}
 }
+
+// keyFunc verification key of token, algorithm is already checked by parser
+func (m *AuthMiddleware) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			return m.jwtKey, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return m.keys.Key(ctx, kid, token.Method.Alg())
+	}
+}
+
+// bearerToken token from Authorization header or cookie
+func bearerToken(c echo.Context) (string, *authError) {
+	if header := c.Request().Header.Get(echo.HeaderAuthorization); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		token = strings.TrimSpace(token)
+		if !ok || !strings.EqualFold(scheme, bearerScheme) || token == "" {
+			return "", &authError{status: http.StatusBadRequest, code: "invalid_request", message: customerr.INVALID_AUTHORIZATION_HEADER}
+		}
+		return token, nil
+	}
+
+	cookie, err := c.Cookie(tokenCookie)
+	if err != nil || cookie.Value == "" {
+		// RFC 6750 3.1: request without credentials gets no error code
+		return "", &authError{status: http.StatusUnauthorized, message: customerr.TOKEN_REQUIRED}
+	}
+	return cookie.Value, nil
+}
+
+// invalidToken reject expired, revoked or malformed token
+func invalidToken(c echo.Context, message string) error {
+	return challenge(c, &authError{status: http.StatusUnauthorized, code: "invalid_token", message: message})
+}
+
+// challenge write error with RFC 6750 WWW-Authenticate header, message is also returned as json
+func challenge(c echo.Context, e *authError) error {
+	value := bearerScheme + ` realm="` + realm + `"`
+	if e.code != "" {
+		value += `, error="` + e.code + `", error_description="` + quoteEscaper.Replace(e.message) + `"`
+	}
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, value)
+	return c.JSON(e.status, customerr.ToJson(e.message))
+}
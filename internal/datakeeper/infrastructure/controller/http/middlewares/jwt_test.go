@@ -1,8 +1,19 @@
 package middlewares
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,83 +21,303 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const secretKey = "your-256-bit-secret"
 
+// revokedSessions revocation checker with fixed revoked sessions
+type revokedSessions map[string]bool
+
+func (r revokedSessions) IsRevoked(ctx context.Context, familyID string) (bool, error) {
+	return r[familyID], nil
+}
+
 func generateTestJWT(t *testing.T, email string) string {
+	return generateSessionJWT(t, email, "")
+}
+
+func generateSessionJWT(t *testing.T, email string, sid string) string {
 	claims := jwt.MapClaims{
 		"email": email,
+		"nbf":   time.Now().Unix(),
 		"exp":   time.Now().Add(time.Hour).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(secretKey))
+	if sid != "" {
+		claims["sid"] = sid
+	}
+	return signTestJWT(t, jwt.SigningMethodHS256, []byte(secretKey), "", claims)
+}
+
+func signTestJWT(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	tokenStr, err := token.SignedString(key)
 	if err != nil {
 		t.Fatal(err)
 	}
 	return tokenStr
 }
 
+// serve run middleware for request with Bearer token, returns status and user set by middleware
+func serve(t *testing.T, authMiddleware *AuthMiddleware, token string) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	var user string
+	handler := authMiddleware.AuthMiddleware(func(c echo.Context) error {
+		user, _ = c.Get("User").(string)
+		return c.String(http.StatusOK, "test")
+	})
+	require.NoError(t, handler(c))
+	if rec.Code == http.StatusUnauthorized {
+		assert.Contains(t, rec.Header().Get(echo.HeaderWWWAuthenticate), `error="invalid_token"`)
+	}
+	return rec.Code, user
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	e := echo.New()
-	authMiddleware := NewAuthMiddleware(config.Config{AuthService: config.AuthService{JWTKey: secretKey}})
-
-	t.Run("Missing Cookie", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-		var handler echo.HandlerFunc = func(c echo.Context) error {
-			return c.String(http.StatusOK, "test")
-		}
+	authMiddleware, err := NewAuthMiddleware(config.Config{AuthService: config.AuthService{JWTKey: secretKey}}, revokedSessions{"revoked": true})
+	require.NoError(t, err)
 
-		hand := authMiddleware.AuthMiddleware(handler)
+	valid := generateTestJWT(t, "test@example.com")
+	other := generateTestJWT(t, "other@example.com")
 
-		if assert.NoError(t, hand(c)) {
-			assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		}
-	})
+	tests := []struct {
+		name      string
+		header    string
+		cookie    string
+		code      int
+		user      string
+		challenge string
+	}{
+		{
+			name:      "Missing Token",
+			code:      http.StatusUnauthorized,
+			challenge: `Bearer realm="data-keeper"`,
+		},
+		{
+			name:      "Invalid Cookie",
+			cookie:    "invalid-token",
+			code:      http.StatusUnauthorized,
+			challenge: `Bearer realm="data-keeper", error="invalid_token", error_description="token is malformed: token contains an invalid number of segments"`,
+		},
+		{
+			name:   "Valid Cookie",
+			cookie: valid,
+			code:   http.StatusOK,
+			user:   "test@example.com",
+		},
+		{
+			name:   "Valid Header",
+			header: "Bearer " + valid,
+			code:   http.StatusOK,
+			user:   "test@example.com",
+		},
+		{
+			name:   "Lower Case Scheme",
+			header: "bearer " + valid,
+			code:   http.StatusOK,
+			user:   "test@example.com",
+		},
+		{
+			name:   "Header Wins Over Cookie",
+			header: "Bearer " + other,
+			cookie: valid,
+			code:   http.StatusOK,
+			user:   "other@example.com",
+		},
+		{
+			name:      "Invalid Header Is Not Replaced By Cookie",
+			header:    "Bearer invalid-token",
+			cookie:    valid,
+			code:      http.StatusUnauthorized,
+			challenge: `Bearer realm="data-keeper", error="invalid_token", error_description="token is malformed: token contains an invalid number of segments"`,
+		},
+		{
+			name:      "Another Scheme",
+			header:    "Basic dXNlcjpwYXNzd29yZA==",
+			code:      http.StatusBadRequest,
+			challenge: `Bearer realm="data-keeper", error="invalid_request", error_description="authorization header must be 'Bearer <token>'"`,
+		},
+		{
+			name:      "Empty Bearer",
+			header:    "Bearer ",
+			cookie:    valid,
+			code:      http.StatusBadRequest,
+			challenge: `Bearer realm="data-keeper", error="invalid_request", error_description="authorization header must be 'Bearer <token>'"`,
+		},
+		{
+			name:   "Active Session",
+			header: "Bearer " + generateSessionJWT(t, "test@example.com", "active"),
+			code:   http.StatusOK,
+			user:   "test@example.com",
+		},
+		{
+			name:      "Revoked Session",
+			header:    "Bearer " + generateSessionJWT(t, "test@example.com", "revoked"),
+			code:      http.StatusUnauthorized,
+			challenge: `Bearer realm="data-keeper", error="invalid_token", error_description="token revoked"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "User", Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
 
-	t.Run("Invalid Token", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		rec := httptest.NewRecorder()
-		cookie := &http.Cookie{
-			Name:  "User",
-			Value: "invalid-token",
-		}
-		req.AddCookie(cookie)
-		c := e.NewContext(req, rec)
+			handler := authMiddleware.AuthMiddleware(func(c echo.Context) error {
+				user, ok := c.Get("User").(string)
+				if !ok {
+					return c.String(http.StatusInternalServerError, "user not found")
+				}
+				assert.Equal(t, tt.user, user)
+				return c.String(http.StatusOK, "test")
+			})
 
-		handler := authMiddleware.AuthMiddleware(func(c echo.Context) error {
-			return c.String(http.StatusOK, "test")
+			if assert.NoError(t, handler(c)) {
+				assert.Equal(t, tt.code, rec.Code)
+				assert.Equal(t, tt.challenge, rec.Header().Get(echo.HeaderWWWAuthenticate))
+			}
 		})
+	}
+}
 
-		if assert.NoError(t, handler(c)) {
-			assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		}
-	})
+func TestAuthMiddlewareClaims(t *testing.T) {
+	authMiddleware, err := NewAuthMiddleware(config.Config{AuthService: config.AuthService{
+		JWTKey:        secretKey,
+		JWTAlgorithms: []string{"HS256"},
+		JWTIssuer:     "auth-service",
+		JWTAudience:   "data-keeper",
+		JWTLeeway:     time.Second,
+	}}, revokedSessions{})
+	require.NoError(t, err)
 
-	t.Run("Valid Token", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		rec := httptest.NewRecorder()
-		tokenStr := generateTestJWT(t, "test@example.com")
-		cookie := &http.Cookie{
-			Name:  "User",
-			Value: tokenStr,
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"email": "test@example.com",
+			"iss":   "auth-service",
+			"aud":   []string{"other", "data-keeper"},
+			"iat":   now.Unix(),
+			"nbf":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		}
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := valid()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
 		}
-		req.AddCookie(cookie)
-		c := e.NewContext(req, rec)
+		return claims
+	}
+	sign := func(claims jwt.MapClaims) string {
+		return signTestJWT(t, jwt.SigningMethodHS256, []byte(secretKey), "", claims)
+	}
 
-		handler := authMiddleware.AuthMiddleware(func(c echo.Context) error {
-			user, ok := c.Get("User").(string)
-			if !ok {
-				return c.String(http.StatusInternalServerError, "user not found")
-			}
-			assert.Equal(t, "test@example.com", user)
-			return c.String(http.StatusOK, "test")
+	code, user := serve(t, authMiddleware, sign(valid()))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "test@example.com", user)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Missing Exp", sign(with("exp", nil))},
+		{"Expired", sign(with("exp", now.Add(-time.Minute).Unix()))},
+		{"Missing Nbf", sign(with("nbf", nil))},
+		{"Not Yet Valid", sign(with("nbf", now.Add(time.Minute).Unix()))},
+		{"Issued In Future", sign(with("iat", now.Add(time.Minute).Unix()))},
+		{"Missing Issuer", sign(with("iss", nil))},
+		{"Wrong Issuer", sign(with("iss", "another-service"))},
+		{"Missing Audience", sign(with("aud", nil))},
+		{"Wrong Audience", sign(with("aud", "another-api"))},
+		{"Missing Email", sign(with("email", nil))},
+		{"Email Is Not String", sign(with("email", 42))},
+		{"Exp Is Not Number", sign(with("exp", "tomorrow"))},
+		{"Session Is Not String", sign(with("sid", []string{"family"}))},
+		{"Not Accepted Algorithm", signTestJWT(t, jwt.SigningMethodHS512, []byte(secretKey), "", valid())},
+		{"Unsigned", signTestJWT(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", valid())},
+		{"Wrong Key", signTestJWT(t, jwt.SigningMethodHS256, []byte("another-secret"), "", valid())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, user := serve(t, authMiddleware, tt.token)
+			assert.Equal(t, http.StatusUnauthorized, code)
+			assert.Empty(t, user)
 		})
+	}
+}
 
-		if assert.NoError(t, handler(c)) {
-			assert.Equal(t, http.StatusOK, rec.Code)
-		}
-	})
+func TestAuthMiddlewareKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	content, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "alg": "RS256", "n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecKey.X.Bytes()), "y": encode(ecKey.Y.Bytes())},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content, 0600))
+
+	authMiddleware, err := NewAuthMiddleware(config.Config{AuthService: config.AuthService{
+		JWTKey:        secretKey,
+		JWTAlgorithms: []string{"HS256", "RS256", "ES256"},
+		JWKS:          path,
+		JWKSRefresh:   time.Hour,
+		Timeout:       time.Second,
+	}}, revokedSessions{})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{"email": "test@example.com", "nbf": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix()}
+
+	code, user := serve(t, authMiddleware, signTestJWT(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", claims))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "test@example.com", user)
+
+	code, _ = serve(t, authMiddleware, signTestJWT(t, jwt.SigningMethodES256, ecKey, "ec-1", claims))
+	assert.Equal(t, http.StatusOK, code)
+
+	// key id must point to key of token algorithm
+	code, _ = serve(t, authMiddleware, signTestJWT(t, jwt.SigningMethodES256, ecKey, "rsa-1", claims))
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(t, authMiddleware, signTestJWT(t, jwt.SigningMethodRS256, rsaKey, "unknown", claims))
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// HMAC signed with public key is not accepted
+	publicKey := x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)
+	code, _ = serve(t, authMiddleware, signTestJWT(t, jwt.SigningMethodHS256, publicKey, "rsa-1", claims))
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestNewAuthMiddleware(t *testing.T) {
+	for _, c := range []config.AuthService{
+		{JWTKey: secretKey, JWTAlgorithms: []string{"none"}},
+		{JWTKey: secretKey, JWTAlgorithms: []string{"PS256"}},
+		{JWTAlgorithms: []string{"HS256"}},
+		{JWTKey: secretKey, JWTAlgorithms: []string{"HS256", "RS256"}},
+		{JWTKey: secretKey, JWTAlgorithms: []string{"HS256", "ES256"}, JWKS: filepath.Join(t.TempDir(), "missing.json")},
+		// access tokens of sessions are signed with HS256
+		{JWTKey: secretKey, JWTAlgorithms: []string{"HS512"}},
+	} {
+		_, err := NewAuthMiddleware(config.Config{AuthService: c}, revokedSessions{})
+		assert.Error(t, err, "config %+v", c)
+	}
 }
@@ -0,0 +1,255 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minReload shortest interval between reloads caused by unknown key id, so forged key ids do not flood source
+	minReload = 30 * time.Second
+	// maxDocumentSize limit of JSON Web Key Set document
+	maxDocumentSize = 1 << 20
+)
+
+// KeySet verification keys of JSON Web Key Set (RFC 7517) loaded from file or URL.
+// Keys are reloaded every refresh interval and when token has unknown key id,
+// so rotated keys are picked up without restart. Failed reload keeps previous keys
+type KeySet struct {
+	source  string
+	client  *http.Client
+	refresh time.Duration
+
+	mu         sync.Mutex
+	keys       map[string]key
+	loadedAt   time.Time
+	attemptAt  time.Time
+	minReload  time.Duration
+	timeSource func() time.Time
+}
+
+// key public key of key set
+type key struct {
+	// alg algorithm fixed by key, empty if key does not fix it
+	alg    string
+	public crypto.PublicKey
+}
+
+type document struct {
+	Keys []jsonKey `json:"keys"`
+}
+
+type jsonKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewKeySet load key set from file path or http(s) URL, source must be readable at start
+func NewKeySet(source string, refresh time.Duration, timeout time.Duration) (*KeySet, error) {
+	if source == "" {
+		return nil, errors.New("JSON Web Key Set source is not configured")
+	}
+	s := &KeySet{
+		source:     source,
+		client:     &http.Client{Timeout: timeout},
+		refresh:    refresh,
+		minReload:  minReload,
+		timeSource: time.Now,
+	}
+	if err := s.reload(context.Background()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Key verification key with key id for algorithm, token without key id is accepted only if set has one key
+func (s *KeySet) Key(ctx context.Context, kid string, alg string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.timeSource()
+	_, found := s.find(kid)
+	stale := s.refresh > 0 && now.Sub(s.loadedAt) >= s.refresh
+	if (stale || !found) && now.Sub(s.attemptAt) >= s.minReload {
+		// previous keys stay in use if source is unavailable
+		_ = s.reload(ctx)
+	}
+
+	k, found := s.find(kid)
+	if !found {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if !k.supports(alg) {
+		return nil, fmt.Errorf("key %q is not for %s", kid, alg)
+	}
+	return k.public, nil
+}
+
+// supports true if key verifies signatures of algorithm
+func (k key) supports(alg string) bool {
+	if k.alg != "" && k.alg != alg {
+		return false
+	}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS")
+	case *ecdsa.PublicKey:
+		return public.Curve == curves[alg]
+	}
+	return false
+}
+
+// curves curve of ECDSA algorithm
+var curves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+func (s *KeySet) find(kid string) (key, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// reload read and parse document, keys are replaced only if document is valid
+func (s *KeySet) reload(ctx context.Context) error {
+	s.attemptAt = s.timeSource()
+	content, err := s.read(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parse(content)
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	s.loadedAt = s.attemptAt
+	return nil
+}
+
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JSON Web Key Set request failed: %s", res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxDocumentSize))
+}
+
+// parse JSON Web Key Set, keys of unsupported type or not for signatures are skipped
+func parse(content []byte) (map[string]key, error) {
+	var doc document
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON Web Key Set: %w", err)
+	}
+
+	keys := make(map[string]key, len(doc.Keys))
+	for _, jk := range doc.Keys {
+		if jk.Use != "" && jk.Use != "sig" {
+			continue
+		}
+		var public crypto.PublicKey
+		var err error
+		switch jk.Kty {
+		case "RSA":
+			public, err = rsaKey(jk)
+		case "EC":
+			public, err = ecKey(jk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", jk.Kid, err)
+		}
+		keys[jk.Kid] = key{alg: jk.Alg, public: public}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JSON Web Key Set has no RSA or EC signature keys")
+	}
+	return keys, nil
+}
+
+func rsaKey(jk jsonKey) (*rsa.PublicKey, error) {
+	n, err := decodeInt(jk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeInt(jk.E)
+	if err != nil {
+		return nil, err
+	}
+	if n.BitLen() < 2048 || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("RSA key must be at least 2048 bits with valid exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func ecKey(jk jsonKey) (*ecdsa.PublicKey, error) {
+	curve, ok := map[string]elliptic.Curve{
+		"P-256": elliptic.P256(),
+		"P-384": elliptic.P384(),
+		"P-521": elliptic.P521(),
+	}[jk.Crv]
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve %q", jk.Crv)
+	}
+	x, err := decodeInt(jk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeInt(jk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decodeInt big-endian unsigned integer in base64url without padding
+func decodeInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
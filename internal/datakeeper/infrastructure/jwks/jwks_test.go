@@ -0,0 +1,182 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func rsaJSONKey(t *testing.T, kid string) (jsonKey, *rsa.PrivateKey) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return jsonKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   encodeInt(private.N),
+		E:   encodeInt(big.NewInt(int64(private.E))),
+	}, private
+}
+
+func ecJSONKey(t *testing.T, kid string) (jsonKey, *ecdsa.PrivateKey) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return jsonKey{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   encodeInt(private.X),
+		Y:   encodeInt(private.Y),
+	}, private
+}
+
+func writeDocument(t *testing.T, path string, keys ...jsonKey) {
+	content, err := json.Marshal(document{Keys: keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, content, 0600))
+}
+
+func TestKeySetFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	rsaKey1, rsaPrivate1 := rsaJSONKey(t, "rsa-1")
+	ecKey1, ecPrivate1 := ecJSONKey(t, "ec-1")
+	// encryption key is skipped
+	encKey, _ := ecJSONKey(t, "enc")
+	encKey.Use = "enc"
+	writeDocument(t, path, rsaKey1, ecKey1, encKey)
+
+	set, err := NewKeySet(path, time.Hour, time.Second)
+	require.NoError(t, err)
+	now := time.Now()
+	set.timeSource = func() time.Time { return now }
+
+	public, err := set.Key(ctx, "rsa-1", "RS256")
+	require.NoError(t, err)
+	assert.Equal(t, &rsaPrivate1.PublicKey, public)
+
+	public, err = set.Key(ctx, "ec-1", "ES256")
+	require.NoError(t, err)
+	assert.True(t, ecPrivate1.PublicKey.Equal(public))
+
+	// algorithm must match key
+	_, err = set.Key(ctx, "rsa-1", "RS384")
+	assert.Error(t, err)
+	_, err = set.Key(ctx, "ec-1", "ES384")
+	assert.Error(t, err)
+	_, err = set.Key(ctx, "ec-1", "RS256")
+	assert.Error(t, err)
+	_, err = set.Key(ctx, "enc", "ES256")
+	assert.Error(t, err)
+	// key id is required for set with several keys
+	_, err = set.Key(ctx, "", "RS256")
+	assert.Error(t, err)
+
+	// rotated key is loaded when token with new key id comes
+	rsaKey2, rsaPrivate2 := rsaJSONKey(t, "rsa-2")
+	writeDocument(t, path, rsaKey2)
+	now = now.Add(minReload)
+	public, err = set.Key(ctx, "rsa-2", "RS256")
+	require.NoError(t, err)
+	assert.Equal(t, &rsaPrivate2.PublicKey, public)
+	_, err = set.Key(ctx, "rsa-1", "RS256")
+	assert.Error(t, err)
+	// the only key of set is used for token without key id
+	_, err = set.Key(ctx, "", "RS256")
+	assert.NoError(t, err)
+
+	// broken document keeps previous keys
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	now = now.Add(2 * time.Hour)
+	_, err = set.Key(ctx, "rsa-2", "RS256")
+	assert.NoError(t, err)
+}
+
+func TestKeySetURL(t *testing.T) {
+	ctx := context.Background()
+	key1, _ := ecJSONKey(t, "ec-1")
+	key2, _ := ecJSONKey(t, "ec-2")
+	var requests atomic.Int32
+	var rotated atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		keys := []jsonKey{key1}
+		if rotated.Load() {
+			keys = []jsonKey{key1, key2}
+		}
+		_ = json.NewEncoder(w).Encode(document{Keys: keys})
+	}))
+	defer server.Close()
+
+	set, err := NewKeySet(server.URL, time.Hour, time.Second)
+	require.NoError(t, err)
+	now := time.Now()
+	set.timeSource = func() time.Time { return now }
+
+	_, err = set.Key(ctx, "ec-1", "ES256")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// unknown key ids do not reload set more often than minReload
+	rotated.Store(true)
+	now = now.Add(minReload)
+	for i := 0; i < 10; i++ {
+		_, err = set.Key(ctx, "forged", "ES256")
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+	_, err = set.Key(ctx, "ec-2", "ES256")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// stale set is reloaded
+	now = now.Add(time.Hour)
+	_, err = set.Key(ctx, "ec-1", "ES256")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestKeySetInvalid(t *testing.T) {
+	_, err := NewKeySet("", time.Hour, time.Second)
+	assert.Error(t, err)
+	_, err = NewKeySet(filepath.Join(t.TempDir(), "missing.json"), time.Hour, time.Second)
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	offCurve, _ := ecJSONKey(t, "ec")
+	offCurve.Y = encodeInt(big.NewInt(1))
+
+	for _, keys := range [][]jsonKey{
+		{},
+		{{Kty: "oct", Kid: "hmac"}},
+		{{Kty: "RSA", Kid: "small", N: encodeInt(small.N), E: encodeInt(big.NewInt(int64(small.E)))}},
+		{offCurve},
+		{{Kty: "EC", Kid: "curve", Crv: "P-192"}},
+	} {
+		writeDocument(t, path, keys...)
+		_, err = NewKeySet(path, time.Hour, time.Second)
+		assert.Error(t, err, "keys %+v", keys)
+	}
+}
@@ -0,0 +1,45 @@
+package kek
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"os"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
+
+// fileKeySize size of key stored in key file
+const fileKeySize = 32
+
+// FileProvider key-encryption key read from local key file
+type FileProvider struct {
+	key string
+}
+
+// NewFileProvider read hex encoded 32 byte key from file, e.g. created by `openssl rand -hex 32`
+func NewFileProvider(path string) (*FileProvider, error) {
+	if path == "" {
+		return nil, errors.New("key-encryption key file is not configured")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(content)))
+	if err != nil || len(key) != fileKeySize {
+		return nil, errors.New("key-encryption key file must contain 32 hex encoded bytes")
+	}
+	return &FileProvider{key: string(key)}, nil
+}
+
+// Wrap encrypt with key from file
+func (p *FileProvider) Wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return lib.Encrypt(p.key, plaintext)
+}
+
+// Unwrap decrypt with key from file
+func (p *FileProvider) Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return lib.Decrypt(p.key, ciphertext)
+}
@@ -0,0 +1,76 @@
+package kek
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+)
+
+// Provider wraps and unwraps user keys with server key-encryption key it holds
+type Provider interface {
+	Wrap(ctx context.Context, plaintext []byte) ([]byte, error)
+	Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+const (
+	// ProviderFile key-encryption key read from local key file
+	ProviderFile = "file"
+	// ProviderTransit key-encryption key held by HTTP transit encryption API
+	ProviderTransit = "transit"
+)
+
+// NewProvider create provider selected in config, wrap and unwrap calls are logged
+func NewProvider(c config.KeyStore, logger *slog.Logger) (Provider, error) {
+	var provider Provider
+	var err error
+	switch c.Provider {
+	case ProviderFile:
+		provider, err = NewFileProvider(c.KeyFile)
+	case ProviderTransit:
+		provider, err = NewTransitProvider(c.TransitAddr, c.TransitKey, c.TransitToken, c.TransitTimeout)
+	default:
+		return nil, fmt.Errorf("unknown key-encryption key provider %q", c.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewLoggingProvider(c.Provider, provider, logger), nil
+}
+
+// LoggingProvider logs calls of wrapped provider, key material is never logged
+type LoggingProvider struct {
+	name     string
+	provider Provider
+	logger   *slog.Logger
+}
+
+func NewLoggingProvider(name string, provider Provider, logger *slog.Logger) *LoggingProvider {
+	return &LoggingProvider{name: name, provider: provider, logger: logger}
+}
+
+// Wrap log and wrap
+func (p *LoggingProvider) Wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	start := time.Now()
+	res, err := p.provider.Wrap(ctx, plaintext)
+	p.log("wrap", start, err)
+	return res, err
+}
+
+// Unwrap log and unwrap
+func (p *LoggingProvider) Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	start := time.Now()
+	res, err := p.provider.Unwrap(ctx, ciphertext)
+	p.log("unwrap", start, err)
+	return res, err
+}
+
+func (p *LoggingProvider) log(operation string, start time.Time, err error) {
+	if err != nil {
+		p.logger.Error(fmt.Sprintf("kek %s [%s] %s: %s", operation, p.name, time.Since(start), err.Error()))
+		return
+	}
+	p.logger.Info(fmt.Sprintf("kek %s [%s] %s", operation, p.name, time.Since(start)))
+}
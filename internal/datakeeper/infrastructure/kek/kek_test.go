@@ -0,0 +1,107 @@
+package kek
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestFileProvider(t *testing.T) {
+	ctx := context.Background()
+	path := writeKeyFile(t, strings.Repeat("ab", 32)+"\n")
+
+	provider, err := NewFileProvider(path)
+	require.NoError(t, err)
+
+	wrapped, err := provider.Wrap(ctx, []byte("user key"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(wrapped), "user key")
+
+	unwrapped, err := provider.Unwrap(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "user key", string(unwrapped))
+
+	other, err := NewFileProvider(writeKeyFile(t, strings.Repeat("cd", 32)))
+	require.NoError(t, err)
+	_, err = other.Unwrap(ctx, wrapped)
+	assert.Error(t, err)
+}
+
+func TestFileProviderInvalidFile(t *testing.T) {
+	_, err := NewFileProvider("")
+	assert.Error(t, err)
+
+	_, err = NewFileProvider(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	_, err = NewFileProvider(writeKeyFile(t, "not hex"))
+	assert.Error(t, err)
+
+	_, err = NewFileProvider(writeKeyFile(t, strings.Repeat("ab", 16)))
+	assert.Error(t, err)
+}
+
+func TestTransitProvider(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(NewTransitMock("transit-key", "token"))
+	defer server.Close()
+
+	provider, err := NewTransitProvider(server.URL, "datakeeper", "token", time.Second)
+	require.NoError(t, err)
+
+	wrapped, err := provider.Wrap(ctx, []byte("user key"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(wrapped), "vault:v1:"))
+
+	unwrapped, err := provider.Unwrap(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "user key", string(unwrapped))
+
+	unauthorized, err := NewTransitProvider(server.URL, "datakeeper", "wrong-token", time.Second)
+	require.NoError(t, err)
+	_, err = unauthorized.Unwrap(ctx, wrapped)
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestNewProvider(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	provider, err := NewProvider(config.KeyStore{
+		Provider: ProviderFile,
+		KeyFile:  writeKeyFile(t, strings.Repeat("ab", 32)),
+	}, logger)
+	require.NoError(t, err)
+
+	wrapped, err := provider.Wrap(ctx, []byte("user key"))
+	require.NoError(t, err)
+	_, err = provider.Unwrap(ctx, wrapped)
+	require.NoError(t, err)
+	_, err = provider.Unwrap(ctx, []byte("corrupted"))
+	assert.Error(t, err)
+
+	assert.Contains(t, logs.String(), "kek wrap [file]")
+	assert.Contains(t, logs.String(), "kek unwrap [file]")
+	assert.Contains(t, logs.String(), "level=ERROR")
+	assert.NotContains(t, logs.String(), "user key")
+
+	_, err = NewProvider(config.KeyStore{Provider: "env"}, logger)
+	assert.Error(t, err)
+}
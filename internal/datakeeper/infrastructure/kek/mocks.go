@@ -0,0 +1,81 @@
+package kek
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
+
+// transitMockPrefix ciphertext prefix of mock, same as transit key version prefix
+const transitMockPrefix = "vault:v1:"
+
+// TransitMock local transit encryption API for tests, serve it with httptest.NewServer
+type TransitMock struct {
+	key   string
+	token string
+}
+
+// NewTransitMock mock holding key, requests without token are rejected
+func NewTransitMock(key string, token string) *TransitMock {
+	return &TransitMock{key: key, token: token}
+}
+
+func (m *TransitMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		m.error(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != m.token {
+		m.error(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	req := transitRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		m.error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res := transitResponse{}
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/transit/encrypt/"):
+		plaintext, err := base64.StdEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			m.error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ciphertext, err := lib.Encrypt(m.key, plaintext)
+		if err != nil {
+			m.error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		res.Data.Ciphertext = transitMockPrefix + base64.StdEncoding.EncodeToString(ciphertext)
+	case strings.HasPrefix(r.URL.Path, "/v1/transit/decrypt/"):
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(req.Ciphertext, transitMockPrefix))
+		if err != nil {
+			m.error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		plaintext, err := lib.Decrypt(m.key, ciphertext)
+		if err != nil {
+			m.error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		res.Data.Plaintext = base64.StdEncoding.EncodeToString(plaintext)
+	default:
+		m.error(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (m *TransitMock) error(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(transitResponse{Errors: []string{message}})
+}
@@ -0,0 +1,100 @@
+package kek
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TransitProvider key-encryption key held by HTTP transit encryption API compatible with HashiCorp Vault transit engine,
+// key never leaves transit service
+type TransitProvider struct {
+	client  *http.Client
+	addr    string
+	keyName string
+	token   string
+}
+
+type transitRequest struct {
+	Plaintext  string `json:"plaintext,omitempty"`
+	Ciphertext string `json:"ciphertext,omitempty"`
+}
+
+type transitResponse struct {
+	Data struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func NewTransitProvider(addr string, keyName string, token string, timeout time.Duration) (*TransitProvider, error) {
+	if addr == "" || keyName == "" {
+		return nil, errors.New("transit address and key name are not configured")
+	}
+	return &TransitProvider{
+		client:  &http.Client{Timeout: timeout},
+		addr:    strings.TrimSuffix(addr, "/"),
+		keyName: keyName,
+		token:   token,
+	}, nil
+}
+
+// Wrap encrypt by transit service
+func (p *TransitProvider) Wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	res, err := p.call(ctx, "encrypt", transitRequest{Plaintext: base64.StdEncoding.EncodeToString(plaintext)})
+	if err != nil {
+		return nil, err
+	}
+	if res.Data.Ciphertext == "" {
+		return nil, errors.New("transit encrypt returned empty ciphertext")
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+// Unwrap decrypt by transit service
+func (p *TransitProvider) Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	res, err := p.call(ctx, "decrypt", transitRequest{Ciphertext: string(ciphertext)})
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+func (p *TransitProvider) call(ctx context.Context, operation string, body transitRequest) (*transitResponse, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/v1/transit/%s/%s", p.addr, operation, p.keyName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("X-Vault-Token", p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	res := &transitResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(res); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transit %s failed with status %d: %s", operation, resp.StatusCode, strings.Join(res.Errors, "; "))
+	}
+	return res, nil
+}
@@ -2,9 +2,11 @@ package repo
 
 import (
 	"context"
+	"errors"
 
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/jackc/pgx/v5"
 )
 
 type FileRepo struct {
@@ -17,9 +19,13 @@ func NewFileRepo(db *postgres.DB) *FileRepo {
 
 func (s *FileRepo) Insert(ctx context.Context, data entity.FileRepo) error {
 	query := `
-	insert into file_repository (uuid, content, created_at, created_by)
-	values ($1, $2, $3, $4)`
-	_, err := s.db.DB.Exec(ctx, query, data.UUID, data.Content, data.CreatedAt, data.CreatedBy)
+	insert into file_repository (uuid, content, chunked, data_key, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6)`
+	content := data.Content
+	if content == nil {
+		content = []byte{}
+	}
+	_, err := s.db.Conn(ctx).Exec(ctx, query, data.UUID, content, data.Chunked, data.DataKey, data.CreatedAt, data.CreatedBy)
 	return err
 }
 
@@ -27,17 +33,77 @@ func (s *FileRepo) Delete(ctx context.Context, user string, uuid string) error {
 	query := `
 	delete from file_repository
     where uuid::text = $1 and created_by = $2`
-	_, err := s.db.DB.Exec(ctx, query, uuid, user)
+	_, err := s.db.Conn(ctx).Exec(ctx, query, uuid, user)
 	return err
 }
 
 func (s *FileRepo) GetByUUID(ctx context.Context, user string, uuid string) (*entity.FileRepo, error) {
 	query := `
-	select uuid, content, created_at, created_by
+	select uuid, content, chunked, data_key, created_at, created_by
 	from file_repository
 	where uuid::text = $1 and created_by = $2`
-	row := s.db.DB.QueryRow(ctx, query, uuid, user)
+	row := s.db.Conn(ctx).QueryRow(ctx, query, uuid, user)
 	data := &entity.FileRepo{}
-	err := row.Scan(&data.UUID, &data.Content, &data.CreatedAt, &data.CreatedBy)
+	err := row.Scan(&data.UUID, &data.Content, &data.Chunked, &data.DataKey, &data.CreatedAt, &data.CreatedBy)
 	return data, err
 }
+
+// InsertChunk insert chunk of file content, chunks are numbered from zero
+func (s *FileRepo) InsertChunk(ctx context.Context, uuid string, seq int, content []byte) error {
+	query := `
+	insert into file_chunk (uuid, seq, content)
+	values ($1, $2, $3)`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, uuid, seq, content)
+	return err
+}
+
+// GetChunk chunk of file content of user, nil if there is no chunk with this number
+func (s *FileRepo) GetChunk(ctx context.Context, user string, uuid string, seq int) ([]byte, error) {
+	query := `
+	select c.content
+	from file_chunk c
+	join file_repository f on f.uuid = c.uuid
+	where c.uuid::text = $1 and f.created_by = $2 and c.seq = $3`
+	var content []byte
+	err := s.db.Conn(ctx).QueryRow(ctx, query, uuid, user, seq).Scan(&content)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (s *FileRepo) Update(ctx context.Context, data entity.FileRepo) error {
+	query := `
+	update file_repository
+	set content = $1, data_key = $2
+	where uuid::text = $3 and created_by = $4`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, data.Content, data.DataKey, data.UUID, data.CreatedBy)
+	return err
+}
+
+// GetUUIDsByUser file uuids of user, files are loaded one by one to keep memory low
+func (s *FileRepo) GetUUIDsByUser(ctx context.Context, user string) ([]string, error) {
+	query := `
+	select uuid::text
+	from file_repository
+	where created_by = $1`
+	rows, err := s.db.Conn(ctx).Query(ctx, query, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		result = append(result, uuid)
+	}
+
+	return result, rows.Err()
+}
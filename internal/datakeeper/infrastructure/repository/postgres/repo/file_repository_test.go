@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileChunks(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := NewFileRepo(repo.db)
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "file_repository"`)
+		assert.NoError(t, err)
+	}()
+
+	newUUID := uuid.New().String()
+	err := fileRepo.Insert(ctx, entity.FileRepo{
+		UUID:      newUUID,
+		Chunked:   true,
+		DataKey:   []byte("test-data-key"),
+		CreatedAt: time.Now(),
+		CreatedBy: "test-user",
+	})
+	assert.NoError(t, err)
+
+	for i, chunk := range []string{"chunk-0", "chunk-1"} {
+		err = fileRepo.InsertChunk(ctx, newUUID, i, []byte(chunk))
+		assert.NoError(t, err)
+	}
+
+	file, err := fileRepo.GetByUUID(ctx, "test-user", newUUID)
+	assert.NoError(t, err)
+	assert.True(t, file.Chunked)
+	assert.Empty(t, file.Content)
+
+	chunk, err := fileRepo.GetChunk(ctx, "test-user", newUUID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk-1", string(chunk))
+
+	// chunk after last one
+	chunk, err = fileRepo.GetChunk(ctx, "test-user", newUUID, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, chunk)
+
+	// chunks of another user are not visible
+	chunk, err = fileRepo.GetChunk(ctx, "another-user", newUUID, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, chunk)
+
+	// chunks are deleted with file
+	err = fileRepo.Delete(ctx, "test-user", newUUID)
+	assert.NoError(t, err)
+	var count int
+	err = repo.db.DB.QueryRow(ctx, `SELECT count(*) FROM "file_chunk"`).Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type KeyStoreRepo struct {
+	db *postgres.DB
+}
+
+// NewKeyStoreRepo creates new repository of wrapped user keys
+func NewKeyStoreRepo(db *postgres.DB) *KeyStoreRepo {
+	return &KeyStoreRepo{db}
+}
+
+// Upsert save wrapped key of user, previous key is replaced. Returns time key was saved
+func (s *KeyStoreRepo) Upsert(ctx context.Context, login string, wrappedKey []byte) (time.Time, error) {
+	query := `
+	insert into user_wrapped_key (login, wrapped_key, updated_at)
+	values ($1, $2, now())
+	on conflict (login) do update set wrapped_key = excluded.wrapped_key, updated_at = excluded.updated_at
+	returning updated_at`
+	var updatedAt time.Time
+	err := s.db.Conn(ctx).QueryRow(ctx, query, login, wrappedKey).Scan(&updatedAt)
+	return updatedAt, err
+}
+
+// GetByLogin wrapped key of user and time it was saved, nil if user has no stored key
+func (s *KeyStoreRepo) GetByLogin(ctx context.Context, login string) ([]byte, time.Time, error) {
+	query := `
+	select wrapped_key, updated_at
+	from user_wrapped_key
+	where login = $1`
+	var wrappedKey []byte
+	var updatedAt time.Time
+	err := s.db.Conn(ctx).QueryRow(ctx, query, login).Scan(&wrappedKey, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, time.Time{}, nil
+	}
+	return wrappedKey, updatedAt, err
+}
+
+// GetUpdatedAt time key of user was saved, zero if user has no stored key
+func (s *KeyStoreRepo) GetUpdatedAt(ctx context.Context, login string) (time.Time, error) {
+	query := `select updated_at from user_wrapped_key where login = $1`
+	var updatedAt time.Time
+	err := s.db.Conn(ctx).QueryRow(ctx, query, login).Scan(&updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return updatedAt, err
+}
+
+// Delete wrapped key of user
+func (s *KeyStoreRepo) Delete(ctx context.Context, login string) error {
+	query := `delete from user_wrapped_key where login = $1`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, login)
+	return err
+}
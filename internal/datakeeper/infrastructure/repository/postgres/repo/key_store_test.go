@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/kek"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/key"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyStoreRepo(t *testing.T) {
+	ctx := context.Background()
+	keyStoreRepo := NewKeyStoreRepo(repo.db)
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "user_wrapped_key"`)
+		assert.NoError(t, err)
+	}()
+
+	_, err := keyStoreRepo.Upsert(ctx, "test-user", []byte("wrapped-key"))
+	assert.NoError(t, err)
+	savedAt, err := keyStoreRepo.Upsert(ctx, "test-user", []byte("rewrapped-key"))
+	assert.NoError(t, err)
+
+	wrappedKey, updatedAt, err := keyStoreRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "rewrapped-key", string(wrappedKey))
+	assert.True(t, savedAt.Equal(updatedAt))
+
+	updatedAt, err = keyStoreRepo.GetUpdatedAt(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.True(t, savedAt.Equal(updatedAt))
+
+	wrappedKey, _, err = keyStoreRepo.GetByLogin(ctx, "nonexistent-user")
+	assert.NoError(t, err)
+	assert.Nil(t, wrappedKey)
+
+	err = keyStoreRepo.Delete(ctx, "test-user")
+	assert.NoError(t, err)
+	wrappedKey, _, err = keyStoreRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Nil(t, wrappedKey)
+	updatedAt, err = keyStoreRepo.GetUpdatedAt(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.True(t, updatedAt.IsZero())
+}
+
+func TestKeyServiceWithPostgresStore(t *testing.T) {
+	ctx := context.Background()
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "user_wrapped_key"`)
+		assert.NoError(t, err)
+	}()
+
+	transit := httptest.NewServer(kek.NewTransitMock("transit-key", "token"))
+	defer transit.Close()
+	provider, err := kek.NewTransitProvider(transit.URL, "datakeeper", "token", time.Second)
+	require.NoError(t, err)
+	store := key.NewWrappedStore(NewKeyStoreRepo(repo.db), provider)
+
+	service := key.NewKeyService(config.KDF{}, config.Vault{}, key.NewMapCache(), store)
+	err = service.SetKeyForUser("test-user", "some_key")
+	require.NoError(t, err)
+
+	// another replica with empty cache
+	service = key.NewKeyService(config.KDF{}, config.Vault{}, key.NewMapCache(), store)
+	userKey, err := service.GetKeyForUser("test-user")
+	require.NoError(t, err)
+	assert.Equal(t, "some_key", userKey)
+
+	// lock on another replica is seen by replica with cached key
+	other := key.NewKeyService(config.KDF{}, config.Vault{}, key.NewMapCache(), store)
+	err = other.Lock("test-user")
+	require.NoError(t, err)
+	_, err = service.GetKeyForUser("test-user")
+	assert.Error(t, err)
+}
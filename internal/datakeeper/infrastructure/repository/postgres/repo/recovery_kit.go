@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type RecoveryKitRepo struct {
+	db *postgres.DB
+}
+
+// NewRecoveryKitRepo creates new recovery kit repository
+func NewRecoveryKitRepo(db *postgres.DB) *RecoveryKitRepo {
+	return &RecoveryKitRepo{db}
+}
+
+// Save recovery kit of user, previous kit is replaced
+func (s *RecoveryKitRepo) Save(ctx context.Context, kit entity.RecoveryKit) error {
+	query := `
+	insert into recovery_kit (login, kit_id, threshold, shares, check_value, created_at)
+	values ($1, $2, $3, $4, $5, $6)
+	on conflict (login) do update
+	set kit_id = excluded.kit_id, threshold = excluded.threshold, shares = excluded.shares,
+	    check_value = excluded.check_value, created_at = excluded.created_at`
+	_, err := s.db.Conn(ctx).Exec(ctx, query,
+		kit.Login, kit.KitID, kit.Threshold, kit.Shares, kit.CheckValue, kit.CreatedAt)
+	return err
+}
+
+// GetByLogin recovery kit of user, nil if user has no kit
+func (s *RecoveryKitRepo) GetByLogin(ctx context.Context, login string) (*entity.RecoveryKit, error) {
+	query := `
+	select login, kit_id, threshold, shares, check_value, created_at
+	from recovery_kit
+	where login = $1`
+	row := s.db.Conn(ctx).QueryRow(ctx, query, login)
+	kit := &entity.RecoveryKit{}
+	err := row.Scan(&kit.Login, &kit.KitID, &kit.Threshold, &kit.Shares, &kit.CheckValue, &kit.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kit, nil
+}
+
+// Delete recovery kit of user
+func (s *RecoveryKitRepo) Delete(ctx context.Context, login string) error {
+	query := `delete from recovery_kit where login = $1`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, login)
+	return err
+}
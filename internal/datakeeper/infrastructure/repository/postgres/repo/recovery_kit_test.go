@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryKitRepo(t *testing.T) {
+	ctx := context.Background()
+	kitRepo := NewRecoveryKitRepo(repo.db)
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "recovery_kit"`)
+		assert.NoError(t, err)
+	}()
+
+	kit := entity.RecoveryKit{
+		Login:      "test-user",
+		KitID:      []byte("kit-id-1"),
+		Threshold:  2,
+		Shares:     3,
+		CheckValue: []byte("test-check-value"),
+		CreatedAt:  time.Now(),
+	}
+	err := kitRepo.Save(ctx, kit)
+	assert.NoError(t, err)
+
+	// new kit replaces previous one
+	kit.KitID = []byte("kit-id-2")
+	kit.Threshold = 3
+	kit.Shares = 5
+	err = kitRepo.Save(ctx, kit)
+	assert.NoError(t, err)
+
+	fromDB, err := kitRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, kit.KitID, fromDB.KitID)
+	assert.Equal(t, 3, fromDB.Threshold)
+	assert.Equal(t, 5, fromDB.Shares)
+	assert.Equal(t, kit.CheckValue, fromDB.CheckValue)
+
+	err = kitRepo.Delete(ctx, "test-user")
+	assert.NoError(t, err)
+	fromDB, err = kitRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Nil(t, fromDB)
+}
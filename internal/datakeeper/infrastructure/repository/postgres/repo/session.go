@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionRepo struct {
+	db *postgres.DB
+}
+
+// NewSessionRepo creates new session repository
+func NewSessionRepo(db *postgres.DB) *SessionRepo {
+	return &SessionRepo{db}
+}
+
+// CreateFamily insert new token family
+func (s *SessionRepo) CreateFamily(ctx context.Context, family entity.TokenFamily) error {
+	query := `
+	insert into token_family (id, login, created_at)
+	values ($1, $2, $3)`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, family.ID, family.Login, family.CreatedAt)
+	return err
+}
+
+// IsFamilyRevoked true if family is revoked or does not exist
+func (s *SessionRepo) IsFamilyRevoked(ctx context.Context, id string) (bool, error) {
+	query := `select revoked_at is not null from token_family where id = $1`
+	var revoked bool
+	err := s.db.Conn(ctx).QueryRow(ctx, query, id).Scan(&revoked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+// RevokeFamily revoke token family, revoked family keeps first revocation time
+func (s *SessionRepo) RevokeFamily(ctx context.Context, id string, revokedAt time.Time) error {
+	query := `update token_family set revoked_at = coalesce(revoked_at, $2) where id = $1`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, id, revokedAt)
+	return err
+}
+
+// InsertToken insert refresh token
+func (s *SessionRepo) InsertToken(ctx context.Context, token entity.RefreshToken) error {
+	query := `
+	insert into refresh_token (hash, family_id, login, expires_at, created_at)
+	values ($1, $2, $3, $4, $5)`
+	_, err := s.db.Conn(ctx).Exec(ctx, query,
+		token.Hash, token.FamilyID, token.Login, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+// GetToken refresh token by hash, nil if token does not exist
+func (s *SessionRepo) GetToken(ctx context.Context, hash []byte) (*entity.RefreshToken, error) {
+	query := `
+	select hash, family_id, login, expires_at, created_at, used_at
+	from refresh_token
+	where hash = $1`
+	row := s.db.Conn(ctx).QueryRow(ctx, query, hash)
+	token := &entity.RefreshToken{}
+	err := row.Scan(&token.Hash, &token.FamilyID, &token.Login, &token.ExpiresAt, &token.CreatedAt, &token.UsedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkTokenUsed mark refresh token as used, false if token was already used.
+// Check and update are one statement, so concurrent refreshes with one token can not both succeed
+func (s *SessionRepo) MarkTokenUsed(ctx context.Context, hash []byte, usedAt time.Time) (bool, error) {
+	query := `update refresh_token set used_at = $2 where hash = $1 and used_at is null`
+	tag, err := s.db.Conn(ctx).Exec(ctx, query, hash, usedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
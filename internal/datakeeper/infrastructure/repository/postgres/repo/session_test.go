@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRepo(t *testing.T) {
+	ctx := context.Background()
+	sessionRepo := NewSessionRepo(repo.db)
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "token_family"`)
+		assert.NoError(t, err)
+	}()
+
+	family := entity.TokenFamily{ID: "family-1", Login: "test-user", CreatedAt: time.Now()}
+	err := sessionRepo.CreateFamily(ctx, family)
+	assert.NoError(t, err)
+
+	token := entity.RefreshToken{
+		Hash:      []byte("test-token-hash"),
+		FamilyID:  family.ID,
+		Login:     family.Login,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+	err = sessionRepo.InsertToken(ctx, token)
+	assert.NoError(t, err)
+
+	fromDB, err := sessionRepo.GetToken(ctx, token.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, family.ID, fromDB.FamilyID)
+	assert.Equal(t, "test-user", fromDB.Login)
+	assert.Nil(t, fromDB.UsedAt)
+
+	// token is used only once
+	used, err := sessionRepo.MarkTokenUsed(ctx, token.Hash, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, used)
+	used, err = sessionRepo.MarkTokenUsed(ctx, token.Hash, time.Now())
+	assert.NoError(t, err)
+	assert.False(t, used)
+
+	fromDB, err = sessionRepo.GetToken(ctx, []byte("unknown"))
+	assert.NoError(t, err)
+	assert.Nil(t, fromDB)
+
+	revoked, err := sessionRepo.IsFamilyRevoked(ctx, family.ID)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	err = sessionRepo.RevokeFamily(ctx, family.ID, time.Now())
+	assert.NoError(t, err)
+	revoked, err = sessionRepo.IsFamilyRevoked(ctx, family.ID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	// unknown family is treated as revoked
+	revoked, err = sessionRepo.IsFamilyRevoked(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
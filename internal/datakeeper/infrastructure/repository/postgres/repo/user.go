@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type UserRepo struct {
+	db *postgres.DB
+}
+
+// NewUserRepo creates new repository of local users
+func NewUserRepo(db *postgres.DB) *UserRepo {
+	return &UserRepo{db}
+}
+
+// Insert user, false if login is taken
+func (s *UserRepo) Insert(ctx context.Context, user entity.User) (bool, error) {
+	query := `
+	insert into users (login, password_hash, created_at)
+	values ($1, $2, $3)
+	on conflict (login) do nothing`
+	tag, err := s.db.Conn(ctx).Exec(ctx, query, user.Login, user.PasswordHash, user.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetByLogin user by login, nil if user does not exist
+func (s *UserRepo) GetByLogin(ctx context.Context, login string) (*entity.User, error) {
+	query := `select login, password_hash, created_at from users where login = $1`
+	row := s.db.Conn(ctx).QueryRow(ctx, query, login)
+	user := &entity.User{}
+	err := row.Scan(&user.Login, &user.PasswordHash, &user.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type KeyParamsRepo struct {
+	db *postgres.DB
+}
+
+// NewKeyParamsRepo creates new key parameters repository
+func NewKeyParamsRepo(db *postgres.DB) *KeyParamsRepo {
+	return &KeyParamsRepo{db}
+}
+
+// Insert key parameters of user
+func (s *KeyParamsRepo) Insert(ctx context.Context, params entity.KeyParams) error {
+	query := `
+	insert into user_key (login, salt, time, memory, threads, check_value, created_at)
+	values ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := s.db.Conn(ctx).Exec(ctx, query,
+		params.Login, params.Salt, params.Time, params.Memory, params.Threads, params.CheckValue, params.CreatedAt)
+	return err
+}
+
+// Save key parameters of user, previous parameters are replaced
+func (s *KeyParamsRepo) Save(ctx context.Context, params entity.KeyParams) error {
+	query := `
+	insert into user_key (login, salt, time, memory, threads, check_value, created_at)
+	values ($1, $2, $3, $4, $5, $6, $7)
+	on conflict (login) do update
+	set salt = excluded.salt, time = excluded.time, memory = excluded.memory, threads = excluded.threads,
+	    check_value = excluded.check_value, created_at = excluded.created_at`
+	_, err := s.db.Conn(ctx).Exec(ctx, query,
+		params.Login, params.Salt, params.Time, params.Memory, params.Threads, params.CheckValue, params.CreatedAt)
+	return err
+}
+
+// Delete key parameters of user
+func (s *KeyParamsRepo) Delete(ctx context.Context, login string) error {
+	query := `delete from user_key where login = $1`
+	_, err := s.db.Conn(ctx).Exec(ctx, query, login)
+	return err
+}
+
+// GetByLogin key parameters of user, nil if user has no master password
+func (s *KeyParamsRepo) GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error) {
+	query := `
+	select login, salt, time, memory, threads, check_value, created_at
+	from user_key
+	where login = $1`
+	row := s.db.Conn(ctx).QueryRow(ctx, query, login)
+	params := &entity.KeyParams{}
+	err := row.Scan(&params.Login, &params.Salt, &params.Time, &params.Memory, &params.Threads, &params.CheckValue, &params.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
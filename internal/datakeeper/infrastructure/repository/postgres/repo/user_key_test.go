@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyParamsRepo(t *testing.T) {
+	ctx := context.Background()
+	keyRepo := NewKeyParamsRepo(repo.db)
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "user_key"`)
+		assert.NoError(t, err)
+	}()
+
+	params := entity.KeyParams{
+		Login:      "test-user",
+		Salt:       []byte("test-salt"),
+		Time:       3,
+		Memory:     64 * 1024,
+		Threads:    4,
+		CheckValue: []byte("test-check-value"),
+		CreatedAt:  time.Now(),
+	}
+	err := keyRepo.Insert(ctx, params)
+	assert.NoError(t, err)
+
+	fromDB, err := keyRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, params.Salt, fromDB.Salt)
+	assert.Equal(t, params.Time, fromDB.Time)
+	assert.Equal(t, params.Memory, fromDB.Memory)
+	assert.Equal(t, params.Threads, fromDB.Threads)
+	assert.Equal(t, params.CheckValue, fromDB.CheckValue)
+
+	// saved parameters replace previous ones
+	params.Salt = []byte("new-salt")
+	params.CheckValue = []byte("new-check-value")
+	err = keyRepo.Save(ctx, params)
+	assert.NoError(t, err)
+	fromDB, err = keyRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, params.Salt, fromDB.Salt)
+	assert.Equal(t, params.CheckValue, fromDB.CheckValue)
+
+	fromDB, err = keyRepo.GetByLogin(ctx, "nonexistent-user")
+	assert.NoError(t, err)
+	assert.Nil(t, fromDB)
+
+	err = keyRepo.Delete(ctx, "test-user")
+	assert.NoError(t, err)
+	fromDB, err = keyRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Nil(t, fromDB)
+}
@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRepo(t *testing.T) {
+	ctx := context.Background()
+	userRepo := NewUserRepo(repo.db)
+	defer func() {
+		_, err := repo.db.DB.Exec(ctx, `DELETE FROM "users"`)
+		assert.NoError(t, err)
+	}()
+
+	user := entity.User{Login: "test-user", PasswordHash: "$argon2id$hash", CreatedAt: time.Now()}
+	created, err := userRepo.Insert(ctx, user)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// login is taken
+	created, err = userRepo.Insert(ctx, entity.User{Login: "test-user", PasswordHash: "another", CreatedAt: time.Now()})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	fromDB, err := userRepo.GetByLogin(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, user.PasswordHash, fromDB.PasswordHash)
+
+	fromDB, err = userRepo.GetByLogin(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, fromDB)
+}
@@ -19,10 +19,10 @@ func NewDataRepo(db *postgres.DB) *DataRepo {
 // Insert insert new data for user
 func (s *DataRepo) Insert(ctx context.Context, data entity.Data) error {
 	query := `
-	insert into user_data (uuid, content, content_type, created_at, created_by) 
-	values ($1, $2, $3, $4, $5)
+	insert into user_data (uuid, content, data_key, content_type, metadata, created_at, created_by) 
+	values ($1, $2, $3, $4, $5, $6, $7)
 	`
-	_, err := s.db.DB.Exec(ctx, query, data.UUID, data.Content, data.ContentType, data.CreatedAt, data.CreatedBy)
+	_, err := s.db.Conn(ctx).Exec(ctx, query, data.UUID, data.Content, data.DataKey, data.ContentType, data.Metadata, data.CreatedAt, data.CreatedBy)
 	if err != nil {
 		return err
 	}
@@ -32,7 +32,7 @@ func (s *DataRepo) Insert(ctx context.Context, data entity.Data) error {
 // Delete delete data for user
 func (s *DataRepo) Delete(ctx context.Context, user string, uuid string) error {
 	query := `delete from user_data where uuid::text = $1 and created_by = $2`
-	_, err := s.db.DB.Exec(ctx, query, uuid, user)
+	_, err := s.db.Conn(ctx).Exec(ctx, query, uuid, user)
 	return err
 }
 
@@ -40,20 +40,20 @@ func (s *DataRepo) Delete(ctx context.Context, user string, uuid string) error {
 func (s *DataRepo) Update(ctx context.Context, data entity.Data) error {
 	query := `
 	update user_data 
-	set content = $1
-	where uuid::text = $2 and created_by = $3 and content_type = $4
+	set content = $1, data_key = $2, metadata = $3
+	where uuid::text = $4 and created_by = $5 and content_type = $6
 	`
-	_, err := s.db.DB.Exec(ctx, query, data.Content, data.UUID, data.CreatedBy, data.ContentType)
+	_, err := s.db.Conn(ctx).Exec(ctx, query, data.Content, data.DataKey, data.Metadata, data.UUID, data.CreatedBy, data.ContentType)
 	return err
 }
 
 // GetByUser Get data by user and content type
 func (s *DataRepo) GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error) {
 	query := `
-	select uuid, content, content_type
+	select uuid, content, data_key, content_type, metadata, created_by
 	from user_data
 	where created_by = $1 and content_type = $2`
-	rows, err := s.db.DB.Query(ctx, query, user, contentType)
+	rows, err := s.db.Conn(ctx).Query(ctx, query, user, contentType)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +62,7 @@ func (s *DataRepo) GetByUser(ctx context.Context, user string, contentType entit
 	var result []*entity.Data
 	for rows.Next() {
 		var data entity.Data
-		err := rows.Scan(&data.UUID, &data.Content, &data.ContentType)
+		err := rows.Scan(&data.UUID, &data.Content, &data.DataKey, &data.ContentType, &data.Metadata, &data.CreatedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -75,11 +75,36 @@ func (s *DataRepo) GetByUser(ctx context.Context, user string, contentType entit
 // GetByUUID Get data by user and content type and uuid
 func (s *DataRepo) GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error) {
 	query := `
-	select uuid, content, content_type, created_by
+	select uuid, content, data_key, content_type, metadata, created_by
 	from user_data
     where created_by = $1 and uuid::text = $2`
-	row := s.db.DB.QueryRow(ctx, query, user, uuid)
+	row := s.db.Conn(ctx).QueryRow(ctx, query, user, uuid)
 	data := &entity.Data{}
-	err := row.Scan(&data.UUID, &data.Content, &data.ContentType, &data.CreatedBy)
+	err := row.Scan(&data.UUID, &data.Content, &data.DataKey, &data.ContentType, &data.Metadata, &data.CreatedBy)
 	return data, err
 }
+
+// GetAllByUser Get data of all content types by user
+func (s *DataRepo) GetAllByUser(ctx context.Context, user string) ([]*entity.Data, error) {
+	query := `
+	select uuid, content, data_key, content_type, metadata, created_by
+	from user_data
+	where created_by = $1`
+	rows, err := s.db.Conn(ctx).Query(ctx, query, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*entity.Data
+	for rows.Next() {
+		var data entity.Data
+		err := rows.Scan(&data.UUID, &data.Content, &data.DataKey, &data.ContentType, &data.Metadata, &data.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &data)
+	}
+
+	return result, rows.Err()
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/repository/postgres"
@@ -213,4 +214,80 @@ func TestGetAllByUser(t *testing.T) {
 	assert.Equal(t, newUUID, userData[0].UUID)
 	assert.Equal(t, "test-content", string(userData[0].Content))
 	assert.Equal(t, entity.ContentType("text"), userData[0].ContentType)
+	assert.Equal(t, "test-user", userData[0].CreatedBy)
+}
+
+func TestGetAllByUserAnyContentType(t *testing.T) {
+	ctx := context.Background()
+	defer clearTable(ctx)
+
+	for _, contentType := range []entity.ContentType{entity.LogPass, entity.Note} {
+		err := repo.Insert(ctx, entity.Data{
+			UUID:        uuid.New().String(),
+			Content:     []byte("test-content"),
+			ContentType: contentType,
+			CreatedAt:   time.Now(),
+			CreatedBy:   "test-user",
+		})
+		assert.NoError(t, err)
+	}
+
+	userData, err := repo.GetAllByUser(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(userData))
+	assert.Equal(t, "test-user", userData[0].CreatedBy)
+}
+
+func TestDataKey(t *testing.T) {
+	ctx := context.Background()
+	defer clearTable(ctx)
+
+	newUUID := uuid.New().String()
+	data := entity.Data{
+		UUID:        newUUID,
+		Content:     []byte("test-content"),
+		DataKey:     []byte("test-data-key"),
+		ContentType: "text",
+		CreatedAt:   time.Now(),
+		CreatedBy:   "test-user",
+	}
+	err := repo.Insert(ctx, data)
+	assert.NoError(t, err)
+
+	fromDB, err := repo.GetByUUID(ctx, "test-user", newUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-data-key", string(fromDB.DataKey))
+
+	fromDB.DataKey = []byte("rewrapped-data-key")
+	err = repo.Update(ctx, *fromDB)
+	assert.NoError(t, err)
+
+	userData, err := repo.GetByUser(ctx, "test-user", "text")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(userData))
+	assert.Equal(t, "rewrapped-data-key", string(userData[0].DataKey))
+	assert.Equal(t, "test-content", string(userData[0].Content))
+}
+
+func TestWithTxRollback(t *testing.T) {
+	ctx := context.Background()
+	defer clearTable(ctx)
+
+	newUUID := uuid.New().String()
+	err := repo.db.WithTx(ctx, func(ctx context.Context) error {
+		err := repo.Insert(ctx, entity.Data{
+			UUID:        newUUID,
+			Content:     []byte("test-content"),
+			ContentType: "text",
+			CreatedAt:   time.Now(),
+			CreatedBy:   "test-user",
+		})
+		assert.NoError(t, err)
+		return errors.New("interrupted")
+	})
+	assert.Error(t, err)
+
+	userData, err := repo.GetAllByUser(ctx, "test-user")
+	assert.NoError(t, err)
+	assert.Empty(t, userData)
 }
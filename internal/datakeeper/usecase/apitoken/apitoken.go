@@ -0,0 +1,250 @@
+package apitoken
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type apiTokenContent struct {
+	Name      string     `json:"name"`
+	Token     string     `json:"token"`
+	Issuer    string     `json:"issuer"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewAPITokenService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Create api token
+func (s *Service) Create(ctx context.Context, r handlers.CreateAPITokenRequest) (*handlers.CreateAPITokenResponse, error) {
+	if r.Token == "" {
+		return nil, customerr.ErrorWithCode(customerr.API_TOKEN_REQUIRED, http.StatusBadRequest)
+	}
+
+	jsonData, err := json.Marshal(&apiTokenContent{
+		Name:      r.Name,
+		Token:     r.Token,
+		Issuer:    r.Issuer,
+		Scopes:    r.Scopes,
+		ExpiresAt: r.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.APIToken,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateAPITokenResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Update update api token
+func (s *Service) Update(ctx context.Context, r handlers.UpdateAPITokenRequest) (*handlers.UpdateAPITokenResponse, error) {
+	if r.Token != nil && *r.Token == "" {
+		return nil, customerr.ErrorWithCode(customerr.API_TOKEN_REQUIRED, http.StatusBadRequest)
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	content := apiTokenContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setContentToUpdate(r, &content)
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Update(ctx, *fromDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateAPITokenResponse{UUID: fromDB.UUID}, nil
+}
+
+func (s *Service) setContentToUpdate(r handlers.UpdateAPITokenRequest, content *apiTokenContent) {
+	if r.Name != nil {
+		content.Name = *r.Name
+	}
+	if r.Token != nil {
+		content.Token = *r.Token
+	}
+	if r.Issuer != nil {
+		content.Issuer = *r.Issuer
+	}
+	if r.Scopes != nil {
+		content.Scopes = r.Scopes
+	}
+	if r.ExpiresAt != nil {
+		content.ExpiresAt = r.ExpiresAt
+	}
+}
+
+// Delete delete api token
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteAPITokenRequest) (*handlers.DeleteAPITokenResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.load(ctx, user, r.UUID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteAPITokenResponse{UUID: r.UUID}, nil
+}
+
+// load api token without decrypting it, row of another content type is not found
+func (s *Service) load(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fromDB.ContentType != entity.APIToken {
+		return nil, customerr.ErrorWithCode(customerr.API_TOKEN_NOT_FOUND, http.StatusNotFound)
+	}
+	return fromDB, nil
+}
+
+// GetAll get api tokens sorted by expiry, soonest first and tokens without expiry last
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllAPITokensRequest) (*handlers.GetAllAPITokensResponse, error) {
+	if r.ExpiringWithinDays < 0 {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_EXPIRY_FILTER, http.StatusBadRequest)
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.APIToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deadline := now.AddDate(0, 0, r.ExpiringWithinDays)
+
+	items := make([]handlers.GetAllAPITokensResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		item := handlers.GetAllAPITokensResponseItem{}
+		err = json.Unmarshal(jsonDecrypted, &item)
+		if err != nil {
+			return nil, err
+		}
+		item.UUID = v.UUID
+		if r.ExpiringWithinDays > 0 && (item.ExpiresAt == nil || item.ExpiresAt.After(deadline)) {
+			continue
+		}
+		item.Expired = item.ExpiresAt != nil && !item.ExpiresAt.After(now)
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ExpiresAt == nil || items[j].ExpiresAt == nil {
+			return items[j].ExpiresAt == nil && items[i].ExpiresAt != nil
+		}
+		return items[i].ExpiresAt.Before(*items[j].ExpiresAt)
+	})
+
+	return &handlers.GetAllAPITokensResponse{Items: items}, nil
+}
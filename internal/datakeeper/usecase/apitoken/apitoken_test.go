@@ -0,0 +1,184 @@
+package apitoken
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenService_Create(t *testing.T) {
+	mockRepo := new(MockAPITokenRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewAPITokenService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	expiresAt := time.Now().AddDate(0, 1, 0)
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, handlers.CreateAPITokenRequest{
+		Name:      "ci",
+		Token:     "ghp_123",
+		Issuer:    "github",
+		Scopes:    []string{"repo"},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+
+	_, err = service.Create(ctx, handlers.CreateAPITokenRequest{Name: "ci"})
+	assert.EqualError(t, err, customerr.API_TOKEN_REQUIRED)
+
+	mockRepo.AssertNumberOfCalls(t, "Insert", 1)
+}
+
+func TestAPITokenService_GetAll(t *testing.T) {
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	now := time.Now()
+	expired := now.AddDate(0, 0, -1)
+	soon := now.AddDate(0, 0, 3)
+	later := now.AddDate(0, 0, 60)
+
+	newData := func() []*entity.Data {
+		tokens := []apiTokenContent{
+			{Name: "later", Token: "t1", ExpiresAt: &later},
+			{Name: "forever", Token: "t2"},
+			{Name: "soon", Token: "t3", ExpiresAt: &soon},
+			{Name: "expired", Token: "t4", ExpiresAt: &expired},
+		}
+		data := make([]*entity.Data, 0, len(tokens))
+		for _, token := range tokens {
+			jsonContent, _ := json.Marshal(&token)
+			encryptedContent, _ := lib.Encrypt(key, jsonContent)
+			data = append(data, &entity.Data{UUID: uuid.New().String(), Content: encryptedContent, ContentType: entity.APIToken})
+		}
+		return data
+	}
+
+	names := func(items []handlers.GetAllAPITokensResponseItem) []string {
+		result := make([]string, 0, len(items))
+		for _, item := range items {
+			result = append(result, item.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		request handlers.GetAllAPITokensRequest
+		want    []string
+	}{
+		{name: "all sorted by expiry", request: handlers.GetAllAPITokensRequest{}, want: []string{"expired", "soon", "later", "forever"}},
+		{name: "expiring within 7 days", request: handlers.GetAllAPITokensRequest{ExpiringWithinDays: 7}, want: []string{"expired", "soon"}},
+		{name: "expiring within 90 days", request: handlers.GetAllAPITokensRequest{ExpiringWithinDays: 90}, want: []string{"expired", "soon", "later"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAPITokenRepo)
+			mockKeyService := new(MockKeyService)
+			mockAuthService := new(MockAuthService)
+			service := NewAPITokenService(mockRepo, mockKeyService, mockAuthService)
+
+			mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+			mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+			mockRepo.On("GetByUser", mock.Anything, user, entity.APIToken).Return(newData(), nil)
+
+			response, err := service.GetAll(ctx, tt.request)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(response.Items))
+			assert.True(t, response.Items[0].Expired)
+			assert.False(t, response.Items[1].Expired)
+		})
+	}
+}
+
+func TestAPITokenService_GetAllNegativeFilter(t *testing.T) {
+	service := NewAPITokenService(new(MockAPITokenRepo), new(MockKeyService), new(MockAuthService))
+
+	_, err := service.GetAll(context.Background(), handlers.GetAllAPITokensRequest{ExpiringWithinDays: -1})
+	assert.EqualError(t, err, customerr.INVALID_EXPIRY_FILTER)
+}
+
+func TestAPITokenService_UpdateAndDelete(t *testing.T) {
+	mockRepo := new(MockAPITokenRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewAPITokenService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	data := entity.Data{UUID: uuidStr, ContentType: entity.APIToken, CreatedBy: user}
+	jsonContent, _ := json.Marshal(&apiTokenContent{Name: "ci", Token: "ghp_123"})
+	data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+
+	var updated entity.Data
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("entity.Data")).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(entity.Data)
+	}).Return(nil)
+	mockRepo.On("Delete", mock.Anything, user, uuidStr).Return(nil)
+
+	token := "ghp_456"
+	_, err := service.Update(ctx, handlers.UpdateAPITokenRequest{UUID: uuidStr, Token: &token})
+	require.NoError(t, err)
+	jsonDecrypted, err := lib.OpenEnvelope(key, updated.DataKey, updated.Content, updated.AssociatedData())
+	require.NoError(t, err)
+	content := apiTokenContent{}
+	require.NoError(t, json.Unmarshal(jsonDecrypted, &content))
+	assert.Equal(t, "ci", content.Name)
+	assert.Equal(t, "ghp_456", content.Token)
+
+	response, err := service.Delete(ctx, handlers.DeleteAPITokenRequest{UUID: uuidStr})
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+}
+
+func TestAPITokenService_WrongType(t *testing.T) {
+	mockRepo := new(MockAPITokenRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewAPITokenService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	data := entity.Data{UUID: uuidStr, ContentType: entity.Note, CreatedBy: user}
+	jsonContent, _ := json.Marshal(map[string]string{"title": "todo"})
+	data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	token := "ghp_456"
+	_, err := service.Update(ctx, handlers.UpdateAPITokenRequest{UUID: uuidStr, Token: &token})
+	assert.EqualError(t, err, customerr.API_TOKEN_NOT_FOUND)
+
+	_, err = service.Delete(ctx, handlers.DeleteAPITokenRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.API_TOKEN_NOT_FOUND)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
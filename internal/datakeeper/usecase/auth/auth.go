@@ -2,58 +2,118 @@ package auth
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
+	"net/http"
 
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
 	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
-	securityservicev1 "github.com/GusevGrishaEm1/protos/gen/go/security_service"
 )
 
+// Provider checks login and password of users, gRPC auth service or local users table
+type Provider interface {
+	// Login check password and return token of provider
+	Login(ctx context.Context, login string, password string) (string, error)
+	// Register create user
+	Register(ctx context.Context, login string, password string) error
+}
+
 type KeyService interface {
 	SetKeyForUser(user string, key string) error
-	GenerateKey() (string, error)
+	NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error)
+	UnlockKey(masterPassword string, params *entity.KeyParams) (string, error)
+}
+
+type KeyParamsRepo interface {
+	Insert(ctx context.Context, params entity.KeyParams) error
+	GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error)
+	Delete(ctx context.Context, login string) error
+}
+
+// SessionService issue access and refresh tokens of signed in user
+type SessionService interface {
+	Issue(ctx context.Context, login string) (*handlers.SessionTokens, error)
 }
 
 type Service struct {
-	authClient securityservicev1.AuthClient
-	keyService KeyService
-	logger     *slog.Logger
+	provider       Provider
+	keyService     KeyService
+	keyParamsRepo  KeyParamsRepo
+	sessionService SessionService
+	logger         *slog.Logger
 }
 
 // NewAuthService creates new auth service
-func NewAuthService(authClient securityservicev1.AuthClient, keyService KeyService, logger *slog.Logger) (*Service, error) {
-	return &Service{authClient, keyService, logger}, nil
+func NewAuthService(provider Provider, keyService KeyService, keyParamsRepo KeyParamsRepo, sessionService SessionService, logger *slog.Logger) (*Service, error) {
+	return &Service{provider, keyService, keyParamsRepo, sessionService, logger}, nil
 }
 
-// SignIn sign in user
+// SignIn sign in user, new session is started
 func (a *Service) SignIn(ctx context.Context, r handlers.LoginRequest) (*handlers.LoginResponse, error) {
-	res, err := a.authClient.Login(ctx, &securityservicev1.LoginRequest{Login: r.Login, Password: r.Password})
+	if err := a.Authenticate(ctx, r.Login, r.Password); err != nil {
+		return nil, err
+	}
+	key, err := a.unlockKey(ctx, r)
 	if err != nil {
-		fmt.Print(err.Error())
 		return nil, err
 	}
-	if err = a.keyService.SetKeyForUser(r.Login, r.Key); err != nil {
+	if err = a.keyService.SetKeyForUser(r.Login, key); err != nil {
+		return nil, err
+	}
+	tokens, err := a.sessionService.Issue(ctx, r.Login)
+	if err != nil {
 		return nil, err
 	}
-	return &handlers.LoginResponse{Token: res.Token}, nil
+	return &handlers.LoginResponse{SessionTokens: *tokens}, nil
 }
 
-// SignUp sign up user
-func (a *Service) SignUp(ctx context.Context, r handlers.RegisterRequest) (*handlers.RegisterResponse, error) {
-	_, err := a.authClient.Register(ctx, &securityservicev1.RegisterRequest{Login: r.Login, Password: r.Password})
+// Authenticate check login and password of user, vault stays locked and no session is started
+func (a *Service) Authenticate(ctx context.Context, login string, password string) error {
+	_, err := a.provider.Login(ctx, login, password)
 	if err != nil {
 		a.logger.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// unlockKey derive key from master password, accounts registered before master password use key from request
+func (a *Service) unlockKey(ctx context.Context, r handlers.LoginRequest) (string, error) {
+	params, err := a.keyParamsRepo.GetByLogin(ctx, r.Login)
+	if err != nil {
+		return "", err
+	}
+	if params == nil {
+		if r.Key == "" {
+			return "", customerr.ErrorWithCode(customerr.KEY_REQUIRED, http.StatusBadRequest)
+		}
+		return r.Key, nil
+	}
+	return a.keyService.UnlockKey(r.MasterPassword, params)
+}
+
+// SignUp sign up user, key parameters are saved before user is registered,
+// so registered user never falls back to login with key from request
+func (a *Service) SignUp(ctx context.Context, r handlers.RegisterRequest) (*handlers.RegisterResponse, error) {
+	key, params, err := a.keyService.NewKeyParams(r.Login, r.MasterPassword)
+	if err != nil {
 		return nil, err
 	}
-	key, err := a.keyService.GenerateKey()
+	if err = a.keyParamsRepo.Insert(ctx, *params); err != nil {
+		return nil, err
+	}
+	err = a.provider.Register(ctx, r.Login, r.Password)
 	if err != nil {
+		a.logger.Error(err.Error())
+		if deleteErr := a.keyParamsRepo.Delete(ctx, r.Login); deleteErr != nil {
+			a.logger.Error(deleteErr.Error())
+		}
 		return nil, err
 	}
 	if err = a.keyService.SetKeyForUser(r.Login, key); err != nil {
 		return nil, err
 	}
-	return &handlers.RegisterResponse{Key: key}, nil
+	return &handlers.RegisterResponse{Login: r.Login}, nil
 }
 
 // GetUserFromContext get user from context
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
 	security_servicev1 "github.com/GusevGrishaEm1/protos/gen/go/security_service"
 )
@@ -52,17 +55,63 @@ func (m *mockKeyService) SetKeyForUser(user string, key string) error {
 	return nil
 }
 
-// GenerateKey mock
-func (m *mockKeyService) GenerateKey() (string, error) {
+// NewKeyParams mock
+func (m *mockKeyService) NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error) {
+	return "some_key", &entity.KeyParams{Login: user, CheckValue: []byte(masterPassword)}, nil
+}
+
+// UnlockKey mock
+func (m *mockKeyService) UnlockKey(masterPassword string, params *entity.KeyParams) (string, error) {
+	if masterPassword != string(params.CheckValue) {
+		return "", customerr.ErrorWithCode(customerr.INVALID_MASTER_PASSWORD, http.StatusUnauthorized)
+	}
 	return "some_key", nil
 }
 
+// mockKeyParamsRepo mocks key parameters repository
+type mockKeyParamsRepo struct {
+	params map[string]entity.KeyParams
+}
+
+// Insert mock
+func (m *mockKeyParamsRepo) Insert(ctx context.Context, params entity.KeyParams) error {
+	if m.params == nil {
+		m.params = make(map[string]entity.KeyParams)
+	}
+	m.params[params.Login] = params
+	return nil
+}
+
+// Delete mock
+func (m *mockKeyParamsRepo) Delete(ctx context.Context, login string) error {
+	delete(m.params, login)
+	return nil
+}
+
+// GetByLogin mock
+func (m *mockKeyParamsRepo) GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error) {
+	params, ok := m.params[login]
+	if !ok {
+		return nil, nil
+	}
+	return &params, nil
+}
+
+// mockSessionService mocks session service
+type mockSessionService struct {
+}
+
+// Issue mock
+func (m *mockSessionService) Issue(ctx context.Context, login string) (*handlers.SessionTokens, error) {
+	return &handlers.SessionTokens{Token: "session_token", RefreshToken: "refresh_token"}, nil
+}
+
 func TestSignIn(t *testing.T) {
 	ctx := context.Background()
 	mockAuthClient := new(mockAuthClient)
 	mockKeyService := new(mockKeyService)
 
-	service, err := NewAuthService(mockAuthClient, mockKeyService, slog.Default())
+	service, err := NewAuthService(NewGRPCProvider(mockAuthClient), mockKeyService, new(mockKeyParamsRepo), new(mockSessionService), slog.Default())
 	assert.NoError(t, err)
 
 	request := handlers.LoginRequest{
@@ -73,7 +122,8 @@ func TestSignIn(t *testing.T) {
 	response, err := service.SignIn(ctx, request)
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
-	assert.Equal(t, "some_token", response.Token)
+	assert.Equal(t, "session_token", response.Token)
+	assert.Equal(t, "refresh_token", response.RefreshToken)
 
 	request = handlers.LoginRequest{
 		Login:    "nonexisting@example.com",
@@ -84,26 +134,88 @@ func TestSignIn(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewAuthService(NewGRPCProvider(new(mockAuthClient)), new(mockKeyService), new(mockKeyParamsRepo), new(mockSessionService), slog.Default())
+	assert.NoError(t, err)
+
+	// vault is not unlocked, no key is required
+	err = service.Authenticate(ctx, "existing@example.com", "password")
+	assert.NoError(t, err)
+
+	err = service.Authenticate(ctx, "existing@example.com", "wrong")
+	assert.Error(t, err)
+}
+
 func TestSignUp(t *testing.T) {
 	ctx := context.Background()
 	mockAuthClient := new(mockAuthClient)
 	mockKeyService := new(mockKeyService)
 
-	service, err := NewAuthService(mockAuthClient, mockKeyService, slog.Default())
+	mockKeyParamsRepo := new(mockKeyParamsRepo)
+
+	service, err := NewAuthService(NewGRPCProvider(mockAuthClient), mockKeyService, mockKeyParamsRepo, new(mockSessionService), slog.Default())
 	assert.NoError(t, err)
 
 	request := handlers.RegisterRequest{
-		Login:    "new@example.com",
-		Password: "password",
+		Login:          "new@example.com",
+		Password:       "password",
+		MasterPassword: "master_password",
 	}
 	response, err := service.SignUp(ctx, request)
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
 
 	request = handlers.RegisterRequest{
-		Login:    "existing@example.com",
-		Password: "password",
+		Login:          "existing@example.com",
+		Password:       "password",
+		MasterPassword: "master_password",
 	}
 	_, err = service.SignUp(ctx, request)
 	assert.Error(t, err)
+
+	// key parameters of failed registration are removed, only registered user has them
+	params, err := mockKeyParamsRepo.GetByLogin(ctx, "existing@example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, params)
+	params, err = mockKeyParamsRepo.GetByLogin(ctx, "new@example.com")
+	assert.NoError(t, err)
+	assert.NotNil(t, params)
+}
+
+func TestSignInMasterPassword(t *testing.T) {
+	ctx := context.Background()
+	mockKeyParamsRepo := new(mockKeyParamsRepo)
+	err := mockKeyParamsRepo.Insert(ctx, entity.KeyParams{Login: "existing@example.com", CheckValue: []byte("master_password")})
+	assert.NoError(t, err)
+
+	service, err := NewAuthService(NewGRPCProvider(new(mockAuthClient)), new(mockKeyService), mockKeyParamsRepo, new(mockSessionService), slog.Default())
+	assert.NoError(t, err)
+
+	request := handlers.LoginRequest{
+		Login:          "existing@example.com",
+		Password:       "password",
+		MasterPassword: "master_password",
+	}
+	response, err := service.SignIn(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "session_token", response.Token)
+	assert.Equal(t, "refresh_token", response.RefreshToken)
+
+	request.MasterPassword = "wrong_password"
+	_, err = service.SignIn(ctx, request)
+	assert.EqualError(t, err, customerr.INVALID_MASTER_PASSWORD)
+}
+
+func TestSignInWithoutKey(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewAuthService(NewGRPCProvider(new(mockAuthClient)), new(mockKeyService), new(mockKeyParamsRepo), new(mockSessionService), slog.Default())
+	assert.NoError(t, err)
+
+	request := handlers.LoginRequest{
+		Login:    "existing@example.com",
+		Password: "password",
+	}
+	_, err = service.SignIn(ctx, request)
+	assert.EqualError(t, err, customerr.KEY_REQUIRED)
 }
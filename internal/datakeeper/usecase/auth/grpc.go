@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+
+	securityservicev1 "github.com/GusevGrishaEm1/protos/gen/go/security_service"
+)
+
+const (
+	// ProviderGRPC users of separate gRPC auth service
+	ProviderGRPC = "grpc"
+	// ProviderLocal users of local users table
+	ProviderLocal = "local"
+)
+
+// GRPCProvider users of gRPC auth service
+type GRPCProvider struct {
+	client securityservicev1.AuthClient
+}
+
+func NewGRPCProvider(client securityservicev1.AuthClient) *GRPCProvider {
+	return &GRPCProvider{client: client}
+}
+
+// Login check password by auth service
+func (p *GRPCProvider) Login(ctx context.Context, login string, password string) (string, error) {
+	res, err := p.client.Login(ctx, &securityservicev1.LoginRequest{Login: login, Password: password})
+	if err != nil {
+		return "", err
+	}
+	return res.Token, nil
+}
+
+// Register create user in auth service
+func (p *GRPCProvider) Register(ctx context.Context, login string, password string) error {
+	_, err := p.client.Register(ctx, &securityservicev1.RegisterRequest{Login: login, Password: password})
+	return err
+}
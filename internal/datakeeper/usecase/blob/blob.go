@@ -0,0 +1,167 @@
+package blob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/google/uuid"
+)
+
+// MaxBlobSize max size of blob content in bytes
+const MaxBlobSize = 1024 * 1024
+
+// MaxMetadataSize max size of blob metadata in bytes
+const MaxMetadataSize = 4 * 1024
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+// Service stores blobs encrypted by client. Service has no access to user key,
+// content and metadata are stored and returned as they are sent
+type Service struct {
+	repo        Repo
+	authService AuthService
+}
+
+func NewBlobService(repo Repo, authService AuthService) *Service {
+	return &Service{repo: repo, authService: authService}
+}
+
+// Create blob, uuid chosen by client is kept so client can bind ciphertext to it
+func (s *Service) Create(ctx context.Context, r handlers.CreateBlobRequest) (*handlers.CreateBlobResponse, error) {
+	if r.UUID == "" {
+		r.UUID = uuid.New().String()
+	}
+	if err := validate(r.UUID, r.Content, r.Metadata); err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        r.UUID,
+		Content:     r.Content,
+		ContentType: entity.Blob,
+		Metadata:    r.Metadata,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateBlobResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Update replace content and metadata of blob
+func (s *Service) Update(ctx context.Context, r handlers.UpdateBlobRequest) (*handlers.UpdateBlobResponse, error) {
+	if err := validate(r.UUID, r.Content, r.Metadata); err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.get(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB.Content = r.Content
+	fromDB.Metadata = r.Metadata
+
+	err = s.repo.Update(ctx, *fromDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateBlobResponse{UUID: fromDB.UUID}, nil
+}
+
+// Delete delete blob
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteBlobRequest) (*handlers.DeleteBlobResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// data of other content types is not deleted through blob endpoints
+	if _, err = s.get(ctx, user, r.UUID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteBlobResponse{UUID: r.UUID}, nil
+}
+
+// GetAll get all blobs of user
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllBlobsRequest) (*handlers.GetAllBlobsResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.Blob)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllBlobsResponseItem, 0, len(data))
+	for _, v := range data {
+		items = append(items, handlers.GetAllBlobsResponseItem{
+			UUID:     v.UUID,
+			Content:  v.Content,
+			Metadata: v.Metadata,
+		})
+	}
+
+	return &handlers.GetAllBlobsResponse{Items: items}, nil
+}
+
+// get load blob of user
+func (s *Service) get(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil || fromDB.ContentType != entity.Blob {
+		return nil, customerr.ErrorWithCode(customerr.BLOB_NOT_FOUND, http.StatusNotFound)
+	}
+	return fromDB, nil
+}
+
+// validate blob, content is not inspected
+func validate(id string, content []byte, metadata json.RawMessage) error {
+	if len(content) > MaxBlobSize || len(metadata) > MaxMetadataSize {
+		return customerr.ErrorWithCode(customerr.BLOB_TOO_LARGE, http.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(id); err != nil || len(content) == 0 {
+		return customerr.ErrorWithCode(customerr.INVALID_BLOB, http.StatusBadRequest)
+	}
+	if len(metadata) > 0 && !json.Valid(metadata) {
+		return customerr.ErrorWithCode(customerr.INVALID_BLOB, http.StatusBadRequest)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package blob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobService_Create(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewBlobService(mockRepo, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	uuidStr := uuid.NewString()
+	content := []byte("client ciphertext")
+	metadata := json.RawMessage(`{"kind": "login"}`)
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, handlers.CreateBlobRequest{UUID: uuidStr, Content: content, Metadata: metadata})
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+
+	// content and metadata are stored untouched
+	saved := mockRepo.Calls[0].Arguments.Get(1).(entity.Data)
+	assert.Equal(t, content, saved.Content)
+	assert.Equal(t, []byte(metadata), saved.Metadata)
+	assert.Empty(t, saved.DataKey)
+	assert.Equal(t, entity.Blob, saved.ContentType)
+	assert.Equal(t, user, saved.CreatedBy)
+}
+
+func TestBlobService_Create_GeneratedUUID(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewBlobService(mockRepo, mockAuthService)
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return("test_user", nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(context.Background(), handlers.CreateBlobRequest{Content: []byte("client ciphertext")})
+	require.NoError(t, err)
+	_, err = uuid.Parse(response.UUID)
+	assert.NoError(t, err)
+}
+
+func TestBlobService_Create_Invalid(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	service := NewBlobService(mockRepo, new(MockAuthService))
+
+	tests := []struct {
+		name    string
+		request handlers.CreateBlobRequest
+		message string
+	}{
+		{"empty content", handlers.CreateBlobRequest{}, customerr.INVALID_BLOB},
+		{"invalid uuid", handlers.CreateBlobRequest{UUID: "1", Content: []byte("x")}, customerr.INVALID_BLOB},
+		{"invalid metadata", handlers.CreateBlobRequest{Content: []byte("x"), Metadata: json.RawMessage(`{`)}, customerr.INVALID_BLOB},
+		{"too large", handlers.CreateBlobRequest{Content: make([]byte, MaxBlobSize+1)}, customerr.BLOB_TOO_LARGE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Create(context.Background(), tt.request)
+			assert.EqualError(t, err, tt.message)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestBlobService_Update(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewBlobService(mockRepo, mockAuthService)
+
+	user := "test_user"
+	uuidStr := uuid.NewString()
+	data := entity.Data{UUID: uuidStr, Content: []byte("old"), ContentType: entity.Blob, CreatedBy: user}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Update(context.Background(), handlers.UpdateBlobRequest{UUID: uuidStr, Content: []byte("new")})
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+
+	updated := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(entity.Data)
+	assert.Equal(t, "new", string(updated.Content))
+	assert.Nil(t, updated.Metadata)
+}
+
+func TestBlobService_Update_NotBlob(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewBlobService(mockRepo, mockAuthService)
+
+	user := "test_user"
+	noteUUID := uuid.NewString()
+	missingUUID := uuid.NewString()
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, noteUUID).Return(&entity.Data{UUID: noteUUID, ContentType: entity.Note}, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, missingUUID).Return((*entity.Data)(nil), errors.New("no rows in result set"))
+
+	_, err := service.Update(context.Background(), handlers.UpdateBlobRequest{UUID: noteUUID, Content: []byte("new")})
+	assert.EqualError(t, err, customerr.BLOB_NOT_FOUND)
+
+	_, err = service.Delete(context.Background(), handlers.DeleteBlobRequest{UUID: missingUUID})
+	assert.EqualError(t, err, customerr.BLOB_NOT_FOUND)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlobService_Delete(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewBlobService(mockRepo, mockAuthService)
+
+	user := "test_user"
+	uuidStr := uuid.NewString()
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&entity.Data{UUID: uuidStr, ContentType: entity.Blob}, nil)
+	mockRepo.On("Delete", mock.Anything, user, uuidStr).Return(nil)
+
+	response, err := service.Delete(context.Background(), handlers.DeleteBlobRequest{UUID: uuidStr})
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlobService_GetAll(t *testing.T) {
+	mockRepo := new(MockBlobRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewBlobService(mockRepo, mockAuthService)
+
+	user := "test_user"
+	data := []*entity.Data{
+		{UUID: uuid.NewString(), Content: []byte("ciphertext"), Metadata: []byte(`{"kind":"note"}`), ContentType: entity.Blob},
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Blob).Return(data, nil)
+
+	response, err := service.GetAll(context.Background(), handlers.GetAllBlobsRequest{})
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, data[0].UUID, response.Items[0].UUID)
+	assert.Equal(t, "ciphertext", string(response.Items[0].Content))
+	assert.JSONEq(t, `{"kind":"note"}`, string(response.Items[0].Metadata))
+}
@@ -0,0 +1,48 @@
+package blob
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBlobRepo is a mock implementation of Repo
+type MockBlobRepo struct {
+	mock.Mock
+}
+
+func (m *MockBlobRepo) Insert(ctx context.Context, data entity.Data) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockBlobRepo) Update(ctx context.Context, data entity.Data) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockBlobRepo) Delete(ctx context.Context, user string, uuid string) error {
+	args := m.Called(ctx, user, uuid)
+	return args.Error(0)
+}
+
+func (m *MockBlobRepo) GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	args := m.Called(ctx, user, uuid)
+	return args.Get(0).(*entity.Data), args.Error(1)
+}
+
+func (m *MockBlobRepo) GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error) {
+	args := m.Called(ctx, user, contentType)
+	return args.Get(0).([]*entity.Data), args.Error(1)
+}
+
+// MockAuthService is a mock implementation of AuthService
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) GetUserFromContext(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
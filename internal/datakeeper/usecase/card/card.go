@@ -0,0 +1,245 @@
+package card
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type cardContent struct {
+	Name   string `json:"name"`
+	Number string `json:"number"`
+	Holder string `json:"holder"`
+	Expiry string `json:"expiry"`
+	CVV    string `json:"cvv"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewCardService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Create card
+func (s *Service) Create(ctx context.Context, r handlers.CreateCardRequest) (*handlers.CreateCardResponse, error) {
+	content := cardContent{
+		Name:   r.Name,
+		Number: normalizeNumber(r.Number),
+		Holder: r.Holder,
+		Expiry: r.Expiry,
+		CVV:    r.CVV,
+	}
+	if err := s.validate(content); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.Card,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateCardResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Update update card
+func (s *Service) Update(ctx context.Context, r handlers.UpdateCardRequest) (*handlers.UpdateCardResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	content := cardContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setContentToUpdate(r, &content)
+	if err = s.validate(content); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Update(ctx, *fromDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateCardResponse{UUID: fromDB.UUID}, nil
+}
+
+func (s *Service) setContentToUpdate(r handlers.UpdateCardRequest, content *cardContent) {
+	if r.Name != nil {
+		content.Name = *r.Name
+	}
+	if r.Number != nil {
+		content.Number = normalizeNumber(*r.Number)
+	}
+	if r.Holder != nil {
+		content.Holder = *r.Holder
+	}
+	if r.Expiry != nil {
+		content.Expiry = *r.Expiry
+	}
+	if r.CVV != nil {
+		content.CVV = *r.CVV
+	}
+}
+
+// validate check card number and expiry date
+func (s *Service) validate(content cardContent) error {
+	if err := validateNumber(content.Number); err != nil {
+		return err
+	}
+	return validateExpiry(content.Expiry, time.Now())
+}
+
+// Delete delete card
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteCardRequest) (*handlers.DeleteCardResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.load(ctx, user, r.UUID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteCardResponse{UUID: r.UUID}, nil
+}
+
+// load card without decrypting it, row of another content type is not found
+func (s *Service) load(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fromDB.ContentType != entity.Card {
+		return nil, customerr.ErrorWithCode(customerr.CARD_NOT_FOUND, http.StatusNotFound)
+	}
+	return fromDB, nil
+}
+
+// GetAll get all cards with masked numbers
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllCardsRequest) (*handlers.GetAllCardsResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.Card)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllCardsResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		content := cardContent{}
+		err = json.Unmarshal(jsonDecrypted, &content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, handlers.GetAllCardsResponseItem{
+			UUID:   v.UUID,
+			Name:   content.Name,
+			Number: maskNumber(content.Number),
+			Brand:  string(detectBrand(content.Number)),
+			Holder: content.Holder,
+			Expiry: content.Expiry,
+		})
+	}
+
+	return &handlers.GetAllCardsResponse{Items: items}, nil
+}
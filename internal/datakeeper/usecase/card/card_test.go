@@ -0,0 +1,207 @@
+package card
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCardService_Create(t *testing.T) {
+	mockRepo := new(MockCardRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewCardService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	request := handlers.CreateCardRequest{
+		Name:   "corporate",
+		Number: "4111 1111 1111 1111",
+		Holder: "IVAN IVANOV",
+		Expiry: "12/99",
+		CVV:    "123",
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	var saved entity.Data
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(entity.Data)
+	}).Return(nil)
+
+	response, err := service.Create(ctx, request)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, response)
+	assert.NotEmpty(t, response.UUID)
+	mockRepo.AssertCalled(t, "Insert", mock.Anything, mock.Anything)
+
+	// content is encrypted with data key, data key is wrapped by user key
+	assert.NotEmpty(t, saved.DataKey)
+	_, err = lib.Decrypt(key, saved.Content)
+	assert.ErrorIs(t, err, lib.ErrIntegrity)
+	_, err = lib.OpenEnvelope(key, saved.DataKey, saved.Content, saved.AssociatedData())
+	assert.NoError(t, err)
+}
+
+func TestCardService_Create_Invalid(t *testing.T) {
+	mockRepo := new(MockCardRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewCardService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, handlers.CreateCardRequest{Number: "4111111111111112", Expiry: "12/99"})
+	assert.EqualError(t, err, customerr.INVALID_CARD_NUMBER)
+
+	_, err = service.Create(ctx, handlers.CreateCardRequest{Number: "4111111111111111", Expiry: "13/99"})
+	assert.EqualError(t, err, customerr.INVALID_CARD_EXPIRY)
+
+	mockRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestCardService_Update(t *testing.T) {
+	mockRepo := new(MockCardRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewCardService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	content := cardContent{Name: "old_name", Number: "4111111111111111", Expiry: "12/99"}
+	jsonContent, _ := json.Marshal(&content)
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.Card}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	name := "new_name"
+	response, err := service.Update(ctx, handlers.UpdateCardRequest{UUID: uuidStr, Name: &name})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+	mockRepo.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCardService_Delete(t *testing.T) {
+	mockRepo := new(MockCardRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewCardService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	uuidStr := uuid.New().String()
+
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockRepo.On("GetByUUID", ctx, user, uuidStr).Return(&entity.Data{UUID: uuidStr, ContentType: entity.Card}, nil)
+	mockRepo.On("Delete", ctx, user, uuidStr).Return(nil)
+
+	response, err := service.Delete(ctx, handlers.DeleteCardRequest{UUID: uuidStr})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+	mockRepo.AssertCalled(t, "Delete", ctx, user, uuidStr)
+}
+
+func TestCardService_WrongType(t *testing.T) {
+	mockRepo := new(MockCardRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewCardService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	data := entity.Data{UUID: uuidStr, ContentType: entity.File, CreatedBy: user}
+	jsonContent, _ := json.Marshal(map[string]string{"name": "scan.pdf"})
+	data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	number := "4111111111111111"
+	_, err := service.Update(ctx, handlers.UpdateCardRequest{UUID: uuidStr, Number: &number})
+	assert.EqualError(t, err, customerr.CARD_NOT_FOUND)
+
+	_, err = service.Delete(ctx, handlers.DeleteCardRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.CARD_NOT_FOUND)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCardService_GetAll(t *testing.T) {
+	mockRepo := new(MockCardRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewCardService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	content := cardContent{Name: "corporate", Number: "5555555555554444", Holder: "IVAN IVANOV", Expiry: "12/99", CVV: "123"}
+	jsonContent, _ := json.Marshal(&content)
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := []*entity.Data{
+		{UUID: uuid.New().String(), Content: encryptedContent, ContentType: entity.Card},
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Card).Return(data, nil)
+
+	response, err := service.GetAll(ctx, handlers.GetAllCardsRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, "**** **** **** 4444", response.Items[0].Number)
+	assert.Equal(t, string(Mastercard), response.Items[0].Brand)
+}
+
+func TestValidateNumber(t *testing.T) {
+	assert.NoError(t, validateNumber("4111111111111111"))
+	assert.NoError(t, validateNumber("378282246310005"))
+	assert.Error(t, validateNumber("4111111111111112"))
+	assert.Error(t, validateNumber("41111111111a1111"))
+	assert.Error(t, validateNumber("4111"))
+}
+
+func TestValidateExpiry(t *testing.T) {
+	now := time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, validateExpiry("07/24", now))
+	assert.NoError(t, validateExpiry("01/30", now))
+	assert.EqualError(t, validateExpiry("06/24", now), customerr.CARD_EXPIRED)
+	assert.EqualError(t, validateExpiry("00/30", now), customerr.INVALID_CARD_EXPIRY)
+	assert.EqualError(t, validateExpiry("1/30", now), customerr.INVALID_CARD_EXPIRY)
+}
+
+func TestDetectBrand(t *testing.T) {
+	assert.Equal(t, Visa, detectBrand("4111111111111111"))
+	assert.Equal(t, Mastercard, detectBrand("5555555555554444"))
+	assert.Equal(t, Mastercard, detectBrand("2223003122003222"))
+	assert.Equal(t, Amex, detectBrand("378282246310005"))
+	assert.Equal(t, Discover, detectBrand("6011111111111117"))
+	assert.Equal(t, JCB, detectBrand("3530111333300000"))
+	assert.Equal(t, Mir, detectBrand("2200000000000004"))
+	assert.Equal(t, Unknown, detectBrand("9999999999999995"))
+}
@@ -0,0 +1,122 @@
+package card
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+)
+
+// Brand card payment system
+type Brand string
+
+const (
+	Visa       Brand = "VISA"
+	Mastercard Brand = "MASTERCARD"
+	Amex       Brand = "AMEX"
+	Discover   Brand = "DISCOVER"
+	JCB        Brand = "JCB"
+	Diners     Brand = "DINERS"
+	UnionPay   Brand = "UNIONPAY"
+	Maestro    Brand = "MAESTRO"
+	Mir        Brand = "MIR"
+	Unknown    Brand = "UNKNOWN"
+)
+
+// normalizeNumber remove spaces and dashes from card number
+func normalizeNumber(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(number)
+}
+
+// validateNumber check card number length and Luhn checksum
+func validateNumber(number string) error {
+	if len(number) < 12 || len(number) > 19 {
+		return customerr.ErrorWithCode(customerr.INVALID_CARD_NUMBER, http.StatusBadRequest)
+	}
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			return customerr.ErrorWithCode(customerr.INVALID_CARD_NUMBER, http.StatusBadRequest)
+		}
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	if sum%10 != 0 {
+		return customerr.ErrorWithCode(customerr.INVALID_CARD_NUMBER, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// validateExpiry check expiry date in MM/YY format, card is valid until the end of the month
+func validateExpiry(expiry string, now time.Time) error {
+	parts := strings.Split(expiry, "/")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return customerr.ErrorWithCode(customerr.INVALID_CARD_EXPIRY, http.StatusBadRequest)
+	}
+	month, err := strconv.Atoi(parts[0])
+	if err != nil || month < 1 || month > 12 {
+		return customerr.ErrorWithCode(customerr.INVALID_CARD_EXPIRY, http.StatusBadRequest)
+	}
+	year, err := strconv.Atoi(parts[1])
+	if err != nil || year < 0 {
+		return customerr.ErrorWithCode(customerr.INVALID_CARD_EXPIRY, http.StatusBadRequest)
+	}
+	validUntil := time.Date(2000+year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	if !now.Before(validUntil) {
+		return customerr.ErrorWithCode(customerr.CARD_EXPIRED, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// detectBrand detect payment system by card number prefix
+func detectBrand(number string) Brand {
+	prefix := func(n int) int {
+		if len(number) < n {
+			return -1
+		}
+		v, err := strconv.Atoi(number[:n])
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+	switch {
+	case prefix(4) >= 2200 && prefix(4) <= 2204:
+		return Mir
+	case prefix(1) == 4:
+		return Visa
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		return Mastercard
+	case prefix(2) == 34, prefix(2) == 37:
+		return Amex
+	case prefix(4) == 6011, prefix(2) == 65, prefix(3) >= 644 && prefix(3) <= 649:
+		return Discover
+	case prefix(4) >= 3528 && prefix(4) <= 3589:
+		return JCB
+	case prefix(2) == 36, prefix(2) == 38, prefix(2) == 39, prefix(3) >= 300 && prefix(3) <= 305:
+		return Diners
+	case prefix(2) == 62:
+		return UnionPay
+	case prefix(2) == 50, prefix(2) >= 56 && prefix(2) <= 58, prefix(1) == 6:
+		return Maestro
+	}
+	return Unknown
+}
+
+// maskNumber hide all digits except the last four
+func maskNumber(number string) string {
+	if len(number) <= 4 {
+		return number
+	}
+	return "**** **** **** " + number[len(number)-4:]
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"context"
+	"io"
+)
+
+// chunkRowSize size of ciphertext stored in one file chunk row
+const chunkRowSize = 1024 * 1024
+
+// chunkWriter writes stream ciphertext to file chunk rows, only one row is buffered
+type chunkWriter struct {
+	ctx  context.Context
+	repo RepoFile
+	uuid string
+	seq  int
+	buf  []byte
+}
+
+func newChunkWriter(ctx context.Context, repo RepoFile, uuid string) *chunkWriter {
+	return &chunkWriter{ctx: ctx, repo: repo, uuid: uuid, buf: make([]byte, 0, chunkRowSize)}
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		m := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		p = p[m:]
+		n += m
+		if len(w.buf) == chunkRowSize {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Close write rest of buffer
+func (w *chunkWriter) Close() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *chunkWriter) flush() error {
+	if err := w.repo.InsertChunk(w.ctx, w.uuid, w.seq, w.buf); err != nil {
+		return err
+	}
+	w.seq++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// chunkReader reads stream ciphertext from file chunk rows one by one
+type chunkReader struct {
+	ctx  context.Context
+	repo RepoFile
+	user string
+	uuid string
+	seq  int
+	buf  []byte
+	eof  bool
+}
+
+func newChunkReader(ctx context.Context, repo RepoFile, user string, uuid string) *chunkReader {
+	return &chunkReader{ctx: ctx, repo: repo, user: user, uuid: uuid}
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		chunk, err := r.repo.GetChunk(r.ctx, r.user, r.uuid, r.seq)
+		if err != nil {
+			return 0, err
+		}
+		if chunk == nil {
+			r.eof = true
+			continue
+		}
+		r.buf = chunk
+		r.seq++
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -1,11 +1,14 @@
 package file
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"time"
 
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
 	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
 	"github.com/google/uuid"
@@ -21,6 +24,8 @@ type RepoFile interface {
 	Insert(ctx context.Context, data entity.FileRepo) error
 	Delete(ctx context.Context, user string, uuid string) error
 	GetByUUID(ctx context.Context, user string, uuid string) (*entity.FileRepo, error)
+	InsertChunk(ctx context.Context, uuid string, seq int, content []byte) error
+	GetChunk(ctx context.Context, user string, uuid string, seq int) ([]byte, error)
 }
 
 type Repo interface {
@@ -55,7 +60,8 @@ func NewFileService(dataRepo Repo, fileRepo RepoFile, authService AuthService, k
 	}
 }
 
-// UploadFile Upload file
+// UploadFile Upload file, content is encrypted and stored in chunks while it is read,
+// so memory use does not depend on file size
 func (s *CardService) UploadFile(ctx context.Context, r handlers.UploadFileRequest) (*handlers.UploadFileResponse, error) {
 	user, err := s.authService.GetUserFromContext(ctx)
 	if err != nil {
@@ -67,54 +73,86 @@ func (s *CardService) UploadFile(ctx context.Context, r handlers.UploadFileReque
 		return nil, err
 	}
 
-	// insert file meta to data
-	fileContent := Content{
-		Name:   r.Name,
-		Format: r.Format,
-		Size:   len(r.File),
+	data := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.File,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
 	}
-	jsonContent, err := json.Marshal(fileContent)
+
+	// insert file content to user_file
+	file := entity.FileRepo{
+		UUID:      data.UUID,
+		Chunked:   true,
+		CreatedAt: data.CreatedAt,
+		CreatedBy: data.CreatedBy,
+	}
+	chunks := newChunkWriter(ctx, s.fileRepo, data.UUID)
+	writer, dataKey, err := lib.SealEnvelopeStream(key, chunks, file.AssociatedData())
 	if err != nil {
 		return nil, err
 	}
+	file.DataKey = dataKey
 
-	encryptedContent, err := lib.Encrypt(key, jsonContent)
+	err = s.fileRepo.Insert(ctx, file)
 	if err != nil {
 		return nil, err
 	}
 
-	data := entity.Data{
-		UUID:        uuid.New().String(),
-		Content:     encryptedContent,
-		ContentType: entity.File,
-		CreatedAt:   time.Now(),
-		CreatedBy:   user,
+	size, err := s.writeContent(writer, chunks, r.File)
+	if err != nil {
+		s.deleteFile(ctx, user, data.UUID)
+		return nil, err
 	}
 
-	err = s.dataRepo.Insert(ctx, data)
+	// insert file meta to data
+	fileContent := Content{
+		Name:   r.Name,
+		Format: r.Format,
+		Size:   int(size),
+	}
+	jsonContent, err := json.Marshal(fileContent)
 	if err != nil {
+		s.deleteFile(ctx, user, data.UUID)
 		return nil, err
 	}
 
-	// insert file data to user_file
-	encryptedContent, err = lib.Encrypt(key, r.File)
+	data.Content, data.DataKey, err = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
 	if err != nil {
+		s.deleteFile(ctx, user, data.UUID)
 		return nil, err
 	}
 
-	err = s.fileRepo.Insert(ctx, entity.FileRepo{
-		UUID:      data.UUID,
-		Content:   encryptedContent,
-		CreatedAt: data.CreatedAt,
-		CreatedBy: data.CreatedBy,
-	})
+	err = s.dataRepo.Insert(ctx, data)
 	if err != nil {
+		s.deleteFile(ctx, user, data.UUID)
 		return nil, err
 	}
 
 	return &handlers.UploadFileResponse{UUID: data.UUID}, nil
 }
 
+// writeContent encrypt file to chunks, size of plaintext is returned
+func (s *CardService) writeContent(writer io.WriteCloser, chunks io.Closer, file io.Reader) (int64, error) {
+	size, err := io.Copy(writer, file)
+	if err != nil {
+		return 0, err
+	}
+	if err = writer.Close(); err != nil {
+		return 0, err
+	}
+	if err = chunks.Close(); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// deleteFile remove partially uploaded file, chunks are deleted with file.
+// Upload may fail because request was canceled, so cleanup does not use request cancellation
+func (s *CardService) deleteFile(ctx context.Context, user string, uuid string) {
+	_ = s.fileRepo.Delete(context.WithoutCancel(ctx), user, uuid)
+}
+
 // DeleteFile delete file
 func (s *CardService) DeleteFile(ctx context.Context, r handlers.DeleteFileRequest) (*handlers.DeleteFileResponse, error) {
 	user, err := s.authService.GetUserFromContext(ctx)
@@ -153,9 +191,9 @@ func (s *CardService) GetAllFiles(ctx context.Context, r handlers.GetAllFilesReq
 
 	items := make([]handlers.GetAllFilesResponceItem, 0, len(data))
 	for _, item := range data {
-		decryptedContent, err := lib.Decrypt(key, item.Content)
+		decryptedContent, err := lib.OpenEnvelope(key, item.DataKey, item.Content, item.AssociatedData())
 		if err != nil {
-			return nil, err
+			return nil, customerr.IntegrityError(item.UUID, err)
 		}
 		fileDB := &Content{}
 		err = json.Unmarshal(decryptedContent, fileDB)
@@ -173,7 +211,7 @@ func (s *CardService) GetAllFiles(ctx context.Context, r handlers.GetAllFilesReq
 	return &handlers.GetAllFilesResponse{Items: items}, nil
 }
 
-// DownloadFile download file
+// DownloadFile download file, returned reader decrypts content while it is read
 func (s *CardService) DownloadFile(ctx context.Context, r handlers.DownloadFileRequest) (*handlers.DownloadFileResponse, error) {
 	user, err := s.authService.GetUserFromContext(ctx)
 	if err != nil {
@@ -190,9 +228,9 @@ func (s *CardService) DownloadFile(ctx context.Context, r handlers.DownloadFileR
 		return nil, err
 	}
 
-	decryptedContent, err := lib.Decrypt(key, data.Content)
+	decryptedContent, err := lib.OpenEnvelope(key, data.DataKey, data.Content, data.AssociatedData())
 	if err != nil {
-		return nil, err
+		return nil, customerr.IntegrityError(data.UUID, err)
 	}
 
 	fileDB := &Content{}
@@ -206,14 +244,29 @@ func (s *CardService) DownloadFile(ctx context.Context, r handlers.DownloadFileR
 		return nil, err
 	}
 
-	decryptedFileContent, err := lib.Decrypt(key, fileContent.Content)
+	fileReader, err := s.openContent(ctx, key, user, fileContent)
 	if err != nil {
-		return nil, err
+		return nil, customerr.IntegrityError(fileContent.UUID, err)
 	}
 
 	return &handlers.DownloadFileResponse{
 		Name:   fileDB.Name,
 		Format: fileDB.Format,
-		File:   decryptedFileContent,
+		Size:   fileDB.Size,
+		File:   fileReader,
 	}, nil
 }
+
+// openContent reader of decrypted file content, chunks are loaded one by one while content is read.
+// Files uploaded before chunked storage are decrypted whole
+func (s *CardService) openContent(ctx context.Context, key string, user string, file *entity.FileRepo) (io.Reader, error) {
+	if !file.Chunked {
+		content, err := lib.OpenEnvelope(key, file.DataKey, file.Content, file.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(content), nil
+	}
+
+	return lib.OpenEnvelopeStream(key, file.DataKey, newChunkReader(ctx, s.fileRepo, user, file.UUID), file.AssociatedData())
+}
@@ -1,12 +1,19 @@
 package file
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
 	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
 	"github.com/google/uuid"
@@ -63,6 +70,36 @@ func (m *mockUserFileRepo) GetByUUID(ctx context.Context, user string, uuid stri
 	return args.Get(0).(*entity.FileRepo), args.Error(1)
 }
 
+func (m *mockUserFileRepo) InsertChunk(ctx context.Context, uuid string, seq int, content []byte) error {
+	args := m.Called(ctx, uuid, seq, content)
+	return args.Error(0)
+}
+
+func (m *mockUserFileRepo) GetChunk(ctx context.Context, user string, uuid string, seq int) ([]byte, error) {
+	args := m.Called(ctx, user, uuid, seq)
+	if fn, ok := args.Get(0).(func(context.Context, string, string, int) []byte); ok {
+		return fn(ctx, user, uuid, seq), args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// chunkStore keeps chunks inserted to mock file repo
+type chunkStore struct {
+	chunks [][]byte
+}
+
+func (s *chunkStore) mock(m *mockUserFileRepo) {
+	m.On("InsertChunk", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		s.chunks = append(s.chunks, bytes.Clone(args.Get(3).([]byte)))
+	}).Return(nil)
+	m.On("GetChunk", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(_ context.Context, _ string, _ string, seq int) []byte {
+		if seq >= len(s.chunks) {
+			return nil
+		}
+		return s.chunks[seq]
+	}, nil)
+}
+
 type mockAuthService struct {
 	mock.Mock
 }
@@ -101,11 +138,12 @@ func TestUploadFile(t *testing.T) {
 	mockDataRepo.On("Insert", ctx, mock.AnythingOfType("entity.Data")).Return(nil)
 
 	mockUserFileRepo.On("Insert", ctx, mock.AnythingOfType("entity.FileRepo")).Return(nil)
+	mockUserFileRepo.On("InsertChunk", ctx, mock.Anything, 0, mock.Anything).Return(nil)
 
 	req := handlers.UploadFileRequest{
 		Name:   "test-file",
 		Format: "txt",
-		File:   fileContent,
+		File:   bytes.NewReader(fileContent),
 	}
 
 	resp, err := service.UploadFile(ctx, req)
@@ -241,5 +279,205 @@ func TestDownloadFile(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Equal(t, "test-file", resp.Name)
 	assert.Equal(t, "txt", resp.Format)
-	assert.Equal(t, []byte("encrypted file content"), resp.File)
+	downloaded, err := io.ReadAll(resp.File)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("encrypted file content"), downloaded)
+}
+
+func TestUploadDownloadFileEnvelope(t *testing.T) {
+	mockDataRepo := new(mockDataRepo)
+	mockUserFileRepo := new(mockUserFileRepo)
+	mockAuthService := new(mockAuthService)
+	mockKeyService := new(MockKeyService)
+
+	service := NewFileService(mockDataRepo, mockUserFileRepo, mockAuthService, mockKeyService)
+
+	ctx := context.Background()
+	user := "test-user"
+	key := "352fa5gdhvdryhwr"
+	fileContent := []byte("test file content")
+
+	var savedData entity.Data
+	var savedFile entity.FileRepo
+	chunks := &chunkStore{}
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockDataRepo.On("Insert", ctx, mock.MatchedBy(func(data entity.Data) bool {
+		savedData = data
+		return true
+	})).Return(nil)
+	mockUserFileRepo.On("Insert", ctx, mock.MatchedBy(func(data entity.FileRepo) bool {
+		savedFile = data
+		return true
+	})).Return(nil)
+	chunks.mock(mockUserFileRepo)
+
+	resp, err := service.UploadFile(ctx, handlers.UploadFileRequest{Name: "test-file", Format: "txt", File: bytes.NewReader(fileContent)})
+	assert.NoError(t, err)
+
+	// metadata and content have own data keys wrapped by user key
+	assert.NotEmpty(t, savedData.DataKey)
+	assert.NotEmpty(t, savedFile.DataKey)
+	assert.NotEqual(t, savedData.DataKey, savedFile.DataKey)
+	assert.True(t, savedFile.Chunked)
+	assert.Empty(t, savedFile.Content)
+	assert.Len(t, chunks.chunks, 1)
+	assert.NotContains(t, string(chunks.chunks[0]), string(fileContent))
+
+	mockDataRepo.On("GetByUUID", ctx, user, resp.UUID).Return(&savedData, nil)
+	mockUserFileRepo.On("GetByUUID", ctx, user, resp.UUID).Return(&savedFile, nil)
+
+	downloaded, err := service.DownloadFile(ctx, handlers.DownloadFileRequest{UUID: resp.UUID})
+	assert.NoError(t, err)
+	assert.Equal(t, "test-file", downloaded.Name)
+	assert.Equal(t, len(fileContent), downloaded.Size)
+	content, err := io.ReadAll(downloaded.File)
+	assert.NoError(t, err)
+	assert.Equal(t, fileContent, content)
+}
+
+func TestUploadDownloadLargeFile(t *testing.T) {
+	mockDataRepo := new(mockDataRepo)
+	mockUserFileRepo := new(mockUserFileRepo)
+	mockAuthService := new(mockAuthService)
+	mockKeyService := new(MockKeyService)
+
+	service := NewFileService(mockDataRepo, mockUserFileRepo, mockAuthService, mockKeyService)
+
+	ctx := context.Background()
+	user := "test-user"
+	key := "352fa5gdhvdryhwr"
+	fileContent := make([]byte, 2*chunkRowSize+100)
+	_, err := rand.Read(fileContent)
+	assert.NoError(t, err)
+
+	var savedData entity.Data
+	var savedFile entity.FileRepo
+	chunks := &chunkStore{}
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockDataRepo.On("Insert", ctx, mock.MatchedBy(func(data entity.Data) bool {
+		savedData = data
+		return true
+	})).Return(nil)
+	mockUserFileRepo.On("Insert", ctx, mock.MatchedBy(func(data entity.FileRepo) bool {
+		savedFile = data
+		return true
+	})).Return(nil)
+	chunks.mock(mockUserFileRepo)
+
+	// file is read in small pieces, as it comes from request body
+	resp, err := service.UploadFile(ctx, handlers.UploadFileRequest{Name: "test-file", Format: "bin", File: iotest.HalfReader(bytes.NewReader(fileContent))})
+	assert.NoError(t, err)
+
+	assert.Len(t, chunks.chunks, 3)
+	for _, chunk := range chunks.chunks {
+		assert.LessOrEqual(t, len(chunk), chunkRowSize)
+	}
+
+	mockDataRepo.On("GetByUUID", ctx, user, resp.UUID).Return(&savedData, nil)
+	mockUserFileRepo.On("GetByUUID", ctx, user, resp.UUID).Return(&savedFile, nil)
+
+	downloaded, err := service.DownloadFile(ctx, handlers.DownloadFileRequest{UUID: resp.UUID})
+	assert.NoError(t, err)
+	assert.Equal(t, len(fileContent), downloaded.Size)
+	content, err := io.ReadAll(downloaded.File)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(fileContent, content))
+
+	// dropped chunk is detected
+	chunks.chunks = chunks.chunks[:2]
+	downloaded, err = service.DownloadFile(ctx, handlers.DownloadFileRequest{UUID: resp.UUID})
+	assert.NoError(t, err)
+	_, err = io.ReadAll(downloaded.File)
+	assert.ErrorIs(t, err, lib.ErrIntegrity)
+}
+
+func TestUploadFileReadError(t *testing.T) {
+	mockDataRepo := new(mockDataRepo)
+	mockUserFileRepo := new(mockUserFileRepo)
+	mockAuthService := new(mockAuthService)
+	mockKeyService := new(MockKeyService)
+
+	service := NewFileService(mockDataRepo, mockUserFileRepo, mockAuthService, mockKeyService)
+
+	ctx := context.Background()
+	user := "test-user"
+	key := "352fa5gdhvdryhwr"
+	readErr := errors.New("connection reset")
+
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockUserFileRepo.On("Insert", ctx, mock.AnythingOfType("entity.FileRepo")).Return(nil)
+	mockUserFileRepo.On("Delete", mock.Anything, user, mock.Anything).Return(nil)
+
+	_, err := service.UploadFile(ctx, handlers.UploadFileRequest{Name: "test-file", Format: "txt", File: iotest.ErrReader(readErr)})
+	assert.ErrorIs(t, err, readErr)
+
+	// partially uploaded file is removed and metadata is not saved
+	mockUserFileRepo.AssertCalled(t, "Delete", mock.Anything, user, mock.Anything)
+	mockDataRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestDownloadFileIntegrity(t *testing.T) {
+	mockDataRepo := new(mockDataRepo)
+	mockUserFileRepo := new(mockUserFileRepo)
+	mockAuthService := new(mockAuthService)
+	mockKeyService := new(MockKeyService)
+
+	service := NewFileService(mockDataRepo, mockUserFileRepo, mockAuthService, mockKeyService)
+
+	ctx := context.Background()
+	user := "test-user"
+	key := "352fa5gdhvdryhwr"
+
+	var savedData entity.Data
+	var savedFile entity.FileRepo
+	chunks := &chunkStore{}
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockDataRepo.On("Insert", ctx, mock.MatchedBy(func(data entity.Data) bool {
+		savedData = data
+		return true
+	})).Return(nil)
+	mockUserFileRepo.On("Insert", ctx, mock.MatchedBy(func(data entity.FileRepo) bool {
+		savedFile = data
+		return true
+	})).Return(nil)
+	chunks.mock(mockUserFileRepo)
+
+	_, err := service.UploadFile(ctx, handlers.UploadFileRequest{Name: "test-file", Format: "txt", File: bytes.NewReader([]byte("test file content"))})
+	assert.NoError(t, err)
+
+	assertIntegrityError := func(err error, uuid string) {
+		var customErr *customerr.CustomError
+		assert.ErrorAs(t, err, &customErr)
+		assert.Equal(t, customerr.INTEGRITY_CHECK_FAILED+": "+uuid, customErr.Message)
+		assert.Equal(t, http.StatusInternalServerError, customErr.Code)
+		assert.ErrorIs(t, err, lib.ErrIntegrity)
+	}
+
+	// metadata relabeled as another content type
+	relabeled := savedData
+	relabeled.UUID = uuid.New().String()
+	relabeled.ContentType = entity.Note
+	mockDataRepo.On("GetByUUID", ctx, user, relabeled.UUID).Return(&relabeled, nil)
+	_, err = service.DownloadFile(ctx, handlers.DownloadFileRequest{UUID: relabeled.UUID})
+	assertIntegrityError(err, relabeled.UUID)
+
+	// file content swapped into another file with valid metadata
+	other := entity.Data{UUID: uuid.New().String(), ContentType: entity.File, CreatedAt: time.Now(), CreatedBy: user}
+	other.Content, other.DataKey, err = lib.SealEnvelope(key, []byte(`{"name":"other","format":"txt","size":17}`), other.AssociatedData())
+	assert.NoError(t, err)
+	swapped := savedFile
+	swapped.UUID = other.UUID
+	mockDataRepo.On("GetByUUID", ctx, user, other.UUID).Return(&other, nil)
+	mockUserFileRepo.On("GetByUUID", ctx, user, other.UUID).Return(&swapped, nil)
+	_, err = service.DownloadFile(ctx, handlers.DownloadFileRequest{UUID: other.UUID})
+	assertIntegrityError(err, other.UUID)
+
+	// relabeled metadata fails listing too
+	mockDataRepo.On("GetByUser", ctx, user, entity.File).Return([]*entity.Data{&other, &relabeled}, nil)
+	_, err = service.GetAllFiles(ctx, handlers.GetAllFilesRequest{})
+	assertIntegrityError(err, relabeled.UUID)
 }
@@ -0,0 +1,328 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+// FileService removes attached scans together with file_repository rows
+type FileService interface {
+	DeleteFile(ctx context.Context, r handlers.DeleteFileRequest) (*handlers.DeleteFileResponse, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type identityContent struct {
+	Kind           string   `json:"kind"`
+	Number         string   `json:"number"`
+	IssuingCountry string   `json:"issuing_country"`
+	IssueDate      string   `json:"issue_date"`
+	ExpiryDate     string   `json:"expiry_date"`
+	FullName       string   `json:"full_name"`
+	Scans          []string `json:"scans"`
+}
+
+type Service struct {
+	repo        Repo
+	fileService FileService
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewIdentityService(repo Repo, fileService FileService, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, fileService: fileService, keyService: keyService, authService: authService}
+}
+
+// Create identity document
+func (s *Service) Create(ctx context.Context, r handlers.CreateIdentityRequest) (*handlers.CreateIdentityResponse, error) {
+	content := identityContent{
+		Kind:           r.Kind,
+		Number:         r.Number,
+		IssuingCountry: strings.ToUpper(r.IssuingCountry),
+		IssueDate:      r.IssueDate,
+		ExpiryDate:     r.ExpiryDate,
+		FullName:       r.FullName,
+		Scans:          r.Scans,
+	}
+	if err := validateIdentity(content); err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.checkScans(ctx, user, content.Scans); err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.Identity,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateIdentityResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Update update identity document, detached scans stay in files
+func (s *Service) Update(ctx context.Context, r handlers.UpdateIdentityRequest) (*handlers.UpdateIdentityResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, content, err := s.get(ctx, user, key, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setContentToUpdate(r, content)
+	if err = validateIdentity(*content); err != nil {
+		return nil, err
+	}
+	if r.Scans != nil {
+		if err = s.checkScans(ctx, user, content.Scans); err != nil {
+			return nil, err
+		}
+	}
+
+	jsonData, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Update(ctx, *fromDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateIdentityResponse{UUID: fromDB.UUID}, nil
+}
+
+func (s *Service) setContentToUpdate(r handlers.UpdateIdentityRequest, content *identityContent) {
+	if r.Kind != nil {
+		content.Kind = *r.Kind
+	}
+	if r.Number != nil {
+		content.Number = *r.Number
+	}
+	if r.IssuingCountry != nil {
+		content.IssuingCountry = strings.ToUpper(*r.IssuingCountry)
+	}
+	if r.IssueDate != nil {
+		content.IssueDate = *r.IssueDate
+	}
+	if r.ExpiryDate != nil {
+		content.ExpiryDate = *r.ExpiryDate
+	}
+	if r.FullName != nil {
+		content.FullName = *r.FullName
+	}
+	if r.Scans != nil {
+		content.Scans = r.Scans
+	}
+}
+
+// Delete delete identity document and its attached scans, scans attached to other documents are kept
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteIdentityRequest) (*handlers.DeleteIdentityResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, content, err := s.get(ctx, user, key, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// scan attached to another document of the user stays with it
+	keep, err := s.attachedScans(ctx, user, key, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// scans are removed after the document, a failure leaves them visible in files
+	for _, scan := range content.Scans {
+		if keep[scan] {
+			continue
+		}
+		if _, err = s.fileService.DeleteFile(ctx, handlers.DeleteFileRequest{UUID: scan}); err != nil {
+			return nil, err
+		}
+		keep[scan] = true
+	}
+
+	return &handlers.DeleteIdentityResponse{UUID: r.UUID}, nil
+}
+
+// GetAll get all identity documents
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllIdentitiesRequest) (*handlers.GetAllIdentitiesResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.Identity)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllIdentitiesResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		item := handlers.GetAllIdentitiesResponseItem{}
+		err = json.Unmarshal(jsonDecrypted, &item)
+		if err != nil {
+			return nil, err
+		}
+		item.UUID = v.UUID
+		items = append(items, item)
+	}
+
+	return &handlers.GetAllIdentitiesResponse{Items: items}, nil
+}
+
+// get load and decrypt identity document
+func (s *Service) get(ctx context.Context, user string, key string, uuid string) (*entity.Data, *identityContent, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromDB.ContentType != entity.Identity {
+		return nil, nil, invalidIdentity("%s is not an identity document", uuid)
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content := identityContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fromDB, &content, nil
+}
+
+// attachedScans scans attached to identity documents of the user except document with uuid
+func (s *Service) attachedScans(ctx context.Context, user string, key string, uuid string) (map[string]bool, error) {
+	data, err := s.repo.GetByUser(ctx, user, entity.Identity)
+	if err != nil {
+		return nil, err
+	}
+
+	scans := make(map[string]bool)
+	for _, v := range data {
+		if v.UUID == uuid {
+			continue
+		}
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		content := identityContent{}
+		err = json.Unmarshal(jsonDecrypted, &content)
+		if err != nil {
+			return nil, err
+		}
+		for _, scan := range content.Scans {
+			scans[scan] = true
+		}
+	}
+	return scans, nil
+}
+
+// checkScans check every scan is a file of the user
+func (s *Service) checkScans(ctx context.Context, user string, scans []string) error {
+	for _, scan := range scans {
+		fromDB, err := s.repo.GetByUUID(ctx, user, scan)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return customerr.ErrorWithCode(customerr.SCAN_NOT_FOUND, http.StatusBadRequest)
+		}
+		if err != nil {
+			return err
+		}
+		if fromDB.ContentType != entity.File {
+			return customerr.ErrorWithCode(customerr.SCAN_NOT_FOUND, http.StatusBadRequest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIdentity(t *testing.T) {
+	valid := identityContent{
+		Kind:           string(Passport),
+		Number:         "AB1234567",
+		IssuingCountry: "DE",
+		IssueDate:      "2020-01-15",
+		ExpiryDate:     "2030-01-14",
+		FullName:       "Erika Mustermann",
+	}
+	assert.NoError(t, validateIdentity(valid))
+
+	tests := []struct {
+		name   string
+		modify func(c *identityContent)
+		want   string
+	}{
+		{name: "kind", modify: func(c *identityContent) { c.Kind = "visa" }, want: "unknown document kind"},
+		{name: "number", modify: func(c *identityContent) { c.Number = "" }, want: "number is required"},
+		{name: "country", modify: func(c *identityContent) { c.IssuingCountry = "DEU" }, want: "ISO 3166-1"},
+		{name: "issue date", modify: func(c *identityContent) { c.IssueDate = "15.01.2020" }, want: "issue date"},
+		{name: "expiry before issue", modify: func(c *identityContent) { c.ExpiryDate = "2019-01-01" }, want: "after issue date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := valid
+			tt.modify(&content)
+			err := validateIdentity(content)
+			assert.ErrorContains(t, err, customerr.INVALID_IDENTITY)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	valid.ExpiryDate = ""
+	assert.NoError(t, validateIdentity(valid))
+}
+
+func TestIdentityService_Create(t *testing.T) {
+	mockRepo := new(MockIdentityRepo)
+	mockFileService := new(MockFileService)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewIdentityService(mockRepo, mockFileService, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	scanUUID := uuid.New().String()
+	missingUUID := uuid.New().String()
+	noteUUID := uuid.New().String()
+	brokenUUID := uuid.New().String()
+	request := handlers.CreateIdentityRequest{
+		Kind:           string(DriverLicense),
+		Number:         "B072RRE2I55",
+		IssuingCountry: "de",
+		IssueDate:      "2021-05-01",
+		FullName:       "Erika Mustermann",
+		Scans:          []string{scanUUID},
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, scanUUID).Return(&entity.Data{UUID: scanUUID, ContentType: entity.File}, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, missingUUID).Return((*entity.Data)(nil), pgx.ErrNoRows)
+	mockRepo.On("GetByUUID", mock.Anything, user, noteUUID).Return(&entity.Data{UUID: noteUUID, ContentType: entity.Note}, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, brokenUUID).Return((*entity.Data)(nil), errors.New("connection refused"))
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, request)
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+
+	request.Scans = []string{missingUUID}
+	_, err = service.Create(ctx, request)
+	assert.EqualError(t, err, customerr.SCAN_NOT_FOUND)
+
+	request.Scans = []string{noteUUID}
+	_, err = service.Create(ctx, request)
+	assert.EqualError(t, err, customerr.SCAN_NOT_FOUND)
+
+	// database error is not reported as missing scan
+	request.Scans = []string{brokenUUID}
+	_, err = service.Create(ctx, request)
+	assert.EqualError(t, err, "connection refused")
+
+	mockRepo.AssertNumberOfCalls(t, "Insert", 1)
+}
+
+func TestIdentityService_Delete(t *testing.T) {
+	mockRepo := new(MockIdentityRepo)
+	mockFileService := new(MockFileService)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewIdentityService(mockRepo, mockFileService, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	scans := []string{uuid.New().String(), uuid.New().String()}
+	jsonContent, _ := json.Marshal(&identityContent{Kind: string(Passport), Scans: scans})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.Identity}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Identity).Return([]*entity.Data{&data}, nil)
+	mockRepo.On("Delete", mock.Anything, user, uuidStr).Return(nil)
+	for _, scan := range scans {
+		mockFileService.On("DeleteFile", mock.Anything, handlers.DeleteFileRequest{UUID: scan}).
+			Return(&handlers.DeleteFileResponse{UUID: scan}, nil)
+	}
+
+	response, err := service.Delete(ctx, handlers.DeleteIdentityRequest{UUID: uuidStr})
+
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+	mockRepo.AssertExpectations(t)
+	mockFileService.AssertExpectations(t)
+}
+
+func TestIdentityService_DeleteSharedScan(t *testing.T) {
+	mockRepo := new(MockIdentityRepo)
+	mockFileService := new(MockFileService)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewIdentityService(mockRepo, mockFileService, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	shared := uuid.New().String()
+	own := uuid.New().String()
+	newIdentity := func(scans ...string) *entity.Data {
+		data := &entity.Data{UUID: uuid.New().String(), ContentType: entity.Identity, CreatedBy: user}
+		jsonContent, _ := json.Marshal(&identityContent{Kind: string(Passport), Scans: scans})
+		data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+		return data
+	}
+	passport := newIdentity(shared, own, own)
+	license := newIdentity(shared)
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, passport.UUID).Return(passport, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Identity).Return([]*entity.Data{passport, license}, nil)
+	mockRepo.On("Delete", mock.Anything, user, passport.UUID).Return(nil)
+	mockFileService.On("DeleteFile", mock.Anything, handlers.DeleteFileRequest{UUID: own}).
+		Return(&handlers.DeleteFileResponse{UUID: own}, nil)
+
+	_, err := service.Delete(ctx, handlers.DeleteIdentityRequest{UUID: passport.UUID})
+
+	require.NoError(t, err)
+	mockFileService.AssertNumberOfCalls(t, "DeleteFile", 1)
+	mockFileService.AssertNotCalled(t, "DeleteFile", mock.Anything, handlers.DeleteFileRequest{UUID: shared})
+}
+
+func TestIdentityService_DeleteNotIdentity(t *testing.T) {
+	mockRepo := new(MockIdentityRepo)
+	mockFileService := new(MockFileService)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewIdentityService(mockRepo, mockFileService, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&entity.Data{UUID: uuidStr, ContentType: entity.Note}, nil)
+
+	_, err := service.Delete(ctx, handlers.DeleteIdentityRequest{UUID: uuidStr})
+
+	assert.ErrorContains(t, err, customerr.INVALID_IDENTITY)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, user, uuidStr)
+	mockFileService.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,72 @@
+package identity
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdentityRepo is a mock implementation of Repo
+type MockIdentityRepo struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepo) Insert(ctx context.Context, data entity.Data) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockIdentityRepo) Update(ctx context.Context, data entity.Data) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockIdentityRepo) Delete(ctx context.Context, user string, uuid string) error {
+	args := m.Called(ctx, user, uuid)
+	return args.Error(0)
+}
+
+func (m *MockIdentityRepo) GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	args := m.Called(ctx, user, uuid)
+	return args.Get(0).(*entity.Data), args.Error(1)
+}
+
+func (m *MockIdentityRepo) GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error) {
+	args := m.Called(ctx, user, contentType)
+	return args.Get(0).([]*entity.Data), args.Error(1)
+}
+
+// MockKeyService is a mock implementation of KeyService
+type MockKeyService struct {
+	mock.Mock
+}
+
+func (m *MockKeyService) GetKeyForUser(user string) (string, error) {
+	args := m.Called(user)
+	return args.String(0), args.Error(1)
+}
+
+// MockAuthService is a mock implementation of AuthService
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) GetUserFromContext(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+// MockFileService is a mock implementation of FileService
+type MockFileService struct {
+	mock.Mock
+}
+
+func (m *MockFileService) DeleteFile(ctx context.Context, r handlers.DeleteFileRequest) (*handlers.DeleteFileResponse, error) {
+	args := m.Called(ctx, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*handlers.DeleteFileResponse), args.Error(1)
+}
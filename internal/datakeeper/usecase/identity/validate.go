@@ -0,0 +1,71 @@
+package identity
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+)
+
+// Kind identity document kind
+type Kind string
+
+const (
+	Passport      Kind = "passport"
+	IDCard        Kind = "id_card"
+	DriverLicense Kind = "driver_license"
+)
+
+// dateLayout issue and expiry date format
+const dateLayout = time.DateOnly
+
+func invalidIdentity(format string, args ...interface{}) error {
+	return customerr.ErrorWithCode(customerr.INVALID_IDENTITY+": "+fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
+
+// validateIdentity check document kind, country and dates
+func validateIdentity(content identityContent) error {
+	switch Kind(content.Kind) {
+	case Passport, IDCard, DriverLicense:
+	default:
+		return invalidIdentity("unknown document kind %q", content.Kind)
+	}
+	if content.Number == "" {
+		return invalidIdentity("number is required")
+	}
+	if content.FullName == "" {
+		return invalidIdentity("full name is required")
+	}
+	if !isCountryCode(content.IssuingCountry) {
+		return invalidIdentity("issuing country must be ISO 3166-1 alpha-2 code")
+	}
+
+	issued, err := time.Parse(dateLayout, content.IssueDate)
+	if err != nil {
+		return invalidIdentity("issue date must be in YYYY-MM-DD format")
+	}
+	if content.ExpiryDate == "" {
+		return nil
+	}
+	expires, err := time.Parse(dateLayout, content.ExpiryDate)
+	if err != nil {
+		return invalidIdentity("expiry date must be in YYYY-MM-DD format")
+	}
+	if !expires.After(issued) {
+		return invalidIdentity("expiry date must be after issue date")
+	}
+	return nil
+}
+
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package key
+
+import "sync"
+
+// MapCache process-local cache of unlocked user keys
+type MapCache struct {
+	sessions map[string]Session
+	mu       sync.RWMutex
+}
+
+func NewMapCache() *MapCache {
+	return &MapCache{sessions: make(map[string]Session)}
+}
+
+// Get session of user
+func (c *MapCache) Get(user string) (Session, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	session, ok := c.sessions[user]
+	return session, ok
+}
+
+// Set session of user
+func (c *MapCache) Set(user string, session Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[user] = session
+}
+
+// Delete session of user
+func (c *MapCache) Delete(user string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, user)
+}
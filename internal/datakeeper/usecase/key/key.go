@@ -1,47 +1,198 @@
 package key
 
 import (
-	"math/rand"
-	"sync"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
 	"time"
 
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
 	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"golang.org/x/crypto/argon2"
 )
 
+const (
+	// saltSize size of per-user salt
+	saltSize = 16
+	// keySize size of derived key
+	keySize = 32
+	// minMasterPasswordLength min length of master password
+	minMasterPasswordLength = 8
+)
+
+// checkInfo message authenticated by key check value
+var checkInfo = []byte("data-keeper key check")
+
+// Session unlocked key of user
+type Session struct {
+	// Key user key
+	Key string
+	// UnlockedAt when key was unlocked, used for absolute lifetime
+	UnlockedAt time.Time
+	// LastUsedAt when key was used last time, used for idle timeout
+	LastUsedAt time.Time
+}
+
+// Cache process-local cache of unlocked user keys
+type Cache interface {
+	Get(user string) (Session, bool)
+	Set(user string, session Session)
+	Delete(user string)
+}
+
+// Store persistent store of user keys shared by replicas, Get returns empty key and SavedAt zero time
+// if user has no stored key
+type Store interface {
+	Get(ctx context.Context, user string) (string, time.Time, error)
+	Set(ctx context.Context, user string, key string) (time.Time, error)
+	SavedAt(ctx context.Context, user string) (time.Time, error)
+	Delete(ctx context.Context, user string) error
+}
+
 type Service struct {
-	keys map[string]string
-	mu   sync.RWMutex
+	keys  Cache
+	store Store
+	kdf   config.KDF
+	vault config.Vault
+	now   func() time.Time
 }
 
-func NewKeyService() *Service {
-	rand.NewSource(int64(time.Now().Nanosecond()))
-	return &Service{keys: make(map[string]string)}
+// NewKeyService create key service, keys are kept only in cache if store is nil
+func NewKeyService(kdf config.KDF, vault config.Vault, cache Cache, store Store) *Service {
+	return &Service{keys: cache, store: store, kdf: kdf, vault: vault, now: time.Now}
 }
 
+// GetKeyForUser unlocked key of user, vault is locked if key is idle or older than its lifetime.
+// Cached key is checked against store, so lock or new key on another replica is seen at once.
+// Idle time is tracked per replica, cache miss starts it again
 func (s *Service) GetKeyForUser(user string) (string, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	key, ok := s.keys[user]
+	now := s.now()
+
+	session, ok := s.keys.Get(user)
+	if ok && s.store != nil {
+		savedAt, err := s.store.SavedAt(context.Background(), user)
+		if err != nil {
+			return "", err
+		}
+		if !savedAt.Equal(session.UnlockedAt) {
+			s.keys.Delete(user)
+			ok = false
+		}
+	}
 	if !ok {
-		return "", customerr.Error(customerr.NO_KEY_IN_CONTEXT)
+		if s.store == nil {
+			return "", customerr.ErrorWithCode(customerr.VAULT_LOCKED, http.StatusLocked)
+		}
+		key, unlockedAt, err := s.store.Get(context.Background(), user)
+		if err != nil {
+			return "", err
+		}
+		if key == "" {
+			return "", customerr.ErrorWithCode(customerr.VAULT_LOCKED, http.StatusLocked)
+		}
+		session = Session{Key: key, UnlockedAt: unlockedAt, LastUsedAt: now}
 	}
-	return key, nil
+
+	if s.expired(session, now) {
+		if err := s.Lock(user); err != nil {
+			return "", err
+		}
+		return "", customerr.ErrorWithCode(customerr.VAULT_LOCKED, http.StatusLocked)
+	}
+
+	session.LastUsedAt = now
+	s.keys.Set(user, session)
+	return session.Key, nil
 }
 
+// SetKeyForUser unlock vault of user with key
 func (s *Service) SetKeyForUser(user string, key string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.keys[user] = key
+	now := s.now()
+	unlockedAt := now
+	if s.store != nil {
+		savedAt, err := s.store.Set(context.Background(), user, key)
+		if err != nil {
+			return err
+		}
+		// same time as in store, cached key is valid while it is not changed
+		unlockedAt = savedAt
+	}
+	s.keys.Set(user, Session{Key: key, UnlockedAt: unlockedAt, LastUsedAt: now})
 	return nil
 }
 
-var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+// Lock vault of user, key is removed from store and cache
+func (s *Service) Lock(user string) error {
+	if s.store != nil {
+		if err := s.store.Delete(context.Background(), user); err != nil {
+			return err
+		}
+	}
+	s.keys.Delete(user)
+	return nil
+}
 
-func (s *Service) GenerateKey() (string, error) {
-	const length = 32
-	b := make([]rune, length)
-	for i := range b {
-		b[i] = letterRunes[rand.Intn(len(letterRunes))]
+// expired session is idle or older than lifetime, zero timeout is disabled
+func (s *Service) expired(session Session, now time.Time) bool {
+	if s.vault.IdleTimeout > 0 && now.Sub(session.LastUsedAt) > s.vault.IdleTimeout {
+		return true
 	}
-	return string(b), nil
+	return s.vault.MaxLifetime > 0 && now.Sub(session.UnlockedAt) > s.vault.MaxLifetime
+}
+
+// NewKeyParams derive key from master password with new random salt and configured Argon2id parameters,
+// returned parameters must be stored to derive the same key at login
+func (s *Service) NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error) {
+	if len(masterPassword) < minMasterPasswordLength {
+		return "", nil, customerr.ErrorWithCode(customerr.WEAK_MASTER_PASSWORD, http.StatusBadRequest)
+	}
+
+	params := &entity.KeyParams{
+		Login:     user,
+		Salt:      make([]byte, saltSize),
+		Time:      uint32(s.kdf.Time),
+		Memory:    uint32(s.kdf.MemoryMB) * 1024,
+		Threads:   uint8(s.kdf.Threads),
+		CreatedAt: time.Now(),
+	}
+	if _, err := io.ReadFull(rand.Reader, params.Salt); err != nil {
+		return "", nil, err
+	}
+
+	key := deriveKey(masterPassword, params)
+	params.CheckValue = checkValue(key)
+
+	return key, params, nil
+}
+
+// UnlockKey derive key from master password with stored parameters, wrong master password is rejected by key check value
+func (s *Service) UnlockKey(masterPassword string, params *entity.KeyParams) (string, error) {
+	key := deriveKey(masterPassword, params)
+	if !hmac.Equal(checkValue(key), params.CheckValue) {
+		return "", customerr.ErrorWithCode(customerr.INVALID_MASTER_PASSWORD, http.StatusUnauthorized)
+	}
+	return key, nil
+}
+
+// CheckValue key check value, stored to verify key rebuilt or derived later
+func (s *Service) CheckValue(key string) []byte {
+	return checkValue(key)
+}
+
+// deriveKey Argon2id key, hex encoded
+func deriveKey(masterPassword string, params *entity.KeyParams) string {
+	key := argon2.IDKey([]byte(masterPassword), params.Salt, params.Time, params.Memory, params.Threads, keySize)
+	return hex.EncodeToString(key)
+}
+
+// checkValue HMAC of constant message, reveals nothing about key
+func checkValue(key string) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(checkInfo)
+	return mac.Sum(nil)
 }
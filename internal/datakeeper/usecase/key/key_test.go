@@ -1,15 +1,22 @@
 package key
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
 	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 )
 
+// testKDF cheap Argon2id parameters for tests
+var testKDF = config.KDF{Time: 1, MemoryMB: 1, Threads: 1}
+
 func TestGetKeyForUser(t *testing.T) {
-	service := NewKeyService()
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), nil)
 
 	err := service.SetKeyForUser("user123", "some_key")
 	assert.NoError(t, err)
@@ -20,16 +27,129 @@ func TestGetKeyForUser(t *testing.T) {
 
 	_, err = service.GetKeyForUser("nonexistent_user")
 	assert.Error(t, err)
-	assert.Equal(t, customerr.NO_KEY_IN_CONTEXT, err.Error())
+	assert.Equal(t, customerr.VAULT_LOCKED, err.Error())
 }
 
 func TestSetKeyForUser(t *testing.T) {
-	service := NewKeyService()
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), nil)
 
 	err := service.SetKeyForUser("user123", "some_key")
 	assert.NoError(t, err)
 
-	userKey, ok := service.keys["user123"]
+	session, ok := service.keys.Get("user123")
 	assert.True(t, ok)
+	assert.Equal(t, "some_key", session.Key)
+}
+
+func TestNewKeyParams(t *testing.T) {
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), nil)
+
+	key, params, err := service.NewKeyParams("user123", "master password")
+	require.NoError(t, err)
+	assert.Len(t, key, 64)
+	assert.Equal(t, "user123", params.Login)
+	assert.Len(t, params.Salt, saltSize)
+	assert.Equal(t, uint32(1), params.Time)
+	assert.Equal(t, uint32(1024), params.Memory)
+	assert.Equal(t, uint8(1), params.Threads)
+	assert.NotEmpty(t, params.CheckValue)
+
+	// same master password gives different key for different salt
+	other, _, err := service.NewKeyParams("user456", "master password")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	_, _, err = service.NewKeyParams("user123", "short")
+	assert.EqualError(t, err, customerr.WEAK_MASTER_PASSWORD)
+}
+
+func TestUnlockKey(t *testing.T) {
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), nil)
+
+	key, params, err := service.NewKeyParams("user123", "master password")
+	require.NoError(t, err)
+
+	// key is derived with stored parameters even if config changed
+	service = NewKeyService(config.KDF{Time: 2, MemoryMB: 2, Threads: 2}, config.Vault{}, NewMapCache(), nil)
+	unlocked, err := service.UnlockKey("master password", params)
+	require.NoError(t, err)
+	assert.Equal(t, key, unlocked)
+
+	_, err = service.UnlockKey("wrong password", params)
+	assert.EqualError(t, err, customerr.INVALID_MASTER_PASSWORD)
+}
+
+func TestCheckValue(t *testing.T) {
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), nil)
+
+	key, params, err := service.NewKeyParams("user123", "master password")
+	require.NoError(t, err)
+	assert.Equal(t, params.CheckValue, service.CheckValue(key))
+	assert.NotEqual(t, params.CheckValue, service.CheckValue("another key"))
+}
+
+func TestVaultIdleTimeout(t *testing.T) {
+	now := time.Now()
+	service := NewKeyService(testKDF, config.Vault{IdleTimeout: 15 * time.Minute, MaxLifetime: time.Hour}, NewMapCache(), nil)
+	service.now = func() time.Time { return now }
+
+	err := service.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+
+	// use extends idle timeout
+	now = now.Add(10 * time.Minute)
+	_, err = service.GetKeyForUser("user123")
+	require.NoError(t, err)
+	now = now.Add(10 * time.Minute)
+	_, err = service.GetKeyForUser("user123")
+	require.NoError(t, err)
+
+	now = now.Add(16 * time.Minute)
+	_, err = service.GetKeyForUser("user123")
+	assert.EqualError(t, err, customerr.VAULT_LOCKED)
+	var customErr *customerr.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, http.StatusLocked, customErr.Code)
+
+	_, ok := service.keys.Get("user123")
+	assert.False(t, ok)
+}
+
+func TestVaultMaxLifetime(t *testing.T) {
+	now := time.Now()
+	service := NewKeyService(testKDF, config.Vault{IdleTimeout: 15 * time.Minute, MaxLifetime: time.Hour}, NewMapCache(), nil)
+	service.now = func() time.Time { return now }
+
+	err := service.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+
+	// key is locked after lifetime even if it is used
+	for i := 0; i < 6; i++ {
+		now = now.Add(10 * time.Minute)
+		_, err = service.GetKeyForUser("user123")
+		require.NoError(t, err)
+	}
+	now = now.Add(10 * time.Minute)
+	_, err = service.GetKeyForUser("user123")
+	assert.EqualError(t, err, customerr.VAULT_LOCKED)
+}
+
+func TestLock(t *testing.T) {
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), nil)
+
+	err := service.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+
+	err = service.Lock("user123")
+	require.NoError(t, err)
+
+	_, err = service.GetKeyForUser("user123")
+	assert.EqualError(t, err, customerr.VAULT_LOCKED)
+
+	// vault is unlocked again with key
+	err = service.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+	userKey, err := service.GetKeyForUser("user123")
+	require.NoError(t, err)
 	assert.Equal(t, "some_key", userKey)
 }
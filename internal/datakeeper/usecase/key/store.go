@@ -0,0 +1,61 @@
+package key
+
+import (
+	"context"
+	"time"
+)
+
+type Repo interface {
+	Upsert(ctx context.Context, login string, wrappedKey []byte) (time.Time, error)
+	GetByLogin(ctx context.Context, login string) ([]byte, time.Time, error)
+	GetUpdatedAt(ctx context.Context, login string) (time.Time, error)
+	Delete(ctx context.Context, login string) error
+}
+
+// KEK server key-encryption key provider
+type KEK interface {
+	Wrap(ctx context.Context, plaintext []byte) ([]byte, error)
+	Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// WrappedStore persistent store of user keys wrapped by server key-encryption key
+type WrappedStore struct {
+	repo Repo
+	kek  KEK
+}
+
+func NewWrappedStore(repo Repo, kek KEK) *WrappedStore {
+	return &WrappedStore{repo: repo, kek: kek}
+}
+
+// Get unwrap key of user and time it was saved, empty if user has no stored key
+func (s *WrappedStore) Get(ctx context.Context, user string) (string, time.Time, error) {
+	wrappedKey, savedAt, err := s.repo.GetByLogin(ctx, user)
+	if err != nil || wrappedKey == nil {
+		return "", time.Time{}, err
+	}
+	key, err := s.kek.Unwrap(ctx, wrappedKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return string(key), savedAt, nil
+}
+
+// Set wrap and save key of user, returns time it was saved
+func (s *WrappedStore) Set(ctx context.Context, user string, key string) (time.Time, error) {
+	wrappedKey, err := s.kek.Wrap(ctx, []byte(key))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.repo.Upsert(ctx, user, wrappedKey)
+}
+
+// SavedAt time key of user was saved without unwrapping it, zero if user has no stored key
+func (s *WrappedStore) SavedAt(ctx context.Context, user string) (time.Time, error) {
+	return s.repo.GetUpdatedAt(ctx, user)
+}
+
+// Delete key of user
+func (s *WrappedStore) Delete(ctx context.Context, user string) error {
+	return s.repo.Delete(ctx, user)
+}
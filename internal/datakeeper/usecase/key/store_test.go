@@ -0,0 +1,160 @@
+package key
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
+
+// memRepo in-memory Repo
+type memRepo struct {
+	keys    map[string][]byte
+	savedAt map[string]time.Time
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{keys: make(map[string][]byte), savedAt: make(map[string]time.Time)}
+}
+
+func (r *memRepo) Upsert(ctx context.Context, login string, wrappedKey []byte) (time.Time, error) {
+	r.keys[login] = wrappedKey
+	r.savedAt[login] = time.Now()
+	return r.savedAt[login], nil
+}
+
+func (r *memRepo) GetUpdatedAt(ctx context.Context, login string) (time.Time, error) {
+	return r.savedAt[login], nil
+}
+
+func (r *memRepo) GetByLogin(ctx context.Context, login string) ([]byte, time.Time, error) {
+	return r.keys[login], r.savedAt[login], nil
+}
+
+func (r *memRepo) Delete(ctx context.Context, login string) error {
+	delete(r.keys, login)
+	delete(r.savedAt, login)
+	return nil
+}
+
+// testKEK key-encryption key provider with static key
+type testKEK struct {
+	key string
+}
+
+func (k testKEK) Wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return lib.Encrypt(k.key, plaintext)
+}
+
+func (k testKEK) Unwrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return lib.Decrypt(k.key, ciphertext)
+}
+
+func TestWrappedStore(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemRepo()
+	store := NewWrappedStore(repo, testKEK{"server-kek"})
+
+	setAt, err := store.Set(ctx, "user123", "some_key")
+	require.NoError(t, err)
+	assert.NotContains(t, string(repo.keys["user123"]), "some_key")
+
+	key, savedAt, err := store.Get(ctx, "user123")
+	require.NoError(t, err)
+	assert.Equal(t, "some_key", key)
+	assert.Equal(t, setAt, savedAt)
+
+	savedAt, err = store.SavedAt(ctx, "user123")
+	require.NoError(t, err)
+	assert.Equal(t, setAt, savedAt)
+
+	key, _, err = store.Get(ctx, "nonexistent_user")
+	require.NoError(t, err)
+	assert.Empty(t, key)
+
+	// key can not be unwrapped with another kek
+	other := NewWrappedStore(repo, testKEK{"other-kek"})
+	_, _, err = other.Get(ctx, "user123")
+	assert.ErrorIs(t, err, lib.ErrIntegrity)
+
+	err = store.Delete(ctx, "user123")
+	require.NoError(t, err)
+	key, _, err = store.Get(ctx, "user123")
+	require.NoError(t, err)
+	assert.Empty(t, key)
+}
+
+func TestGetKeyForUserFromStore(t *testing.T) {
+	store := NewWrappedStore(newMemRepo(), testKEK{"server-kek"})
+
+	service := NewKeyService(testKDF, config.Vault{}, NewMapCache(), store)
+	err := service.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+
+	// restarted or another replica with empty cache
+	cache := NewMapCache()
+	service = NewKeyService(testKDF, config.Vault{}, cache, store)
+	userKey, err := service.GetKeyForUser("user123")
+	require.NoError(t, err)
+	assert.Equal(t, "some_key", userKey)
+
+	cached, ok := cache.Get("user123")
+	assert.True(t, ok)
+	assert.Equal(t, "some_key", cached.Key)
+
+	// lock is shared by replicas through store
+	err = service.Lock("user123")
+	require.NoError(t, err)
+	service = NewKeyService(testKDF, config.Vault{}, NewMapCache(), store)
+	_, err = service.GetKeyForUser("user123")
+	assert.EqualError(t, err, customerr.VAULT_LOCKED)
+}
+
+func TestLockOnAnotherReplica(t *testing.T) {
+	store := NewWrappedStore(newMemRepo(), testKEK{"server-kek"})
+	replicaA := NewKeyService(testKDF, config.Vault{}, NewMapCache(), store)
+	replicaB := NewKeyService(testKDF, config.Vault{}, NewMapCache(), store)
+
+	err := replicaA.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+	userKey, err := replicaB.GetKeyForUser("user123")
+	require.NoError(t, err)
+	assert.Equal(t, "some_key", userKey)
+
+	// key cached on replica B is not used after lock on replica A
+	err = replicaA.Lock("user123")
+	require.NoError(t, err)
+	_, err = replicaB.GetKeyForUser("user123")
+	assert.EqualError(t, err, customerr.VAULT_LOCKED)
+
+	// new key set on replica A replaces key cached on replica B
+	err = replicaA.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+	_, err = replicaB.GetKeyForUser("user123")
+	require.NoError(t, err)
+	err = replicaA.SetKeyForUser("user123", "new_key")
+	require.NoError(t, err)
+	userKey, err = replicaB.GetKeyForUser("user123")
+	require.NoError(t, err)
+	assert.Equal(t, "new_key", userKey)
+}
+
+func TestMaxLifetimeFromStore(t *testing.T) {
+	store := NewWrappedStore(newMemRepo(), testKEK{"server-kek"})
+
+	service := NewKeyService(testKDF, config.Vault{MaxLifetime: time.Hour}, NewMapCache(), store)
+	err := service.SetKeyForUser("user123", "some_key")
+	require.NoError(t, err)
+
+	// lifetime is counted from time key was saved to store, not loaded to cache
+	service = NewKeyService(testKDF, config.Vault{MaxLifetime: time.Hour}, NewMapCache(), store)
+	service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = service.GetKeyForUser("user123")
+	assert.EqualError(t, err, customerr.VAULT_LOCKED)
+}
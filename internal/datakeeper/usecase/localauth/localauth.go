@@ -0,0 +1,111 @@
+package localauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minPasswordLength shortest accepted password
+const minPasswordLength = 8
+
+type Repo interface {
+	Insert(ctx context.Context, user entity.User) (bool, error)
+	GetByLogin(ctx context.Context, login string) (*entity.User, error)
+}
+
+// Service local auth provider, users and Argon2id password hashes are stored in users table,
+// tokens are signed by HS256 with JWT key. It replaces gRPC auth service on single-node deployments
+type Service struct {
+	repo   Repo
+	auth   config.AuthService
+	ttl    time.Duration
+	params hashParams
+	// dummyHash verified for unknown login, so response time does not reveal registered logins
+	dummyHash string
+}
+
+// NewLocalAuthService password hashes use Argon2id parameters of KDF config, tokens live as access tokens of session
+func NewLocalAuthService(repo Repo, auth config.AuthService, session config.Session, kdf config.KDF) (*Service, error) {
+	if auth.JWTKey == "" {
+		return nil, errors.New("JWT key is required for local auth provider")
+	}
+	s := &Service{
+		repo: repo,
+		auth: auth,
+		ttl:  session.AccessTokenTTL,
+		params: hashParams{
+			memory:  uint32(kdf.MemoryMB) * 1024,
+			time:    uint32(kdf.Time),
+			threads: uint8(kdf.Threads),
+		},
+	}
+	var err error
+	if s.dummyHash, err = hashPassword("dummy password", s.params); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Register create user with password hash
+func (s *Service) Register(ctx context.Context, login string, password string) error {
+	if login == "" || len(password) < minPasswordLength {
+		return customerr.ErrorWithCode(customerr.INVALID_REGISTRATION, http.StatusBadRequest)
+	}
+	hash, err := hashPassword(password, s.params)
+	if err != nil {
+		return err
+	}
+	created, err := s.repo.Insert(ctx, entity.User{Login: login, PasswordHash: hash, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	if !created {
+		return customerr.ErrorWithCode(customerr.USER_ALREADY_EXISTS, http.StatusConflict)
+	}
+	return nil
+}
+
+// Login check password and issue token, unknown login and wrong password give the same error
+func (s *Service) Login(ctx context.Context, login string, password string) (string, error) {
+	user, err := s.repo.GetByLogin(ctx, login)
+	if err != nil {
+		return "", err
+	}
+	hash := s.dummyHash
+	if user != nil {
+		hash = user.PasswordHash
+	}
+	ok, err := verifyPassword(password, hash)
+	if err != nil {
+		return "", err
+	}
+	if user == nil || !ok {
+		return "", customerr.ErrorWithCode(customerr.INVALID_CREDENTIALS, http.StatusUnauthorized)
+	}
+	return s.issueToken(login)
+}
+
+// issueToken sign token with claims checked by auth middleware
+func (s *Service) issueToken(login string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"email": login,
+		"iat":   now.Unix(),
+		"nbf":   now.Unix(),
+		"exp":   now.Add(s.ttl).Unix(),
+	}
+	if s.auth.JWTIssuer != "" {
+		claims["iss"] = s.auth.JWTIssuer
+	}
+	if s.auth.JWTAudience != "" {
+		claims["aud"] = s.auth.JWTAudience
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.auth.JWTKey))
+}
@@ -0,0 +1,142 @@
+package localauth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAuth = config.AuthService{JWTKey: "test-jwt-key", JWTIssuer: "data-keeper", JWTAudience: "data-keeper-api"}
+	// cheap parameters keep tests fast
+	testKDF = config.KDF{Time: 1, MemoryMB: 1, Threads: 1}
+)
+
+func newService(t *testing.T, repo Repo) *Service {
+	service, err := NewLocalAuthService(repo, testAuth, config.Session{AccessTokenTTL: 15 * time.Minute}, testKDF)
+	require.NoError(t, err)
+	return service
+}
+
+func assertCode(t *testing.T, err error, message string, code int) {
+	var customErr *customerr.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, message, customErr.Message)
+	assert.Equal(t, code, customErr.Code)
+}
+
+func TestLocalAuthService_Register(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepo)
+	service := newService(t, mockRepo)
+
+	var stored entity.User
+	mockRepo.On("Insert", mock.Anything, mock.MatchedBy(func(user entity.User) bool {
+		stored = user
+		return user.Login == "new@example.com"
+	})).Return(true, nil).Once()
+	mockRepo.On("Insert", mock.Anything, mock.Anything).Return(false, nil)
+
+	require.NoError(t, service.Register(ctx, "new@example.com", "password"))
+	assert.True(t, strings.HasPrefix(stored.PasswordHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.NotContains(t, stored.PasswordHash, "password")
+
+	err := service.Register(ctx, "existing@example.com", "password")
+	assertCode(t, err, customerr.USER_ALREADY_EXISTS, http.StatusConflict)
+
+	err = service.Register(ctx, "new@example.com", "short")
+	assertCode(t, err, customerr.INVALID_REGISTRATION, http.StatusBadRequest)
+	err = service.Register(ctx, "", "password")
+	assertCode(t, err, customerr.INVALID_REGISTRATION, http.StatusBadRequest)
+}
+
+func TestLocalAuthService_Login(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepo)
+	service := newService(t, mockRepo)
+
+	hash, err := hashPassword("password", service.params)
+	require.NoError(t, err)
+	mockRepo.On("GetByLogin", mock.Anything, "existing@example.com").
+		Return(&entity.User{Login: "existing@example.com", PasswordHash: hash}, nil)
+	mockRepo.On("GetByLogin", mock.Anything, "unknown@example.com").Return((*entity.User)(nil), nil)
+
+	token, err := service.Login(ctx, "existing@example.com", "password")
+	require.NoError(t, err)
+
+	// token passes checks of auth middleware
+	claims := jwt.MapClaims{}
+	_, err = jwt.NewParser(
+		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer("data-keeper"),
+		jwt.WithAudience("data-keeper-api"),
+	).ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(testAuth.JWTKey), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "existing@example.com", claims["email"])
+
+	// wrong password and unknown login are not distinguished
+	_, err = service.Login(ctx, "existing@example.com", "wrong password")
+	assertCode(t, err, customerr.INVALID_CREDENTIALS, http.StatusUnauthorized)
+	_, err = service.Login(ctx, "unknown@example.com", "password")
+	assertCode(t, err, customerr.INVALID_CREDENTIALS, http.StatusUnauthorized)
+}
+
+func TestNewLocalAuthService(t *testing.T) {
+	_, err := NewLocalAuthService(new(MockRepo), config.AuthService{}, config.Session{}, testKDF)
+	assert.Error(t, err)
+}
+
+func TestPasswordHash(t *testing.T) {
+	params := hashParams{memory: 1024, time: 1, threads: 1}
+	hash, err := hashPassword("password", params)
+	require.NoError(t, err)
+
+	// same password gets another salt
+	other, err := hashPassword("password", params)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	ok, err := verifyPassword("password", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = verifyPassword("Password", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// parameters are part of hash
+	ok, err = verifyPassword("password", strings.Replace(other, "m=1024,t=1", "m=1024,t=2", 1))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// hash made with other parameters is still verified
+	stronger, err := hashPassword("password", hashParams{memory: 2048, time: 2, threads: 2})
+	require.NoError(t, err)
+	ok, err = verifyPassword("password", stronger)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	for _, invalid := range []string{
+		"",
+		"plain password",
+		"$2a$10$bcrypthashbcrypthashbcrypthashbcrypthashbcrypthashbc",
+		strings.Replace(hash, "v=19", "v=16", 1),
+		strings.Replace(hash, "m=1024", "m=many", 1),
+		hash[:strings.LastIndex(hash, "$")] + "$!!!",
+	} {
+		_, err = verifyPassword("password", invalid)
+		assert.Error(t, err, "hash %q", invalid)
+	}
+}
@@ -0,0 +1,23 @@
+package localauth
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo is a mock implementation of Repo
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Insert(ctx context.Context, user entity.User) (bool, error) {
+	args := m.Called(ctx, user)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) GetByLogin(ctx context.Context, login string) (*entity.User, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(*entity.User), args.Error(1)
+}
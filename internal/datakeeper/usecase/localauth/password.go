@@ -0,0 +1,71 @@
+package localauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Password hash in PHC string format
+//
+//	$argon2id$v=19$m=<memory KiB>,t=<time>,p=<threads>$<salt>$<hash>
+//
+// Parameters are stored with hash, so hashes stay verifiable after KDF config is changed.
+const (
+	saltSize = 16
+	hashSize = 32
+)
+
+var hashEncoding = base64.RawStdEncoding
+
+// hashParams Argon2id cost parameters
+type hashParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+// hashPassword Argon2id hash of password with random salt
+func hashPassword(password string, params hashParams) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+	hash := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, hashSize)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.time, params.threads,
+		hashEncoding.EncodeToString(salt), hashEncoding.EncodeToString(hash)), nil
+}
+
+// verifyPassword true if password matches hash, comparison is constant time
+func verifyPassword(password string, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, errors.New("unsupported password hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, errors.New("unsupported argon2id version")
+	}
+	var params hashParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return false, errors.New("invalid argon2id parameters")
+	}
+	salt, err := hashEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, errors.New("invalid argon2id salt")
+	}
+	want, err := hashEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false, errors.New("invalid argon2id hash")
+	}
+
+	got := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
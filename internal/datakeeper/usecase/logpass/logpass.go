@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
 	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
 	"github.com/google/uuid"
@@ -60,19 +61,18 @@ func (s *Service) Create(ctx context.Context, r handlers.CreateLogPassRequest) (
 		return nil, err
 	}
 
-	jsonEncrypted, err := lib.Encrypt(key, jsonData)
-	if err != nil {
-		return nil, err
-	}
-
 	newDataToSave := entity.Data{
 		UUID:        uuid.New().String(),
-		Content:     jsonEncrypted,
 		ContentType: entity.LogPass,
 		CreatedAt:   time.Now(),
 		CreatedBy:   user,
 	}
 
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.repo.Insert(ctx, newDataToSave)
 	if err != nil {
 		return nil, err
@@ -98,9 +98,9 @@ func (s *Service) Update(ctx context.Context, r handlers.UpdateLogPassRequest) (
 		return nil, err
 	}
 
-	jsonDecrypted, err := lib.Decrypt(key, fromDB.Content)
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
 	if err != nil {
-		return nil, err
+		return nil, customerr.IntegrityError(fromDB.UUID, err)
 	}
 
 	logPassContent := logPassContent{}
@@ -116,13 +116,11 @@ func (s *Service) Update(ctx context.Context, r handlers.UpdateLogPassRequest) (
 		return nil, err
 	}
 
-	jsonEncrypted, err := lib.Encrypt(key, jsonData)
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
 	if err != nil {
 		return nil, err
 	}
 
-	fromDB.Content = jsonEncrypted
-
 	err = s.repo.Update(ctx, *fromDB)
 	if err != nil {
 		return nil, err
@@ -177,9 +175,9 @@ func (s *Service) GetAll(ctx context.Context, r handlers.GetAllLogPassesRequest)
 
 	items := make([]handlers.GetAllLogPassResponseItem, 0, len(data))
 	for _, v := range data {
-		jsonDecrypted, err := lib.Decrypt(key, v.Content)
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
 		if err != nil {
-			return nil, err
+			return nil, customerr.IntegrityError(v.UUID, err)
 		}
 		item := handlers.GetAllLogPassResponseItem{}
 		err = json.Unmarshal(jsonDecrypted, &item)
@@ -3,9 +3,11 @@ package logpass
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
 	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
 	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
 	"github.com/google/uuid"
@@ -143,3 +145,94 @@ func TestLogPassService_GetAll(t *testing.T) {
 	mockKeyService.AssertCalled(t, "GetKeyForUser", user)
 	mockRepo.AssertCalled(t, "GetByUser", mock.Anything, user, entity.LogPass)
 }
+
+func TestLogPassService_Envelope(t *testing.T) {
+	mockRepo := new(MockLogPassRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := Service{
+		repo:        mockRepo,
+		keyService:  mockKeyService,
+		authService: mockAuthService,
+	}
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	request := handlers.CreateLogPassRequest{Name: "test_name", Login: "test_login", Password: "test_password"}
+
+	var saved entity.Data
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.MatchedBy(func(data entity.Data) bool {
+		saved = data
+		return true
+	})).Return(nil)
+
+	_, err := service.Create(ctx, request)
+	assert.NoError(t, err)
+
+	// content is encrypted with data key, data key is wrapped by user key
+	assert.NotEmpty(t, saved.DataKey)
+	_, err = lib.Decrypt(key, saved.Content)
+	assert.ErrorIs(t, err, lib.ErrIntegrity)
+
+	mockRepo.On("GetByUser", mock.Anything, user, entity.LogPass).Return([]*entity.Data{&saved}, nil)
+
+	response, err := service.GetAll(ctx, handlers.GetAllLogPassesRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, "test_login", response.Items[0].Login)
+	assert.Equal(t, "test_password", response.Items[0].Password)
+}
+
+func TestLogPassService_Integrity(t *testing.T) {
+	mockRepo := new(MockLogPassRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := Service{
+		repo:        mockRepo,
+		keyService:  mockKeyService,
+		authService: mockAuthService,
+	}
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+
+	var saved entity.Data
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.MatchedBy(func(data entity.Data) bool {
+		saved = data
+		return true
+	})).Return(nil)
+
+	_, err := service.Create(ctx, handlers.CreateLogPassRequest{Name: "test_name", Login: "test_login", Password: "test_password"})
+	assert.NoError(t, err)
+
+	// content copied to another row
+	moved := saved
+	moved.UUID = uuid.New().String()
+	// row relabeled as another content type
+	relabeled := saved
+	relabeled.ContentType = entity.Note
+	// row moved to another user
+	stolen := saved
+	stolen.CreatedBy = "another_user"
+
+	for _, data := range []entity.Data{moved, relabeled, stolen} {
+		mockRepo.On("GetByUUID", mock.Anything, user, data.UUID).Return(&data, nil).Once()
+		_, err = service.Update(ctx, handlers.UpdateLogPassRequest{UUID: data.UUID, Name: ptrString("new_name")})
+		var customErr *customerr.CustomError
+		assert.ErrorAs(t, err, &customErr)
+		assert.Equal(t, customerr.INTEGRITY_CHECK_FAILED+": "+data.UUID, customErr.Message)
+		assert.Equal(t, http.StatusInternalServerError, customErr.Code)
+		assert.ErrorIs(t, err, lib.ErrIntegrity)
+	}
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	mockRepo.On("GetByUser", mock.Anything, user, entity.LogPass).Return([]*entity.Data{&saved, &moved}, nil)
+	_, err = service.GetAll(ctx, handlers.GetAllLogPassesRequest{})
+	assert.ErrorContains(t, err, customerr.INTEGRITY_CHECK_FAILED+": "+moved.UUID)
+}
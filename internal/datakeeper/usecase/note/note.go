@@ -0,0 +1,216 @@
+package note
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+)
+
+// MaxNoteSize max size of note text in bytes, files have their own limit
+const MaxNoteSize = 64 * 1024
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type noteContent struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewNoteService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Create note
+func (s *Service) Create(ctx context.Context, r handlers.CreateNoteRequest) (*handlers.CreateNoteResponse, error) {
+	if len(r.Text) > MaxNoteSize {
+		return nil, customerr.ErrorWithCode(customerr.NOTE_TOO_LARGE, http.StatusBadRequest)
+	}
+
+	jsonData, err := json.Marshal(&noteContent{Title: r.Title, Text: r.Text})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.Note,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateNoteResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Update update note
+func (s *Service) Update(ctx context.Context, r handlers.UpdateNoteRequest) (*handlers.UpdateNoteResponse, error) {
+	if r.Text != nil && len(*r.Text) > MaxNoteSize {
+		return nil, customerr.ErrorWithCode(customerr.NOTE_TOO_LARGE, http.StatusBadRequest)
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	content := noteContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setContentToUpdate(r, &content)
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Update(ctx, *fromDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateNoteResponse{UUID: fromDB.UUID}, nil
+}
+
+func (s *Service) setContentToUpdate(r handlers.UpdateNoteRequest, content *noteContent) {
+	if r.Title != nil {
+		content.Title = *r.Title
+	}
+	if r.Text != nil {
+		content.Text = *r.Text
+	}
+}
+
+// Delete delete note
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteNoteRequest) (*handlers.DeleteNoteResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.load(ctx, user, r.UUID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteNoteResponse{UUID: r.UUID}, nil
+}
+
+// load note without decrypting it, row of another content type is not found
+func (s *Service) load(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fromDB.ContentType != entity.Note {
+		return nil, customerr.ErrorWithCode(customerr.NOTE_NOT_FOUND, http.StatusNotFound)
+	}
+	return fromDB, nil
+}
+
+// GetAll get all notes
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllNotesRequest) (*handlers.GetAllNotesResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllNotesResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		item := handlers.GetAllNotesResponseItem{}
+		err = json.Unmarshal(jsonDecrypted, &item)
+		if err != nil {
+			return nil, err
+		}
+		item.UUID = v.UUID
+		items = append(items, item)
+	}
+
+	return &handlers.GetAllNotesResponse{Items: items}, nil
+}
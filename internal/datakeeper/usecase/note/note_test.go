@@ -0,0 +1,157 @@
+package note
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNoteService_Create(t *testing.T) {
+	mockRepo := new(MockNoteRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewNoteService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, handlers.CreateNoteRequest{Title: "license", Text: "XXXX-YYYY"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+	mockRepo.AssertCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestNoteService_Create_TooLarge(t *testing.T) {
+	mockRepo := new(MockNoteRepo)
+	service := NewNoteService(mockRepo, new(MockKeyService), new(MockAuthService))
+
+	_, err := service.Create(context.Background(), handlers.CreateNoteRequest{Text: strings.Repeat("a", MaxNoteSize+1)})
+
+	assert.EqualError(t, err, customerr.NOTE_TOO_LARGE)
+	mockRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestNoteService_Update(t *testing.T) {
+	mockRepo := new(MockNoteRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewNoteService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	jsonContent, _ := json.Marshal(&noteContent{Title: "old_title", Text: "old_text"})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.Note}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	text := "new_text"
+	response, err := service.Update(ctx, handlers.UpdateNoteRequest{UUID: uuidStr, Text: &text})
+	assert.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+
+	updated := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(1).(entity.Data)
+	// updated content is sealed in envelope with new data key
+	assert.NotEmpty(t, updated.DataKey)
+	decrypted, err := lib.OpenEnvelope(key, updated.DataKey, updated.Content, updated.AssociatedData())
+	assert.NoError(t, err)
+	content := noteContent{}
+	assert.NoError(t, json.Unmarshal(decrypted, &content))
+	assert.Equal(t, noteContent{Title: "old_title", Text: "new_text"}, content)
+}
+
+func TestNoteService_Delete(t *testing.T) {
+	mockRepo := new(MockNoteRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewNoteService(mockRepo, new(MockKeyService), mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	uuidStr := uuid.New().String()
+
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockRepo.On("GetByUUID", ctx, user, uuidStr).Return(&entity.Data{UUID: uuidStr, ContentType: entity.Note}, nil)
+	mockRepo.On("Delete", ctx, user, uuidStr).Return(nil)
+
+	response, err := service.Delete(ctx, handlers.DeleteNoteRequest{UUID: uuidStr})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+}
+
+func TestNoteService_WrongType(t *testing.T) {
+	mockRepo := new(MockNoteRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewNoteService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	// card must not be rewritten as note
+	data := entity.Data{UUID: uuidStr, ContentType: entity.Card, CreatedBy: user}
+	jsonContent, _ := json.Marshal(map[string]string{"number": "4111111111111111", "cvv": "123"})
+	data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	title := "title"
+	_, err := service.Update(ctx, handlers.UpdateNoteRequest{UUID: uuidStr, Title: &title})
+	assert.EqualError(t, err, customerr.NOTE_NOT_FOUND)
+
+	_, err = service.Delete(ctx, handlers.DeleteNoteRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.NOTE_NOT_FOUND)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNoteService_GetAll(t *testing.T) {
+	mockRepo := new(MockNoteRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewNoteService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	jsonContent, _ := json.Marshal(&noteContent{Title: "license", Text: "XXXX-YYYY"})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := []*entity.Data{
+		{UUID: uuid.New().String(), Content: encryptedContent, ContentType: entity.Note},
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Note).Return(data, nil)
+
+	response, err := service.GetAll(ctx, handlers.GetAllNotesRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, "XXXX-YYYY", response.Items[0].Text)
+	assert.Equal(t, data[0].UUID, response.Items[0].UUID)
+}
@@ -0,0 +1,224 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type otpContent struct {
+	Name      string `json:"name"`
+	Issuer    string `json:"issuer"`
+	Account   string `json:"account"`
+	Secret    string `json:"secret"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewOTPService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Create save otp secret from otpauth:// uri or raw base32 secret
+func (s *Service) Create(ctx context.Context, r handlers.CreateOTPRequest) (*handlers.CreateOTPResponse, error) {
+	content := otpContent{
+		Name:      r.Name,
+		Issuer:    r.Issuer,
+		Account:   r.Account,
+		Secret:    r.Secret,
+		Algorithm: r.Algorithm,
+		Digits:    r.Digits,
+		Period:    r.Period,
+	}
+	if r.URI != "" {
+		if err := parseURI(r.URI, &content); err != nil {
+			return nil, err
+		}
+	}
+	if err := normalize(&content); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.OTP,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateOTPResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Delete delete otp secret
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteOTPRequest) (*handlers.DeleteOTPResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.load(ctx, user, r.UUID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteOTPResponse{UUID: r.UUID}, nil
+}
+
+// load otp secret without decrypting it, row of another content type is rejected
+func (s *Service) load(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fromDB.ContentType != entity.OTP {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_OTP_SECRET, http.StatusBadRequest)
+	}
+	return fromDB, nil
+}
+
+// GetAll get all otp entries without secrets
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllOTPRequest) (*handlers.GetAllOTPResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.OTP)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllOTPResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		content := otpContent{}
+		err = json.Unmarshal(jsonDecrypted, &content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, handlers.GetAllOTPResponseItem{
+			UUID:      v.UUID,
+			Name:      content.Name,
+			Issuer:    content.Issuer,
+			Account:   content.Account,
+			Algorithm: content.Algorithm,
+			Digits:    content.Digits,
+			Period:    content.Period,
+		})
+	}
+
+	return &handlers.GetAllOTPResponse{Items: items}, nil
+}
+
+// GetCode generate current code and its remaining validity
+func (s *Service) GetCode(ctx context.Context, r handlers.GetOTPCodeRequest) (*handlers.GetOTPCodeResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	content := otpContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := decodeSecret(content.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	code, err := lib.TOTP(secret, content.Algorithm, content.Digits, content.Period, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.GetOTPCodeResponse{
+		Code:      code,
+		ExpiresIn: content.Period - int(now.Unix()%int64(content.Period)),
+		Period:    content.Period,
+	}, nil
+}
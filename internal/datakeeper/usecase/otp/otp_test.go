@@ -0,0 +1,172 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// base32 of RFC 6238 SHA1 seed "12345678901234567890"
+const testSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestParseURI(t *testing.T) {
+	content := otpContent{}
+	err := parseURI("otpauth://totp/ACME%20Co:john@example.com?secret="+testSecret+"&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60", &content)
+	assert.NoError(t, err)
+	assert.NoError(t, normalize(&content))
+	assert.Equal(t, otpContent{
+		Issuer:    "ACME Co",
+		Account:   "john@example.com",
+		Secret:    testSecret,
+		Algorithm: "SHA256",
+		Digits:    8,
+		Period:    60,
+	}, content)
+
+	err = parseURI("otpauth://hotp/ACME?secret="+testSecret, &content)
+	assert.EqualError(t, err, customerr.INVALID_OTP_URI)
+}
+
+func TestNormalize(t *testing.T) {
+	content := otpContent{Secret: "gezd gnbv gy3t qojq gezd gnbv gy3t qojq"}
+	assert.NoError(t, normalize(&content))
+	assert.Equal(t, testSecret, content.Secret)
+	assert.Equal(t, "SHA1", content.Algorithm)
+	assert.Equal(t, 6, content.Digits)
+	assert.Equal(t, 30, content.Period)
+
+	assert.EqualError(t, normalize(&otpContent{Secret: "not base32!"}), customerr.INVALID_OTP_SECRET)
+	assert.EqualError(t, normalize(&otpContent{Secret: testSecret, Digits: 9}), customerr.INVALID_OTP_PARAMS)
+	assert.EqualError(t, normalize(&otpContent{Secret: testSecret, Algorithm: "MD5"}), customerr.INVALID_OTP_PARAMS)
+}
+
+func TestOTPService_Create(t *testing.T) {
+	mockRepo := new(MockOTPRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewOTPService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, handlers.CreateOTPRequest{URI: "otpauth://totp/ACME:john?secret=" + testSecret})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+	mockRepo.AssertCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestOTPService_GetCode(t *testing.T) {
+	mockRepo := new(MockOTPRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewOTPService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	jsonContent, _ := json.Marshal(&otpContent{Secret: testSecret, Algorithm: "SHA1", Digits: 6, Period: 30})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.OTP}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	response, err := service.GetCode(ctx, handlers.GetOTPCodeRequest{UUID: uuidStr})
+	assert.NoError(t, err)
+
+	// code may roll over between generation and check
+	now := time.Now()
+	current, _ := lib.TOTP([]byte("12345678901234567890"), "SHA1", 6, 30, now)
+	previous, _ := lib.TOTP([]byte("12345678901234567890"), "SHA1", 6, 30, now.Add(-30*time.Second))
+	assert.Contains(t, []string{current, previous}, response.Code)
+	assert.True(t, response.ExpiresIn > 0 && response.ExpiresIn <= 30)
+	assert.Equal(t, 30, response.Period)
+}
+
+func TestOTPService_GetCodeWrongType(t *testing.T) {
+	mockRepo := new(MockOTPRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewOTPService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	encryptedContent, _ := lib.Encrypt(key, []byte(`{"title":"note"}`))
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.Note}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	_, err := service.GetCode(ctx, handlers.GetOTPCodeRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.INVALID_OTP_SECRET)
+
+	_, err = service.Delete(ctx, handlers.DeleteOTPRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.INVALID_OTP_SECRET)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOTPService_Delete(t *testing.T) {
+	mockRepo := new(MockOTPRepo)
+	mockAuthService := new(MockAuthService)
+	service := NewOTPService(mockRepo, new(MockKeyService), mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	uuidStr := uuid.New().String()
+
+	mockAuthService.On("GetUserFromContext", ctx).Return(user, nil)
+	mockRepo.On("GetByUUID", ctx, user, uuidStr).Return(&entity.Data{UUID: uuidStr, ContentType: entity.OTP}, nil)
+	mockRepo.On("Delete", ctx, user, uuidStr).Return(nil)
+
+	response, err := service.Delete(ctx, handlers.DeleteOTPRequest{UUID: uuidStr})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+	mockRepo.AssertCalled(t, "Delete", ctx, user, uuidStr)
+}
+
+func TestOTPService_GetAll(t *testing.T) {
+	mockRepo := new(MockOTPRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewOTPService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	jsonContent, _ := json.Marshal(&otpContent{Issuer: "ACME", Account: "john", Secret: testSecret, Algorithm: "SHA1", Digits: 6, Period: 30})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := []*entity.Data{
+		{UUID: uuid.New().String(), Content: encryptedContent, ContentType: entity.OTP},
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.OTP).Return(data, nil)
+
+	response, err := service.GetAll(ctx, handlers.GetAllOTPRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, "ACME", response.Items[0].Issuer)
+}
@@ -0,0 +1,91 @@
+package otp
+
+import (
+	"encoding/base32"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+)
+
+const (
+	defaultAlgorithm = "SHA1"
+	defaultDigits    = 6
+	defaultPeriod    = 30
+)
+
+// parseURI parse otpauth://totp/Issuer:account?secret=...&issuer=...&algorithm=...&digits=...&period=...
+func parseURI(rawURI string, content *otpContent) error {
+	u, err := url.Parse(rawURI)
+	if err != nil || u.Scheme != "otpauth" || u.Host != "totp" {
+		return customerr.ErrorWithCode(customerr.INVALID_OTP_URI, http.StatusBadRequest)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		content.Issuer = issuer
+		content.Account = strings.TrimSpace(account)
+	} else {
+		content.Account = label
+	}
+
+	query := u.Query()
+	content.Secret = query.Get("secret")
+	if issuer := query.Get("issuer"); issuer != "" {
+		content.Issuer = issuer
+	}
+	content.Algorithm = query.Get("algorithm")
+	if digits := query.Get("digits"); digits != "" {
+		content.Digits, err = strconv.Atoi(digits)
+		if err != nil {
+			return customerr.ErrorWithCode(customerr.INVALID_OTP_URI, http.StatusBadRequest)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		content.Period, err = strconv.Atoi(period)
+		if err != nil {
+			return customerr.ErrorWithCode(customerr.INVALID_OTP_URI, http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+// normalize set default params and validate secret
+func normalize(content *otpContent) error {
+	content.Secret = strings.ToUpper(strings.ReplaceAll(content.Secret, " ", ""))
+	content.Secret = strings.TrimRight(content.Secret, "=")
+	if content.Secret == "" {
+		return customerr.ErrorWithCode(customerr.INVALID_OTP_SECRET, http.StatusBadRequest)
+	}
+	if _, err := decodeSecret(content.Secret); err != nil {
+		return customerr.ErrorWithCode(customerr.INVALID_OTP_SECRET, http.StatusBadRequest)
+	}
+
+	content.Algorithm = strings.ToUpper(content.Algorithm)
+	if content.Algorithm == "" {
+		content.Algorithm = defaultAlgorithm
+	}
+	if content.Digits == 0 {
+		content.Digits = defaultDigits
+	}
+	if content.Period == 0 {
+		content.Period = defaultPeriod
+	}
+
+	switch content.Algorithm {
+	case "SHA1", "SHA256", "SHA512":
+	default:
+		return customerr.ErrorWithCode(customerr.INVALID_OTP_PARAMS, http.StatusBadRequest)
+	}
+	if content.Digits < 6 || content.Digits > 8 || content.Period <= 0 {
+		return customerr.ErrorWithCode(customerr.INVALID_OTP_PARAMS, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// decodeSecret decode base32 secret without padding
+func decodeSecret(secret string) ([]byte, error) {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+}
@@ -0,0 +1,112 @@
+package recovery
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo is a mock implementation of Repo
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) Save(ctx context.Context, kit entity.RecoveryKit) error {
+	args := m.Called(ctx, kit)
+	return args.Error(0)
+}
+
+func (m *MockRepo) GetByLogin(ctx context.Context, login string) (*entity.RecoveryKit, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(*entity.RecoveryKit), args.Error(1)
+}
+
+func (m *MockRepo) Delete(ctx context.Context, login string) error {
+	args := m.Called(ctx, login)
+	return args.Error(0)
+}
+
+// MockKeyParamsRepo is a mock implementation of KeyParamsRepo
+type MockKeyParamsRepo struct {
+	mock.Mock
+}
+
+func (m *MockKeyParamsRepo) Save(ctx context.Context, params entity.KeyParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+// MockTransactor is a mock implementation of Transactor, fn is called if WithTx returns no error
+type MockTransactor struct {
+	mock.Mock
+}
+
+func (m *MockTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// MockRotator is a mock implementation of Rotator
+type MockRotator struct {
+	mock.Mock
+}
+
+func (m *MockRotator) RotateData(ctx context.Context, user string, oldKey string, newKey string) (*handlers.RotateKeyResponse, error) {
+	args := m.Called(ctx, user, oldKey, newKey)
+	return args.Get(0).(*handlers.RotateKeyResponse), args.Error(1)
+}
+
+// MockKeyService is a mock implementation of KeyService
+type MockKeyService struct {
+	mock.Mock
+}
+
+func (m *MockKeyService) GetKeyForUser(user string) (string, error) {
+	args := m.Called(user)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockKeyService) SetKeyForUser(user string, key string) error {
+	args := m.Called(user, key)
+	return args.Error(0)
+}
+
+func (m *MockKeyService) NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error) {
+	args := m.Called(user, masterPassword)
+	return args.String(0), args.Get(1).(*entity.KeyParams), args.Error(2)
+}
+
+func (m *MockKeyService) CheckValue(key string) []byte {
+	args := m.Called(key)
+	return args.Get(0).([]byte)
+}
+
+// MockAuthService is a mock implementation of AuthService
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) GetUserFromContext(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAuthService) Authenticate(ctx context.Context, login string, password string) error {
+	args := m.Called(ctx, login, password)
+	return args.Error(0)
+}
+
+// MockSessionService is a mock implementation of SessionService
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) Issue(ctx context.Context, login string) (*handlers.SessionTokens, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(*handlers.SessionTokens), args.Error(1)
+}
@@ -0,0 +1,217 @@
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// maxShares max number of shares in kit
+	maxShares = 16
+	// qrSize size of share QR code in pixels
+	qrSize = 256
+)
+
+type Repo interface {
+	Save(ctx context.Context, kit entity.RecoveryKit) error
+	GetByLogin(ctx context.Context, login string) (*entity.RecoveryKit, error)
+	Delete(ctx context.Context, login string) error
+}
+
+type KeyParamsRepo interface {
+	Save(ctx context.Context, params entity.KeyParams) error
+}
+
+// Transactor run repository calls in one transaction
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Rotator re-encrypt data of user with new key
+type Rotator interface {
+	RotateData(ctx context.Context, user string, oldKey string, newKey string) (*handlers.RotateKeyResponse, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+	SetKeyForUser(user string, key string) error
+	NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error)
+	CheckValue(key string) []byte
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+	Authenticate(ctx context.Context, login string, password string) error
+}
+
+type SessionService interface {
+	Issue(ctx context.Context, login string) (*handlers.SessionTokens, error)
+}
+
+type Service struct {
+	repo           Repo
+	keyParamsRepo  KeyParamsRepo
+	tx             Transactor
+	rotator        Rotator
+	keyService     KeyService
+	authService    AuthService
+	sessionService SessionService
+}
+
+func NewRecoveryService(repo Repo, keyParamsRepo KeyParamsRepo, tx Transactor, rotator Rotator, keyService KeyService, authService AuthService, sessionService SessionService) *Service {
+	return &Service{
+		repo:           repo,
+		keyParamsRepo:  keyParamsRepo,
+		tx:             tx,
+		rotator:        rotator,
+		keyService:     keyService,
+		authService:    authService,
+		sessionService: sessionService,
+	}
+}
+
+// CreateKit split user key into shares, any threshold shares rebuild key.
+// Shares are returned once and never stored, new kit replaces previous one
+func (s *Service) CreateKit(ctx context.Context, r handlers.CreateRecoveryKitRequest) (*handlers.CreateRecoveryKitResponse, error) {
+	if r.Threshold < 2 || r.Threshold > r.Shares || r.Shares > maxShares {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_RECOVERY_KIT, http.StatusBadRequest)
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	kit := entity.RecoveryKit{
+		Login:      user,
+		KitID:      make([]byte, kitIDSize),
+		Threshold:  r.Threshold,
+		Shares:     r.Shares,
+		CheckValue: s.keyService.CheckValue(key),
+		CreatedAt:  time.Now(),
+	}
+	if _, err = io.ReadFull(rand.Reader, kit.KitID); err != nil {
+		return nil, err
+	}
+
+	shares, err := lib.SplitSecret([]byte(key), r.Shares, r.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.RecoveryShare, 0, len(shares))
+	for i, data := range shares {
+		text := encodeShare(kit.KitID, kit.Threshold, data)
+		png, err := qrcode.Encode(text, qrcode.Medium, qrSize)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, handlers.RecoveryShare{Index: i + 1, Text: text, QR: png})
+	}
+
+	if err = s.repo.Save(ctx, kit); err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateRecoveryKitResponse{Threshold: kit.Threshold, Items: items}, nil
+}
+
+// Recover rebuild user key from shares and re-encrypt data with key derived from new master password.
+// Shares of used kit are useless after recovery, user creates new kit
+func (s *Service) Recover(ctx context.Context, r handlers.RecoverKeyRequest) (*handlers.RecoverKeyResponse, error) {
+	if err := s.authService.Authenticate(ctx, r.Login, r.Password); err != nil {
+		return nil, err
+	}
+
+	kit, err := s.repo.GetByLogin(ctx, r.Login)
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, customerr.ErrorWithCode(customerr.RECOVERY_KIT_NOT_FOUND, http.StatusNotFound)
+	}
+
+	oldKey, err := s.combine(kit, r.Shares)
+	if err != nil {
+		return nil, err
+	}
+
+	newKey, params, err := s.keyService.NewKeyParams(r.Login, r.NewMasterPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.rotator.RotateData(ctx, r.Login, oldKey, newKey); err != nil {
+			return err
+		}
+		if err := s.keyParamsRepo.Save(ctx, *params); err != nil {
+			return err
+		}
+		return s.repo.Delete(ctx, r.Login)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.keyService.SetKeyForUser(r.Login, newKey); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.Issue(ctx, r.Login)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.RecoverKeyResponse{SessionTokens: *tokens}, nil
+}
+
+// combine rebuild key from share texts, key is verified by check value of kit
+func (s *Service) combine(kit *entity.RecoveryKit, texts []string) (string, error) {
+	if len(texts) < kit.Threshold {
+		return "", invalidShare("at least %d shares are required", kit.Threshold)
+	}
+
+	shares := make([][]byte, 0, len(texts))
+	for i, text := range texts {
+		share, err := decodeShare(text)
+		if err != nil {
+			return "", invalidShare("share %d: %s", i+1, err)
+		}
+		if !bytes.Equal(share.kitID, kit.KitID) {
+			return "", invalidShare("share %d belongs to another recovery kit", i+1)
+		}
+		shares = append(shares, share.data)
+	}
+
+	key, err := lib.CombineShares(shares)
+	if err != nil {
+		return "", invalidShare("%s", err)
+	}
+	if !hmac.Equal(s.keyService.CheckValue(string(key)), kit.CheckValue) {
+		return "", customerr.ErrorWithCode(customerr.RECOVERY_FAILED, http.StatusBadRequest)
+	}
+
+	return string(key), nil
+}
+
+func invalidShare(format string, args ...interface{}) error {
+	return customerr.ErrorWithCode(customerr.INVALID_RECOVERY_SHARE+": "+fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
@@ -0,0 +1,225 @@
+package recovery
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// checkValue test key check value
+func checkValue(key string) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("check"))
+	return mac.Sum(nil)
+}
+
+// createKit create kit of key with mocks, saved kit is returned with shares
+func createKit(t *testing.T, key string, shares int, threshold int) (*entity.RecoveryKit, []string) {
+	mockRepo := new(MockRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewRecoveryService(mockRepo, new(MockKeyParamsRepo), new(MockTransactor), new(MockRotator), mockKeyService, mockAuthService, new(MockSessionService))
+
+	var saved entity.RecoveryKit
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return("test_user", nil)
+	mockKeyService.On("GetKeyForUser", "test_user").Return(key, nil)
+	mockKeyService.On("CheckValue", key).Return(checkValue(key))
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(kit entity.RecoveryKit) bool {
+		saved = kit
+		return true
+	})).Return(nil)
+
+	response, err := service.CreateKit(context.Background(), handlers.CreateRecoveryKitRequest{Shares: shares, Threshold: threshold})
+	require.NoError(t, err)
+
+	texts := make([]string, 0, len(response.Items))
+	for _, item := range response.Items {
+		texts = append(texts, item.Text)
+	}
+	return &saved, texts
+}
+
+func TestRecoveryService_CreateKit(t *testing.T) {
+	key := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	mockRepo := new(MockRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewRecoveryService(mockRepo, new(MockKeyParamsRepo), new(MockTransactor), new(MockRotator), mockKeyService, mockAuthService, new(MockSessionService))
+
+	var saved entity.RecoveryKit
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return("test_user", nil)
+	mockKeyService.On("GetKeyForUser", "test_user").Return(key, nil)
+	mockKeyService.On("CheckValue", key).Return(checkValue(key))
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(kit entity.RecoveryKit) bool {
+		saved = kit
+		return true
+	})).Return(nil)
+
+	response, err := service.CreateKit(context.Background(), handlers.CreateRecoveryKitRequest{Shares: 5, Threshold: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, response.Threshold)
+	require.Len(t, response.Items, 5)
+	for i, item := range response.Items {
+		assert.Equal(t, i+1, item.Index)
+		assert.True(t, strings.HasPrefix(item.Text, "DKR-"))
+		assert.NotContains(t, item.Text, key[:8])
+		// PNG signature
+		assert.Equal(t, "\x89PNG", string(item.QR[:4]))
+
+		share, err := decodeShare(item.Text)
+		require.NoError(t, err)
+		assert.Equal(t, saved.KitID, share.kitID)
+		assert.Equal(t, 3, share.threshold)
+	}
+
+	// shares are not stored, only kit and check value
+	assert.Equal(t, "test_user", saved.Login)
+	assert.Equal(t, 3, saved.Threshold)
+	assert.Equal(t, 5, saved.Shares)
+	assert.Len(t, saved.KitID, kitIDSize)
+	assert.Equal(t, checkValue(key), saved.CheckValue)
+}
+
+func TestRecoveryService_CreateKitInvalid(t *testing.T) {
+	service := NewRecoveryService(new(MockRepo), new(MockKeyParamsRepo), new(MockTransactor), new(MockRotator), new(MockKeyService), new(MockAuthService), new(MockSessionService))
+
+	for _, r := range []handlers.CreateRecoveryKitRequest{
+		{Shares: 3, Threshold: 1},
+		{Shares: 2, Threshold: 3},
+		{Shares: 17, Threshold: 2},
+	} {
+		_, err := service.CreateKit(context.Background(), r)
+		assert.EqualError(t, err, customerr.INVALID_RECOVERY_KIT, "request %+v", r)
+	}
+}
+
+func TestRecoveryService_Recover(t *testing.T) {
+	oldKey := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	newKey := "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	kit, shares := createKit(t, oldKey, 5, 3)
+
+	mockRepo := new(MockRepo)
+	mockKeyParamsRepo := new(MockKeyParamsRepo)
+	mockTransactor := new(MockTransactor)
+	mockRotator := new(MockRotator)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	mockSessionService := new(MockSessionService)
+	service := NewRecoveryService(mockRepo, mockKeyParamsRepo, mockTransactor, mockRotator, mockKeyService, mockAuthService, mockSessionService)
+
+	params := &entity.KeyParams{Login: "test_user", CheckValue: checkValue(newKey)}
+	mockAuthService.On("Authenticate", mock.Anything, "test_user", "password").Return(nil)
+	mockRepo.On("GetByLogin", mock.Anything, "test_user").Return(kit, nil)
+	mockKeyService.On("CheckValue", oldKey).Return(checkValue(oldKey))
+	mockKeyService.On("NewKeyParams", "test_user", "new master password").Return(newKey, params, nil)
+	mockTransactor.On("WithTx", mock.Anything).Return(nil)
+	mockRotator.On("RotateData", mock.Anything, "test_user", oldKey, newKey).Return(&handlers.RotateKeyResponse{Data: 2}, nil)
+	mockKeyParamsRepo.On("Save", mock.Anything, *params).Return(nil)
+	mockRepo.On("Delete", mock.Anything, "test_user").Return(nil)
+	mockKeyService.On("SetKeyForUser", "test_user", newKey).Return(nil)
+	mockSessionService.On("Issue", mock.Anything, "test_user").Return(&handlers.SessionTokens{Token: "token"}, nil)
+
+	// any threshold shares in any order, typed in lower case with spaces
+	response, err := service.Recover(context.Background(), handlers.RecoverKeyRequest{
+		Login:             "test_user",
+		Password:          "password",
+		Shares:            []string{shares[4], strings.ToLower(shares[0]), strings.ReplaceAll(shares[2], "-", " ")},
+		NewMasterPassword: "new master password",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "token", response.Token)
+
+	mockTransactor.AssertNumberOfCalls(t, "WithTx", 1)
+	mockRotator.AssertExpectations(t)
+	mockKeyParamsRepo.AssertExpectations(t)
+	mockRepo.AssertCalled(t, "Delete", mock.Anything, "test_user")
+	mockKeyService.AssertCalled(t, "SetKeyForUser", "test_user", newKey)
+}
+
+func TestRecoveryService_RecoverInvalidShares(t *testing.T) {
+	key := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	kit, shares := createKit(t, key, 3, 2)
+	_, otherShares := createKit(t, "another key of the same user, 1234567890abcdef", 3, 2)
+	// shares of another key relabeled with kit id
+	forged := make([]string, 0, 2)
+	for _, text := range otherShares[:2] {
+		share, err := decodeShare(text)
+		require.NoError(t, err)
+		forged = append(forged, encodeShare(kit.KitID, share.threshold, share.data))
+	}
+	mistyped := []byte(shares[1])
+	mistyped[10] = map[bool]byte{true: 'B', false: 'A'}[mistyped[10] == 'A']
+
+	mockRepo := new(MockRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewRecoveryService(mockRepo, new(MockKeyParamsRepo), new(MockTransactor), new(MockRotator), mockKeyService, mockAuthService, new(MockSessionService))
+
+	mockAuthService.On("Authenticate", mock.Anything, "test_user", "password").Return(nil)
+	mockRepo.On("GetByLogin", mock.Anything, "test_user").Return(kit, nil)
+	mockRepo.On("GetByLogin", mock.Anything, "new_user").Return((*entity.RecoveryKit)(nil), nil)
+	mockAuthService.On("Authenticate", mock.Anything, "new_user", "password").Return(nil)
+	// shares rebuild another key
+	mockKeyService.On("CheckValue", mock.Anything).Return(checkValue("another key"))
+
+	tests := []struct {
+		name    string
+		login   string
+		shares  []string
+		message string
+		code    int
+	}{
+		{"no kit", "new_user", shares[:2], customerr.RECOVERY_KIT_NOT_FOUND, http.StatusNotFound},
+		{"too few shares", "test_user", shares[:1], customerr.INVALID_RECOVERY_SHARE + ": at least 2 shares are required", http.StatusBadRequest},
+		{"mistyped share", "test_user", []string{shares[0], string(mistyped)}, customerr.INVALID_RECOVERY_SHARE + ": share 2: share is mistyped", http.StatusBadRequest},
+		{"not a share", "test_user", []string{shares[0], "hello"}, customerr.INVALID_RECOVERY_SHARE + ": share 2: not a recovery share", http.StatusBadRequest},
+		{"another kit", "test_user", []string{shares[0], otherShares[1]}, customerr.INVALID_RECOVERY_SHARE + ": share 2 belongs to another recovery kit", http.StatusBadRequest},
+		{"repeated share", "test_user", []string{shares[0], shares[0]}, customerr.INVALID_RECOVERY_SHARE + ": shares have invalid or repeated index", http.StatusBadRequest},
+		{"wrong key", "test_user", forged, customerr.RECOVERY_FAILED, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Recover(context.Background(), handlers.RecoverKeyRequest{
+				Login:             tt.login,
+				Password:          "password",
+				Shares:            tt.shares,
+				NewMasterPassword: "new master password",
+			})
+			var customErr *customerr.CustomError
+			require.ErrorAs(t, err, &customErr)
+			assert.Equal(t, tt.message, customErr.Message)
+			assert.Equal(t, tt.code, customErr.Code)
+		})
+	}
+	mockKeyService.AssertNotCalled(t, "SetKeyForUser", mock.Anything, mock.Anything)
+}
+
+func TestShareText(t *testing.T) {
+	kitID := []byte("12345678")
+	data := []byte{3, 0xde, 0xad, 0xbe, 0xef}
+
+	text := encodeShare(kitID, 2, data)
+	for _, group := range strings.Split(text, "-")[1:] {
+		assert.LessOrEqual(t, len(group), shareGroupSize)
+	}
+
+	share, err := decodeShare(text)
+	require.NoError(t, err)
+	assert.Equal(t, kitID, share.kitID)
+	assert.Equal(t, 2, share.threshold)
+	assert.Equal(t, data, share.data)
+
+	_, err = decodeShare("DKR-AAAA")
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package recovery
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Share text format
+//
+//	"DKR" - base32 of version (1) | kit id (8) | threshold (1) | x (1) | y | checksum (4), in groups of 5
+//
+// Text uses only upper case letters, digits and dashes, so it is typed back easily and fits QR alphanumeric mode.
+// Checksum is first bytes of SHA-256 of payload, it catches typos before shares are combined.
+const (
+	sharePrefix       = "DKR"
+	shareVersion      = 1
+	kitIDSize         = 8
+	shareChecksumSize = 4
+	shareGroupSize    = 5
+)
+
+var shareEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// share decoded share text
+type share struct {
+	kitID     []byte
+	threshold int
+	// data x | y of Shamir share
+	data []byte
+}
+
+// encodeShare printable text of share
+func encodeShare(kitID []byte, threshold int, data []byte) string {
+	payload := []byte{shareVersion}
+	payload = append(payload, kitID...)
+	payload = append(payload, byte(threshold))
+	payload = append(payload, data...)
+	sum := sha256.Sum256(payload)
+	payload = append(payload, sum[:shareChecksumSize]...)
+
+	encoded := shareEncoding.EncodeToString(payload)
+	groups := []string{sharePrefix}
+	for len(encoded) > 0 {
+		n := min(shareGroupSize, len(encoded))
+		groups = append(groups, encoded[:n])
+		encoded = encoded[n:]
+	}
+	return strings.Join(groups, "-")
+}
+
+// decodeShare parse share text, case, dashes and spaces are ignored
+func decodeShare(text string) (*share, error) {
+	text = strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, text))
+	if !strings.HasPrefix(text, sharePrefix) {
+		return nil, errors.New("not a recovery share")
+	}
+
+	payload, err := shareEncoding.DecodeString(text[len(sharePrefix):])
+	if err != nil {
+		return nil, errors.New("share is mistyped")
+	}
+	if len(payload) < 1+kitIDSize+1+2+shareChecksumSize {
+		return nil, errors.New("share is too short")
+	}
+	body := payload[:len(payload)-shareChecksumSize]
+	sum := sha256.Sum256(body)
+	if subtle.ConstantTimeCompare(sum[:shareChecksumSize], payload[len(body):]) != 1 {
+		return nil, errors.New("share is mistyped")
+	}
+	if body[0] != shareVersion {
+		return nil, errors.New("unsupported share version")
+	}
+
+	return &share{
+		kitID:     body[1 : 1+kitIDSize],
+		threshold: int(body[1+kitIDSize]),
+		data:      body[2+kitIDSize:],
+	}, nil
+}
@@ -0,0 +1,121 @@
+package reencrypt
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReencryptRepo is a mock implementation of Repo
+type MockReencryptRepo struct {
+	mock.Mock
+}
+
+func (m *MockReencryptRepo) Update(ctx context.Context, data entity.Data) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockReencryptRepo) GetAllByUser(ctx context.Context, user string) ([]*entity.Data, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).([]*entity.Data), args.Error(1)
+}
+
+// MockFileRepo is a mock implementation of RepoFile
+type MockFileRepo struct {
+	mock.Mock
+}
+
+func (m *MockFileRepo) Update(ctx context.Context, data entity.FileRepo) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockFileRepo) GetByUUID(ctx context.Context, user string, uuid string) (*entity.FileRepo, error) {
+	args := m.Called(ctx, user, uuid)
+	return args.Get(0).(*entity.FileRepo), args.Error(1)
+}
+
+func (m *MockFileRepo) GetUUIDsByUser(ctx context.Context, user string) ([]string, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// MockKeyParamsRepo is a mock implementation of KeyParamsRepo
+type MockKeyParamsRepo struct {
+	mock.Mock
+}
+
+func (m *MockKeyParamsRepo) GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(*entity.KeyParams), args.Error(1)
+}
+
+func (m *MockKeyParamsRepo) Save(ctx context.Context, params entity.KeyParams) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+// MockRecoveryKitRepo is a mock implementation of RecoveryKitRepo
+type MockRecoveryKitRepo struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryKitRepo) GetByLogin(ctx context.Context, login string) (*entity.RecoveryKit, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(*entity.RecoveryKit), args.Error(1)
+}
+
+func (m *MockRecoveryKitRepo) Delete(ctx context.Context, login string) error {
+	args := m.Called(ctx, login)
+	return args.Error(0)
+}
+
+// MockKeyService is a mock implementation of KeyService
+type MockKeyService struct {
+	mock.Mock
+}
+
+func (m *MockKeyService) GetKeyForUser(user string) (string, error) {
+	args := m.Called(user)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockKeyService) SetKeyForUser(user string, key string) error {
+	args := m.Called(user, key)
+	return args.Error(0)
+}
+
+func (m *MockKeyService) NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error) {
+	args := m.Called(user, masterPassword)
+	return args.String(0), args.Get(1).(*entity.KeyParams), args.Error(2)
+}
+
+func (m *MockKeyService) UnlockKey(masterPassword string, params *entity.KeyParams) (string, error) {
+	args := m.Called(masterPassword, params)
+	return args.String(0), args.Error(1)
+}
+
+// MockTransactor is a mock implementation of Transactor, fn is called if WithTx returns no error
+type MockTransactor struct {
+	mock.Mock
+}
+
+func (m *MockTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// MockAuthService is a mock implementation of AuthService
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) GetUserFromContext(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,332 @@
+package reencrypt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/usecase/sshkey"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+)
+
+type Repo interface {
+	Update(ctx context.Context, data entity.Data) error
+	GetAllByUser(ctx context.Context, user string) ([]*entity.Data, error)
+}
+
+type RepoFile interface {
+	Update(ctx context.Context, data entity.FileRepo) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.FileRepo, error)
+	GetUUIDsByUser(ctx context.Context, user string) ([]string, error)
+}
+
+type KeyParamsRepo interface {
+	GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error)
+	Save(ctx context.Context, params entity.KeyParams) error
+}
+
+// RecoveryKitRepo recovery kits of users, kit rebuilds key it was created for
+type RecoveryKitRepo interface {
+	GetByLogin(ctx context.Context, login string) (*entity.RecoveryKit, error)
+	Delete(ctx context.Context, login string) error
+}
+
+// Transactor run repository calls in one transaction
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+	SetKeyForUser(user string, key string) error
+	NewKeyParams(user string, masterPassword string) (string, *entity.KeyParams, error)
+	UnlockKey(masterPassword string, params *entity.KeyParams) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+// minKeyLength min length of new user key
+const minKeyLength = 16
+
+type Service struct {
+	dataRepo        Repo
+	fileRepo        RepoFile
+	keyParamsRepo   KeyParamsRepo
+	recoveryKitRepo RecoveryKitRepo
+	tx              Transactor
+	keyService      KeyService
+	authService     AuthService
+}
+
+func NewReencryptService(dataRepo Repo, fileRepo RepoFile, keyParamsRepo KeyParamsRepo, recoveryKitRepo RecoveryKitRepo, tx Transactor, keyService KeyService, authService AuthService) *Service {
+	return &Service{
+		dataRepo:        dataRepo,
+		fileRepo:        fileRepo,
+		keyParamsRepo:   keyParamsRepo,
+		recoveryKitRepo: recoveryKitRepo,
+		tx:              tx,
+		keyService:      keyService,
+		authService:     authService,
+	}
+}
+
+// Upgrade seal rows of user in envelope bound to record, rows already bound are skipped.
+// Rows written before binding are readable only until they are upgraded, so swapped rows are detected after it.
+// Private key of ssh key row written by earlier versions is moved to its own envelope, so listing doesn't open it
+func (s *Service) Upgrade(ctx context.Context, r handlers.UpgradeEncryptionRequest) (*handlers.UpgradeEncryptionResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &handlers.UpgradeEncryptionResponse{}
+
+	data, err := s.dataRepo.GetAllByUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range data {
+		// blobs are encrypted by client with key unknown to server
+		if v.ContentType == entity.Blob || (lib.IsBound(v.DataKey) && v.ContentType != entity.SSHKey) {
+			continue
+		}
+		upgraded, err := rotateRow(key, key, &v.DataKey, &v.Content, v.AssociatedData(), false, v.ContentType == entity.SSHKey)
+		if err != nil {
+			return nil, err
+		}
+		if !upgraded {
+			continue
+		}
+		if err = s.dataRepo.Update(ctx, *v); err != nil {
+			return nil, err
+		}
+		res.Data++
+	}
+
+	uuids, err := s.fileRepo.GetUUIDsByUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, uuid := range uuids {
+		file, err := s.fileRepo.GetByUUID(ctx, user, uuid)
+		if err != nil {
+			return nil, err
+		}
+		if lib.IsBound(file.DataKey) {
+			continue
+		}
+		if _, err = rotateRow(key, key, &file.DataKey, &file.Content, file.AssociatedData(), file.Chunked, false); err != nil {
+			return nil, err
+		}
+		if err = s.fileRepo.Update(ctx, *file); err != nil {
+			return nil, err
+		}
+		res.Files++
+	}
+
+	return res, nil
+}
+
+// Rotate re-encrypt all rows of user with new key in one transaction and set new key for user.
+// Bound data keys are re-wrapped, other rows are sealed in envelope bound to record,
+// so next rotation only re-wraps their data keys.
+// Key of account with master password is derived from new master password and its parameters are saved
+// in same transaction, so key derived at next login opens re-encrypted data.
+// Recovery kit rebuilds old key, so it is deleted in same transaction and user must create new one.
+// Rows already readable with new key are skipped, so interrupted rotation is resumed by repeating request
+func (s *Service) Rotate(ctx context.Context, r handlers.RotateKeyRequest) (*handlers.RotateKeyResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := s.keyParamsRepo.GetByLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if params != nil {
+		return s.rotateMasterPassword(ctx, user, params, r)
+	}
+
+	if len(r.NewKey) < minKeyLength || r.NewKey == r.OldKey {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_NEW_KEY, http.StatusBadRequest)
+	}
+	// key is already new one if rotation was committed but not finished
+	if key != r.OldKey && key != r.NewKey {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_OLD_KEY, http.StatusBadRequest)
+	}
+
+	var res *handlers.RotateKeyResponse
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if res, err = s.RotateData(ctx, user, r.OldKey, r.NewKey); err != nil {
+			return err
+		}
+		res.RecoveryKitDeleted, err = s.deleteRecoveryKit(ctx, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.keyService.SetKeyForUser(user, r.NewKey); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// rotateMasterPassword re-encrypt rows with key derived from new master password and replace key parameters of user
+func (s *Service) rotateMasterPassword(ctx context.Context, user string, params *entity.KeyParams, r handlers.RotateKeyRequest) (*handlers.RotateKeyResponse, error) {
+	oldKey, err := s.keyService.UnlockKey(r.OldMasterPassword, params)
+	if err != nil {
+		return nil, err
+	}
+
+	newKey, newParams, err := s.keyService.NewKeyParams(user, r.NewMasterPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *handlers.RotateKeyResponse
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if res, err = s.RotateData(ctx, user, oldKey, newKey); err != nil {
+			return err
+		}
+		if res.RecoveryKitDeleted, err = s.deleteRecoveryKit(ctx, user); err != nil {
+			return err
+		}
+		return s.keyParamsRepo.Save(ctx, *newParams)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.keyService.SetKeyForUser(user, newKey); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// deleteRecoveryKit delete recovery kit of user, false if user has no kit
+func (s *Service) deleteRecoveryKit(ctx context.Context, user string) (bool, error) {
+	kit, err := s.recoveryKitRepo.GetByLogin(ctx, user)
+	if err != nil || kit == nil {
+		return false, err
+	}
+	return true, s.recoveryKitRepo.Delete(ctx, user)
+}
+
+// RotateData re-encrypt all rows of user with new key, key of user is not changed.
+// Caller must run it in transaction and check that old key belongs to user
+func (s *Service) RotateData(ctx context.Context, user string, oldKey string, newKey string) (*handlers.RotateKeyResponse, error) {
+	res := &handlers.RotateKeyResponse{}
+
+	data, err := s.dataRepo.GetAllByUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range data {
+		if v.ContentType == entity.Blob {
+			continue
+		}
+		rotated, err := rotateRow(oldKey, newKey, &v.DataKey, &v.Content, v.AssociatedData(), false, v.ContentType == entity.SSHKey)
+		if err != nil {
+			return nil, err
+		}
+		if !rotated {
+			continue
+		}
+		if err = s.dataRepo.Update(ctx, *v); err != nil {
+			return nil, err
+		}
+		res.Data++
+	}
+
+	uuids, err := s.fileRepo.GetUUIDsByUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	for _, uuid := range uuids {
+		file, err := s.fileRepo.GetByUUID(ctx, user, uuid)
+		if err != nil {
+			return nil, err
+		}
+		rotated, err := rotateRow(oldKey, newKey, &file.DataKey, &file.Content, file.AssociatedData(), file.Chunked, false)
+		if err != nil {
+			return nil, err
+		}
+		if !rotated {
+			continue
+		}
+		if err = s.fileRepo.Update(ctx, *file); err != nil {
+			return nil, err
+		}
+		res.Files++
+	}
+
+	return res, nil
+}
+
+// rotateRow re-wrap bound data key with new key, other rows are decrypted and sealed in envelope bound to record,
+// with same key row is only bound. Data key of stream ciphertext is re-wrapped and bound, stream is not re-encrypted.
+// Ssh key row is always sealed again, data key of its private key is re-wrapped inside it.
+// False if row is already rotated or has nothing to upgrade
+func rotateRow(oldKey string, newKey string, dataKey *[]byte, content *[]byte, associatedData []byte, stream bool, sshKey bool) (bool, error) {
+	if stream || (lib.IsBound(*dataKey) && !sshKey) {
+		rewrapped, err := lib.RewrapKey(oldKey, newKey, *dataKey, associatedData)
+		if errors.Is(err, lib.ErrIntegrity) && unwraps(newKey, *dataKey, associatedData) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		*dataKey = rewrapped
+		return true, nil
+	}
+
+	plaintext, err := lib.OpenEnvelope(oldKey, *dataKey, *content, associatedData)
+	if errors.Is(err, lib.ErrIntegrity) && opens(newKey, *dataKey, *content, associatedData) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if sshKey {
+		// private key is sealed with key row was written with
+		var sealed bool
+		if plaintext, sealed, err = sshkey.SealPrivateKey(oldKey, newKey, plaintext, associatedData); err != nil {
+			return false, err
+		}
+		if !sealed && lib.IsBound(*dataKey) {
+			return false, nil
+		}
+	}
+	*content, *dataKey, err = lib.SealEnvelope(newKey, plaintext, associatedData)
+	return err == nil, err
+}
+
+func unwraps(key string, dataKey []byte, associatedData []byte) bool {
+	_, err := lib.UnwrapKey(key, dataKey, associatedData)
+	return err == nil
+}
+
+func opens(key string, dataKey []byte, content []byte, associatedData []byte) bool {
+	_, err := lib.OpenEnvelope(key, dataKey, content, associatedData)
+	return err == nil
+}
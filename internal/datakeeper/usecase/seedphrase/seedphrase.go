@@ -0,0 +1,190 @@
+package seedphrase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type seedPhraseContent struct {
+	Name       string   `json:"name"`
+	Words      []string `json:"words"`
+	Passphrase string   `json:"passphrase"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewSeedPhraseService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Create validate mnemonic and save seed phrase
+func (s *Service) Create(ctx context.Context, r handlers.CreateSeedPhraseRequest) (*handlers.CreateSeedPhraseResponse, error) {
+	words := strings.Fields(strings.ToLower(r.Phrase))
+	if err := lib.ValidateMnemonic(words); err != nil {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_SEED_PHRASE+": "+err.Error(), http.StatusBadRequest)
+	}
+
+	jsonData, err := json.Marshal(&seedPhraseContent{Name: r.Name, Words: words, Passphrase: r.Passphrase})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.SeedPhrase,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateSeedPhraseResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Delete delete seed phrase
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteSeedPhraseRequest) (*handlers.DeleteSeedPhraseResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteSeedPhraseResponse{UUID: r.UUID}, nil
+}
+
+// GetAll get all seed phrases, words never leave this method
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllSeedPhrasesRequest) (*handlers.GetAllSeedPhrasesResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.SeedPhrase)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllSeedPhrasesResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		content := seedPhraseContent{}
+		err = json.Unmarshal(jsonDecrypted, &content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, handlers.GetAllSeedPhrasesResponseItem{
+			UUID:          v.UUID,
+			Name:          content.Name,
+			WordCount:     len(content.Words),
+			HasPassphrase: content.Passphrase != "",
+		})
+	}
+
+	return &handlers.GetAllSeedPhrasesResponse{Items: items}, nil
+}
+
+// Reveal get words and passphrase of seed phrase
+func (s *Service) Reveal(ctx context.Context, r handlers.RevealSeedPhraseRequest) (*handlers.RevealSeedPhraseResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	content := seedPhraseContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.RevealSeedPhraseResponse{Words: content.Words, Passphrase: content.Passphrase}, nil
+}
+
+// load seed phrase without decrypting it, row of another content type is rejected
+func (s *Service) load(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fromDB.ContentType != entity.SeedPhrase {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_SEED_PHRASE, http.StatusBadRequest)
+	}
+	return fromDB, nil
+}
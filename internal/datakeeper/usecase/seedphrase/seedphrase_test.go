@@ -0,0 +1,148 @@
+package seedphrase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedPhraseService_Create(t *testing.T) {
+	mockRepo := new(MockSeedPhraseRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewSeedPhraseService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, handlers.CreateSeedPhraseRequest{Name: "treasury", Phrase: "  Legal winner thank year wave sausage worth useful legal winner thank yellow\n"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+
+	_, err = service.Create(ctx, handlers.CreateSeedPhraseRequest{Name: "treasury", Phrase: "legal winner thank year wave sausage worth useful legal winner thank year"})
+	assert.ErrorContains(t, err, customerr.INVALID_SEED_PHRASE)
+
+	_, err = service.Create(ctx, handlers.CreateSeedPhraseRequest{Name: "treasury", Phrase: "legal winner thank"})
+	assert.ErrorContains(t, err, customerr.INVALID_SEED_PHRASE)
+
+	mockRepo.AssertNumberOfCalls(t, "Insert", 1)
+}
+
+func TestSeedPhraseService_GetAll(t *testing.T) {
+	mockRepo := new(MockSeedPhraseRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewSeedPhraseService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	jsonContent, _ := json.Marshal(&seedPhraseContent{Name: "treasury", Words: []string{"legal", "winner"}, Passphrase: "extra"})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := []*entity.Data{
+		{UUID: uuid.New().String(), Content: encryptedContent, ContentType: entity.SeedPhrase},
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.SeedPhrase).Return(data, nil)
+
+	response, err := service.GetAll(ctx, handlers.GetAllSeedPhrasesRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, handlers.GetAllSeedPhrasesResponseItem{
+		UUID:          data[0].UUID,
+		Name:          "treasury",
+		WordCount:     2,
+		HasPassphrase: true,
+	}, response.Items[0])
+}
+
+func TestSeedPhraseService_Reveal(t *testing.T) {
+	mockRepo := new(MockSeedPhraseRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewSeedPhraseService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	words := []string{"legal", "winner", "thank", "year", "wave", "sausage", "worth", "useful", "legal", "winner", "thank", "yellow"}
+	jsonContent, _ := json.Marshal(&seedPhraseContent{Name: "treasury", Words: words})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.SeedPhrase}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	response, err := service.Reveal(ctx, handlers.RevealSeedPhraseRequest{UUID: uuidStr})
+
+	require.NoError(t, err)
+	assert.Equal(t, words, response.Words)
+	assert.NoError(t, lib.ValidateMnemonic(response.Words))
+}
+
+func TestSeedPhraseService_Delete(t *testing.T) {
+	mockRepo := new(MockSeedPhraseRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewSeedPhraseService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	uuidStr := uuid.New().String()
+	data := entity.Data{UUID: uuidStr, ContentType: entity.SeedPhrase}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("Delete", mock.Anything, user, uuidStr).Return(nil)
+
+	response, err := service.Delete(ctx, handlers.DeleteSeedPhraseRequest{UUID: uuidStr})
+
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+}
+
+func TestSeedPhraseService_WrongType(t *testing.T) {
+	mockRepo := new(MockSeedPhraseRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewSeedPhraseService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	data := entity.Data{UUID: uuidStr, ContentType: entity.Note, CreatedBy: user}
+	jsonContent, _ := json.Marshal(map[string]string{"title": "todo"})
+	data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	_, err := service.Reveal(ctx, handlers.RevealSeedPhraseRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.INVALID_SEED_PHRASE)
+
+	_, err = service.Delete(ctx, handlers.DeleteSeedPhraseRequest{UUID: uuidStr})
+	assert.EqualError(t, err, customerr.INVALID_SEED_PHRASE)
+
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
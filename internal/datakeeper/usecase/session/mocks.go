@@ -0,0 +1,44 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepo is a mock implementation of Repo
+type MockRepo struct {
+	mock.Mock
+}
+
+func (m *MockRepo) CreateFamily(ctx context.Context, family entity.TokenFamily) error {
+	args := m.Called(ctx, family)
+	return args.Error(0)
+}
+
+func (m *MockRepo) IsFamilyRevoked(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepo) RevokeFamily(ctx context.Context, id string, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepo) InsertToken(ctx context.Context, token entity.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRepo) GetToken(ctx context.Context, hash []byte) (*entity.RefreshToken, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(*entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRepo) MarkTokenUsed(ctx context.Context, hash []byte, usedAt time.Time) (bool, error) {
+	args := m.Called(ctx, hash, usedAt)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// refreshTokenSize random bytes of refresh token
+const refreshTokenSize = 32
+
+type Repo interface {
+	CreateFamily(ctx context.Context, family entity.TokenFamily) error
+	IsFamilyRevoked(ctx context.Context, id string) (bool, error)
+	RevokeFamily(ctx context.Context, id string, revokedAt time.Time) error
+	InsertToken(ctx context.Context, token entity.RefreshToken) error
+	GetToken(ctx context.Context, hash []byte) (*entity.RefreshToken, error)
+	MarkTokenUsed(ctx context.Context, hash []byte, usedAt time.Time) (bool, error)
+}
+
+// Service sessions of signed in users.
+// Session is a family of refresh tokens, every refresh token is exchanged once for new access and refresh tokens.
+// Access tokens carry family id in "sid" claim, so revoked session rejects them before they expire
+type Service struct {
+	repo   Repo
+	auth   config.AuthService
+	config config.Session
+}
+
+// NewSessionService access tokens are signed by HS256 with JWT key, issuer and audience are taken from auth config
+func NewSessionService(repo Repo, auth config.AuthService, config config.Session) *Service {
+	return &Service{
+		repo:   repo,
+		auth:   auth,
+		config: config,
+	}
+}
+
+// Issue start new session of user
+func (s *Service) Issue(ctx context.Context, login string) (*handlers.SessionTokens, error) {
+	family := entity.TokenFamily{
+		ID:        uuid.NewString(),
+		Login:     login,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateFamily(ctx, family); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, family.ID, login)
+}
+
+// Refresh exchange refresh token for new tokens of the same session.
+// Used token presented again means it was stolen, whole session is revoked
+func (s *Service) Refresh(ctx context.Context, r handlers.RefreshRequest) (*handlers.RefreshResponse, error) {
+	token, err := s.findToken(ctx, r.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	used, err := s.repo.MarkTokenUsed(ctx, token.Hash, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		if err = s.repo.RevokeFamily(ctx, token.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, customerr.ErrorWithCode(customerr.REFRESH_TOKEN_REUSED, http.StatusUnauthorized)
+	}
+
+	revoked, err := s.repo.IsFamilyRevoked(ctx, token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked || now.After(token.ExpiresAt) {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_REFRESH_TOKEN, http.StatusUnauthorized)
+	}
+
+	tokens, err := s.issueTokens(ctx, token.FamilyID, token.Login)
+	if err != nil {
+		return nil, err
+	}
+	return &handlers.RefreshResponse{SessionTokens: *tokens}, nil
+}
+
+// Logout revoke session of refresh token, access tokens of session are rejected too
+func (s *Service) Logout(ctx context.Context, r handlers.LogoutRequest) (*handlers.LogoutResponse, error) {
+	token, err := s.findToken(ctx, r.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.RevokeFamily(ctx, token.FamilyID, time.Now()); err != nil {
+		return nil, err
+	}
+	return &handlers.LogoutResponse{LoggedOut: true}, nil
+}
+
+// IsRevoked true if session of access token is revoked
+func (s *Service) IsRevoked(ctx context.Context, familyID string) (bool, error) {
+	return s.repo.IsFamilyRevoked(ctx, familyID)
+}
+
+// findToken stored refresh token by its value
+func (s *Service) findToken(ctx context.Context, value string) (*entity.RefreshToken, error) {
+	if value == "" {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_REFRESH_TOKEN, http.StatusUnauthorized)
+	}
+	token, err := s.repo.GetToken(ctx, hashToken(value))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_REFRESH_TOKEN, http.StatusUnauthorized)
+	}
+	return token, nil
+}
+
+// issueTokens sign access token and store new refresh token of family
+func (s *Service) issueTokens(ctx context.Context, familyID string, login string) (*handlers.SessionTokens, error) {
+	now := time.Now()
+	tokens := &handlers.SessionTokens{
+		ExpiresAt:        now.Add(s.config.AccessTokenTTL),
+		RefreshExpiresAt: now.Add(s.config.RefreshTokenTTL),
+	}
+
+	claims := jwt.MapClaims{
+		"email": login,
+		"sid":   familyID,
+		"iat":   now.Unix(),
+		"nbf":   now.Unix(),
+		"exp":   tokens.ExpiresAt.Unix(),
+	}
+	if s.auth.JWTIssuer != "" {
+		claims["iss"] = s.auth.JWTIssuer
+	}
+	if s.auth.JWTAudience != "" {
+		claims["aud"] = s.auth.JWTAudience
+	}
+
+	var err error
+	tokens.Token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.auth.JWTKey))
+	if err != nil {
+		return nil, err
+	}
+
+	value := make([]byte, refreshTokenSize)
+	if _, err = io.ReadFull(rand.Reader, value); err != nil {
+		return nil, err
+	}
+	tokens.RefreshToken = base64.RawURLEncoding.EncodeToString(value)
+
+	err = s.repo.InsertToken(ctx, entity.RefreshToken{
+		Hash:      hashToken(tokens.RefreshToken),
+		FamilyID:  familyID,
+		Login:     login,
+		ExpiresAt: tokens.RefreshExpiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// hashToken SHA-256 of refresh token, token has enough entropy to not need salt or slow hash
+func hashToken(value string) []byte {
+	sum := sha256.Sum256([]byte(value))
+	return sum[:]
+}
@@ -0,0 +1,136 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/config"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const jwtKey = "test-jwt-key"
+
+var (
+	testAuth   = config.AuthService{JWTKey: jwtKey, JWTIssuer: "data-keeper", JWTAudience: "data-keeper-api"}
+	testConfig = config.Session{AccessTokenTTL: 15 * time.Minute, RefreshTokenTTL: time.Hour}
+)
+
+func TestSessionService_Issue(t *testing.T) {
+	mockRepo := new(MockRepo)
+	service := NewSessionService(mockRepo, testAuth, testConfig)
+
+	var family entity.TokenFamily
+	var stored entity.RefreshToken
+	mockRepo.On("CreateFamily", mock.Anything, mock.MatchedBy(func(f entity.TokenFamily) bool {
+		family = f
+		return f.Login == "test_user"
+	})).Return(nil)
+	mockRepo.On("InsertToken", mock.Anything, mock.MatchedBy(func(token entity.RefreshToken) bool {
+		stored = token
+		return true
+	})).Return(nil)
+
+	tokens, err := service.Issue(context.Background(), "test_user")
+	require.NoError(t, err)
+
+	// only hash of refresh token is stored
+	assert.Equal(t, hashToken(tokens.RefreshToken), stored.Hash)
+	assert.NotContains(t, string(stored.Hash), tokens.RefreshToken)
+	assert.Equal(t, family.ID, stored.FamilyID)
+	assert.Equal(t, tokens.RefreshExpiresAt, stored.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(testConfig.AccessTokenTTL), tokens.ExpiresAt, time.Second)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokens.Token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtKey), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test_user", claims["email"])
+	assert.Equal(t, family.ID, claims["sid"])
+	assert.Equal(t, "data-keeper", claims["iss"])
+	assert.Equal(t, "data-keeper-api", claims["aud"])
+}
+
+func TestSessionService_Refresh(t *testing.T) {
+	mockRepo := new(MockRepo)
+	service := NewSessionService(mockRepo, testAuth, testConfig)
+
+	token := &entity.RefreshToken{
+		Hash:      hashToken("refresh"),
+		FamilyID:  "family",
+		Login:     "test_user",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	mockRepo.On("GetToken", mock.Anything, hashToken("refresh")).Return(token, nil)
+	mockRepo.On("MarkTokenUsed", mock.Anything, token.Hash, mock.Anything).Return(true, nil).Once()
+	mockRepo.On("IsFamilyRevoked", mock.Anything, "family").Return(false, nil)
+	mockRepo.On("InsertToken", mock.Anything, mock.MatchedBy(func(t entity.RefreshToken) bool {
+		return t.FamilyID == "family" && t.Login == "test_user"
+	})).Return(nil)
+
+	response, err := service.Refresh(context.Background(), handlers.RefreshRequest{RefreshToken: "refresh"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	assert.NotEqual(t, "refresh", response.RefreshToken)
+
+	// second use of the same token revokes whole family
+	mockRepo.On("MarkTokenUsed", mock.Anything, token.Hash, mock.Anything).Return(false, nil).Once()
+	mockRepo.On("RevokeFamily", mock.Anything, "family", mock.Anything).Return(nil)
+
+	_, err = service.Refresh(context.Background(), handlers.RefreshRequest{RefreshToken: "refresh"})
+	var customErr *customerr.CustomError
+	require.ErrorAs(t, err, &customErr)
+	assert.Equal(t, customerr.REFRESH_TOKEN_REUSED, customErr.Message)
+	assert.Equal(t, http.StatusUnauthorized, customErr.Code)
+	mockRepo.AssertCalled(t, "RevokeFamily", mock.Anything, "family", mock.Anything)
+	mockRepo.AssertNumberOfCalls(t, "InsertToken", 1)
+}
+
+func TestSessionService_RefreshInvalid(t *testing.T) {
+	mockRepo := new(MockRepo)
+	service := NewSessionService(mockRepo, testAuth, testConfig)
+
+	expired := &entity.RefreshToken{Hash: hashToken("expired"), FamilyID: "family", ExpiresAt: time.Now().Add(-time.Minute)}
+	revoked := &entity.RefreshToken{Hash: hashToken("revoked"), FamilyID: "revoked", ExpiresAt: time.Now().Add(time.Hour)}
+	mockRepo.On("GetToken", mock.Anything, hashToken("unknown")).Return((*entity.RefreshToken)(nil), nil)
+	mockRepo.On("GetToken", mock.Anything, expired.Hash).Return(expired, nil)
+	mockRepo.On("GetToken", mock.Anything, revoked.Hash).Return(revoked, nil)
+	mockRepo.On("MarkTokenUsed", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockRepo.On("IsFamilyRevoked", mock.Anything, "family").Return(false, nil)
+	mockRepo.On("IsFamilyRevoked", mock.Anything, "revoked").Return(true, nil)
+
+	for _, value := range []string{"", "unknown", "expired", "revoked"} {
+		_, err := service.Refresh(context.Background(), handlers.RefreshRequest{RefreshToken: value})
+		var customErr *customerr.CustomError
+		require.ErrorAs(t, err, &customErr, "token %q", value)
+		assert.Equal(t, customerr.INVALID_REFRESH_TOKEN, customErr.Message)
+		assert.Equal(t, http.StatusUnauthorized, customErr.Code)
+	}
+	mockRepo.AssertNotCalled(t, "InsertToken", mock.Anything, mock.Anything)
+}
+
+func TestSessionService_Logout(t *testing.T) {
+	mockRepo := new(MockRepo)
+	service := NewSessionService(mockRepo, testAuth, testConfig)
+
+	token := &entity.RefreshToken{Hash: hashToken("refresh"), FamilyID: "family"}
+	mockRepo.On("GetToken", mock.Anything, token.Hash).Return(token, nil)
+	mockRepo.On("RevokeFamily", mock.Anything, "family", mock.Anything).Return(nil)
+	mockRepo.On("IsFamilyRevoked", mock.Anything, "family").Return(true, nil)
+
+	response, err := service.Logout(context.Background(), handlers.LogoutRequest{RefreshToken: "refresh"})
+	require.NoError(t, err)
+	assert.True(t, response.LoggedOut)
+
+	revoked, err := service.IsRevoked(context.Background(), "family")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
@@ -0,0 +1,126 @@
+package sshkey
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"strings"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// TypeEd25519 ed25519 key pair
+	TypeEd25519 = "ed25519"
+	// TypeRSA rsa key pair
+	TypeRSA = "rsa"
+
+	defaultRSABits = 3072
+)
+
+// keyPair parsed or generated key pair
+type keyPair struct {
+	// privateKey OpenSSH private key in PEM format
+	privateKey []byte
+	publicKey  ssh.PublicKey
+	comment    string
+	encrypted  bool
+}
+
+// parsePrivateKey parse OpenSSH/PEM private key, passphrase is required for protected keys
+func parsePrivateKey(privateKey []byte, passphrase string) (*keyPair, error) {
+	var (
+		signer ssh.Signer
+		err    error
+	)
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(privateKey, []byte(passphrase))
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			return nil, customerr.ErrorWithCode(customerr.INVALID_SSH_KEY_PASSPHRASE, http.StatusBadRequest)
+		}
+	} else {
+		signer, err = ssh.ParsePrivateKey(privateKey)
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			return nil, customerr.ErrorWithCode(customerr.SSH_KEY_PASSPHRASE_REQUIRED, http.StatusBadRequest)
+		}
+	}
+	if err != nil {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_SSH_KEY, http.StatusBadRequest)
+	}
+	return &keyPair{
+		privateKey: privateKey,
+		publicKey:  signer.PublicKey(),
+		encrypted:  passphrase != "",
+	}, nil
+}
+
+// generateKeyPair generate new ed25519 or rsa key pair in OpenSSH format
+func generateKeyPair(keyType string, bits int, comment string, passphrase string) (*keyPair, error) {
+	var (
+		private crypto.PrivateKey
+		public  crypto.PublicKey
+	)
+	switch keyType {
+	case TypeEd25519, "":
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private, public = priv, pub
+	case TypeRSA:
+		if bits == 0 {
+			bits = defaultRSABits
+		}
+		if bits < 2048 || bits > 8192 {
+			return nil, customerr.ErrorWithCode(customerr.UNSUPPORTED_SSH_KEY_TYPE, http.StatusBadRequest)
+		}
+		priv, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		private, public = priv, &priv.PublicKey
+	default:
+		return nil, customerr.ErrorWithCode(customerr.UNSUPPORTED_SSH_KEY_TYPE, http.StatusBadRequest)
+	}
+
+	var (
+		block *pem.Block
+		err   error
+	)
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(private, comment, []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(private, comment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyPair{
+		privateKey: pem.EncodeToMemory(block),
+		publicKey:  publicKey,
+		comment:    comment,
+		encrypted:  passphrase != "",
+	}, nil
+}
+
+// authorizedKey public key in authorized_keys format
+func authorizedKey(publicKey ssh.PublicKey, comment string) string {
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey)))
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
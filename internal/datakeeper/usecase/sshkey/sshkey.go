@@ -0,0 +1,297 @@
+package sshkey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+// sshKeyContent whole record is encrypted, private key is sealed in its own envelope
+// and is only opened on explicit request
+type sshKeyContent struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	Encrypted   bool   `json:"encrypted"`
+	// SealedPrivateKey OpenSSH private key encrypted with its own data key
+	SealedPrivateKey []byte `json:"sealed_private_key,omitempty"`
+	// PrivateDataKey data key of private key wrapped by user key
+	PrivateDataKey []byte `json:"private_data_key,omitempty"`
+	// PrivateKey OpenSSH private key stored in record by earlier versions, moved to its own envelope by SealPrivateKey
+	PrivateKey string `json:"private_key_pem,omitempty"`
+	// NestedPrivateKey private key encrypted once more with user key by earlier versions,
+	// it is not re-encrypted together with record and is moved to its own envelope by SealPrivateKey
+	NestedPrivateKey []byte `json:"private_key,omitempty"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewSSHKeyService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Import save existing OpenSSH private key
+func (s *Service) Import(ctx context.Context, r handlers.ImportSSHKeyRequest) (*handlers.SSHKeyResponse, error) {
+	pair, err := parsePrivateKey([]byte(r.PrivateKey), r.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	pair.comment = r.Comment
+	return s.save(ctx, r.Name, pair)
+}
+
+// Generate generate and save new key pair
+func (s *Service) Generate(ctx context.Context, r handlers.GenerateSSHKeyRequest) (*handlers.SSHKeyResponse, error) {
+	pair, err := generateKeyPair(r.Type, r.Bits, r.Comment, r.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, r.Name, pair)
+}
+
+func (s *Service) save(ctx context.Context, name string, pair *keyPair) (*handlers.SSHKeyResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	content := sshKeyContent{
+		Name:        name,
+		Type:        pair.publicKey.Type(),
+		PublicKey:   authorizedKey(pair.publicKey, pair.comment),
+		Fingerprint: ssh.FingerprintSHA256(pair.publicKey),
+		Encrypted:   pair.encrypted,
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.SSHKey,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	content.SealedPrivateKey, content.PrivateDataKey, err = lib.SealEnvelope(key, pair.privateKey, privateAssociatedData(newDataToSave.AssociatedData()))
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.SSHKeyResponse{
+		UUID:        newDataToSave.UUID,
+		PublicKey:   content.PublicKey,
+		Fingerprint: content.Fingerprint,
+	}, nil
+}
+
+// Delete delete ssh key
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteSSHKeyRequest) (*handlers.DeleteSSHKeyResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteSSHKeyResponse{UUID: r.UUID}, nil
+}
+
+// GetAll get all ssh keys with public keys and fingerprints, envelopes of private keys are not opened
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllSSHKeysRequest) (*handlers.GetAllSSHKeysResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.SSHKey)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllSSHKeysResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		content := sshKeyContent{}
+		err = json.Unmarshal(jsonDecrypted, &content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, handlers.GetAllSSHKeysResponseItem{
+			UUID:        v.UUID,
+			Name:        content.Name,
+			Type:        content.Type,
+			PublicKey:   content.PublicKey,
+			Fingerprint: content.Fingerprint,
+			Encrypted:   content.Encrypted,
+		})
+	}
+
+	return &handlers.GetAllSSHKeysResponse{Items: items}, nil
+}
+
+// GetPrivateKey get private key in OpenSSH format
+func (s *Service) GetPrivateKey(ctx context.Context, r handlers.GetSSHPrivateKeyRequest) (*handlers.GetSSHPrivateKeyResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, err := s.load(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	content := sshKeyContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := openPrivateKey(key, &content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.GetSSHPrivateKeyResponse{PrivateKey: string(privateKey)}, nil
+}
+
+// load ssh key without decrypting it, row of another content type is not found
+func (s *Service) load(ctx context.Context, user string, uuid string) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if fromDB.ContentType != entity.SSHKey {
+		return nil, customerr.ErrorWithCode(customerr.SSH_KEY_NOT_FOUND, http.StatusNotFound)
+	}
+	return fromDB, nil
+}
+
+// SealPrivateKey re-wrap data key of private key in decrypted content from oldKey to newKey,
+// private key stored in record or nested by earlier versions is moved to its own envelope sealed with newKey.
+// oldKey must be the one record was written with. False if private key is already sealed with newKey
+func SealPrivateKey(oldKey string, newKey string, plaintext []byte, associatedData []byte) ([]byte, bool, error) {
+	content := sshKeyContent{}
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return nil, false, err
+	}
+
+	if content.PrivateDataKey != nil {
+		if oldKey == newKey {
+			return plaintext, false, nil
+		}
+		rewrapped, err := lib.RewrapKey(oldKey, newKey, content.PrivateDataKey, privateAssociatedData(associatedData))
+		if err != nil {
+			return nil, false, err
+		}
+		content.PrivateDataKey = rewrapped
+	} else {
+		privateKey, err := openPrivateKey(oldKey, &content, associatedData)
+		if err != nil {
+			return nil, false, err
+		}
+		content.SealedPrivateKey, content.PrivateDataKey, err = lib.SealEnvelope(newKey, privateKey, privateAssociatedData(associatedData))
+		if err != nil {
+			return nil, false, err
+		}
+		content.PrivateKey = ""
+		content.NestedPrivateKey = nil
+	}
+
+	sealed, err := json.Marshal(&content)
+	if err != nil {
+		return nil, false, err
+	}
+	return sealed, true, nil
+}
+
+// openPrivateKey open envelope of private key, key is user key record was written with
+func openPrivateKey(key string, content *sshKeyContent, associatedData []byte) ([]byte, error) {
+	switch {
+	case content.PrivateDataKey != nil:
+		return lib.OpenEnvelope(key, content.PrivateDataKey, content.SealedPrivateKey, privateAssociatedData(associatedData))
+	case content.NestedPrivateKey != nil:
+		return lib.Decrypt(key, content.NestedPrivateKey)
+	default:
+		return []byte(content.PrivateKey), nil
+	}
+}
+
+// privateAssociatedData bind envelope of private key to record and to its place in it,
+// so it can't be swapped with envelope of record
+func privateAssociatedData(associatedData []byte) []byte {
+	return append(bytes.Clone(associatedData), lib.AssociatedData("private_key")...)
+}
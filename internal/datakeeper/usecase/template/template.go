@@ -0,0 +1,387 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type templateContent struct {
+	Name   string                   `json:"name"`
+	Fields []handlers.TemplateField `json:"fields"`
+}
+
+type recordContent struct {
+	TemplateUUID string                 `json:"template_uuid"`
+	Name         string                 `json:"name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewTemplateService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// CreateTemplate create record type
+func (s *Service) CreateTemplate(ctx context.Context, r handlers.CreateTemplateRequest) (*handlers.CreateTemplateResponse, error) {
+	content := templateContent{Name: r.Name, Fields: r.Fields}
+	if err := validateTemplate(content); err != nil {
+		return nil, err
+	}
+
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.insert(ctx, user, key, entity.Template, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateTemplateResponse{UUID: data.UUID}, nil
+}
+
+// UpdateTemplate update record type, existing records are validated on their next update
+func (s *Service) UpdateTemplate(ctx context.Context, r handlers.UpdateTemplateRequest) (*handlers.UpdateTemplateResponse, error) {
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	content := templateContent{}
+	fromDB, err := s.get(ctx, user, key, r.UUID, entity.Template, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Name != nil {
+		content.Name = *r.Name
+	}
+	if r.Fields != nil {
+		content.Fields = r.Fields
+	}
+	if err = validateTemplate(content); err != nil {
+		return nil, err
+	}
+
+	if err = s.update(ctx, key, fromDB, &content); err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateTemplateResponse{UUID: fromDB.UUID}, nil
+}
+
+// DeleteTemplate delete record type if there are no records of this type
+func (s *Service) DeleteTemplate(ctx context.Context, r handlers.DeleteTemplateRequest) (*handlers.DeleteTemplateResponse, error) {
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.load(ctx, user, r.UUID, entity.Template); err != nil {
+		return nil, err
+	}
+
+	records, err := s.records(ctx, user, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.content.TemplateUUID == r.UUID {
+			return nil, customerr.ErrorWithCode(customerr.TEMPLATE_IN_USE, http.StatusConflict)
+		}
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteTemplateResponse{UUID: r.UUID}, nil
+}
+
+// GetAllTemplates get all record types
+func (s *Service) GetAllTemplates(ctx context.Context, r handlers.GetAllTemplatesRequest) (*handlers.GetAllTemplatesResponse, error) {
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllTemplatesResponseItem, 0, len(data))
+	for _, v := range data {
+		content := templateContent{}
+		if err = decrypt(key, v, &content); err != nil {
+			return nil, err
+		}
+		items = append(items, handlers.GetAllTemplatesResponseItem{
+			UUID:   v.UUID,
+			Name:   content.Name,
+			Fields: content.Fields,
+		})
+	}
+
+	return &handlers.GetAllTemplatesResponse{Items: items}, nil
+}
+
+// CreateRecord create record validated against its template
+func (s *Service) CreateRecord(ctx context.Context, r handlers.CreateRecordRequest) (*handlers.CreateRecordResponse, error) {
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := templateContent{}
+	if _, err = s.get(ctx, user, key, r.TemplateUUID, entity.Template, &tmpl); err != nil {
+		return nil, err
+	}
+
+	content := recordContent{TemplateUUID: r.TemplateUUID, Name: r.Name, Values: r.Values}
+	if err = validateRecord(tmpl.Fields, content.Values); err != nil {
+		return nil, err
+	}
+
+	data, err := s.insert(ctx, user, key, entity.Record, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateRecordResponse{UUID: data.UUID}, nil
+}
+
+// UpdateRecord merge values into record and validate it against current template
+func (s *Service) UpdateRecord(ctx context.Context, r handlers.UpdateRecordRequest) (*handlers.UpdateRecordResponse, error) {
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	content := recordContent{}
+	fromDB, err := s.get(ctx, user, key, r.UUID, entity.Record, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := templateContent{}
+	if _, err = s.get(ctx, user, key, content.TemplateUUID, entity.Template, &tmpl); err != nil {
+		return nil, err
+	}
+
+	if r.Name != nil {
+		content.Name = *r.Name
+	}
+	if content.Values == nil {
+		content.Values = make(map[string]interface{}, len(r.Values))
+	}
+	for name, value := range r.Values {
+		if value == nil {
+			delete(content.Values, name)
+			continue
+		}
+		content.Values[name] = value
+	}
+	if err = validateRecord(tmpl.Fields, content.Values); err != nil {
+		return nil, err
+	}
+
+	if err = s.update(ctx, key, fromDB, &content); err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateRecordResponse{UUID: fromDB.UUID}, nil
+}
+
+// DeleteRecord delete record
+func (s *Service) DeleteRecord(ctx context.Context, r handlers.DeleteRecordRequest) (*handlers.DeleteRecordResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.load(ctx, user, r.UUID, entity.Record); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteRecordResponse{UUID: r.UUID}, nil
+}
+
+// GetAllRecords get all records, optionally filtered by template
+func (s *Service) GetAllRecords(ctx context.Context, r handlers.GetAllRecordsRequest) (*handlers.GetAllRecordsResponse, error) {
+	user, key, err := s.userAndKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.records(ctx, user, key)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllRecordsResponseItem, 0, len(records))
+	for _, record := range records {
+		if r.TemplateUUID != "" && record.content.TemplateUUID != r.TemplateUUID {
+			continue
+		}
+		items = append(items, handlers.GetAllRecordsResponseItem{
+			UUID:         record.uuid,
+			TemplateUUID: record.content.TemplateUUID,
+			Name:         record.content.Name,
+			Values:       record.content.Values,
+		})
+	}
+
+	return &handlers.GetAllRecordsResponse{Items: items}, nil
+}
+
+type decryptedRecord struct {
+	uuid    string
+	content recordContent
+}
+
+// records get all decrypted records for user
+func (s *Service) records(ctx context.Context, user string, key string) ([]decryptedRecord, error) {
+	data, err := s.repo.GetByUser(ctx, user, entity.Record)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]decryptedRecord, 0, len(data))
+	for _, v := range data {
+		record := decryptedRecord{uuid: v.UUID}
+		if err = decrypt(key, v, &record.content); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (s *Service) userAndKey(ctx context.Context) (string, string, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return "", "", err
+	}
+
+	return user, key, nil
+}
+
+// get load and decrypt data of expected content type
+func (s *Service) get(ctx context.Context, user string, key string, uuid string, contentType entity.ContentType, content interface{}) (*entity.Data, error) {
+	fromDB, err := s.load(ctx, user, uuid, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = decrypt(key, fromDB, content); err != nil {
+		return nil, err
+	}
+
+	return fromDB, nil
+}
+
+// load data of expected content type without decrypting it
+func (s *Service) load(ctx context.Context, user string, uuid string, contentType entity.ContentType) (*entity.Data, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil || fromDB.ContentType != contentType {
+		if contentType == entity.Template {
+			return nil, customerr.ErrorWithCode(customerr.TEMPLATE_NOT_FOUND, http.StatusNotFound)
+		}
+		if err == nil {
+			err = customerr.ErrorWithCode(customerr.INVALID_RECORD, http.StatusBadRequest)
+		}
+		return nil, err
+	}
+
+	return fromDB, nil
+}
+
+func (s *Service) insert(ctx context.Context, user string, key string, contentType entity.ContentType, content interface{}) (*entity.Data, error) {
+	jsonData, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: contentType,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &newDataToSave, nil
+}
+
+func (s *Service) update(ctx context.Context, key string, fromDB *entity.Data, content interface{}) error {
+	jsonData, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, *fromDB)
+}
+
+func decrypt(key string, data *entity.Data, content interface{}) error {
+	jsonDecrypted, err := lib.OpenEnvelope(key, data.DataKey, data.Content, data.AssociatedData())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonDecrypted, content)
+}
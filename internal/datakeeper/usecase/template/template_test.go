@@ -0,0 +1,289 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var serverFields = []handlers.TemplateField{
+	{Name: "host", Kind: string(URL), Required: true},
+	{Name: "port", Kind: string(Number)},
+	{Name: "password", Kind: string(Hidden)},
+	{Name: "until", Kind: string(Date)},
+	{Name: "active", Kind: string(Boolean)},
+}
+
+func encrypted(t *testing.T, key string, content interface{}) []byte {
+	jsonContent, err := json.Marshal(content)
+	require.NoError(t, err)
+	encryptedContent, err := lib.Encrypt(key, jsonContent)
+	require.NoError(t, err)
+	return encryptedContent
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, validateTemplate(templateContent{Name: "server", Fields: serverFields}))
+
+	err := validateTemplate(templateContent{Fields: serverFields})
+	assert.ErrorContains(t, err, customerr.INVALID_TEMPLATE)
+
+	err = validateTemplate(templateContent{Name: "server"})
+	assert.ErrorContains(t, err, customerr.INVALID_TEMPLATE)
+
+	err = validateTemplate(templateContent{Name: "server", Fields: []handlers.TemplateField{
+		{Name: "host", Kind: string(Text)},
+		{Name: "host", Kind: string(URL)},
+	}})
+	assert.ErrorContains(t, err, "duplicate field")
+
+	err = validateTemplate(templateContent{Name: "server", Fields: []handlers.TemplateField{{Name: "host", Kind: "ip"}}})
+	assert.ErrorContains(t, err, "unknown kind")
+}
+
+func TestValidateRecord(t *testing.T) {
+	assert.NoError(t, validateRecord(serverFields, map[string]interface{}{
+		"host":     "https://example.com",
+		"port":     float64(22),
+		"password": "secret",
+		"until":    "2030-01-31",
+		"active":   true,
+	}))
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   string
+	}{
+		{name: "required", values: map[string]interface{}{"port": float64(22)}, want: `field "host" is required`},
+		{name: "url", values: map[string]interface{}{"host": "example.com"}, want: "must be an absolute url"},
+		{name: "number", values: map[string]interface{}{"host": "https://example.com", "port": "22"}, want: "must be a number"},
+		{name: "date", values: map[string]interface{}{"host": "https://example.com", "until": "31.01.2030"}, want: "YYYY-MM-DD"},
+		{name: "boolean", values: map[string]interface{}{"host": "https://example.com", "active": "yes"}, want: "must be a boolean"},
+		{name: "unknown", values: map[string]interface{}{"host": "https://example.com", "user": "root"}, want: `unknown field "user"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRecord(serverFields, tt.values)
+			assert.ErrorContains(t, err, customerr.INVALID_RECORD)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestTemplateService_CreateRecord(t *testing.T) {
+	mockRepo := new(MockTemplateRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewTemplateService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	templateUUID := uuid.New().String()
+	// lib.Decrypt works in place, so every repo call returns its own row
+	tmpl := func() *entity.Data {
+		return &entity.Data{
+			UUID:        templateUUID,
+			Content:     encrypted(t, key, templateContent{Name: "server", Fields: serverFields}),
+			ContentType: entity.Template,
+		}
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, templateUUID).Return(tmpl(), nil).Once()
+	mockRepo.On("GetByUUID", mock.Anything, user, templateUUID).Return(tmpl(), nil).Once()
+	mockRepo.On("Insert", mock.Anything, mock.MatchedBy(func(data entity.Data) bool {
+		return data.ContentType == entity.Record
+	})).Return(nil)
+
+	response, err := service.CreateRecord(ctx, handlers.CreateRecordRequest{
+		TemplateUUID: templateUUID,
+		Name:         "prod",
+		Values:       map[string]interface{}{"host": "https://example.com"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+
+	_, err = service.CreateRecord(ctx, handlers.CreateRecordRequest{
+		TemplateUUID: templateUUID,
+		Name:         "prod",
+		Values:       map[string]interface{}{"port": float64(22)},
+	})
+	assert.ErrorContains(t, err, customerr.INVALID_RECORD)
+
+	mockRepo.AssertNumberOfCalls(t, "Insert", 1)
+}
+
+func TestTemplateService_CreateRecordTemplateNotFound(t *testing.T) {
+	mockRepo := new(MockTemplateRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewTemplateService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	noteUUID := uuid.New().String()
+	note := entity.Data{UUID: noteUUID, Content: encrypted(t, key, "note"), ContentType: entity.Note}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, noteUUID).Return(&note, nil)
+
+	_, err := service.CreateRecord(ctx, handlers.CreateRecordRequest{TemplateUUID: noteUUID, Name: "prod"})
+	assert.EqualError(t, err, customerr.TEMPLATE_NOT_FOUND)
+}
+
+func TestTemplateService_UpdateRecord(t *testing.T) {
+	mockRepo := new(MockTemplateRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewTemplateService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	templateUUID := uuid.New().String()
+	recordUUID := uuid.New().String()
+	tmpl := entity.Data{
+		UUID:        templateUUID,
+		Content:     encrypted(t, key, templateContent{Name: "server", Fields: serverFields}),
+		ContentType: entity.Template,
+	}
+	record := entity.Data{
+		UUID: recordUUID,
+		Content: encrypted(t, key, recordContent{
+			TemplateUUID: templateUUID,
+			Name:         "prod",
+			Values:       map[string]interface{}{"host": "https://example.com", "port": float64(22)},
+		}),
+		ContentType: entity.Record,
+	}
+
+	var saved recordContent
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, recordUUID).Return(&record, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, templateUUID).Return(&tmpl, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("entity.Data")).Run(func(args mock.Arguments) {
+		data := args.Get(1).(entity.Data)
+		decrypted, err := lib.OpenEnvelope(key, data.DataKey, data.Content, data.AssociatedData())
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decrypted, &saved))
+	}).Return(nil)
+
+	_, err := service.UpdateRecord(ctx, handlers.UpdateRecordRequest{
+		UUID:   recordUUID,
+		Values: map[string]interface{}{"port": nil, "active": true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"host": "https://example.com", "active": true}, saved.Values)
+	assert.Equal(t, "prod", saved.Name)
+}
+
+func TestTemplateService_DeleteTemplateInUse(t *testing.T) {
+	mockRepo := new(MockTemplateRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewTemplateService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	usedUUID := uuid.New().String()
+	unusedUUID := uuid.New().String()
+	records := func() []*entity.Data {
+		return []*entity.Data{{
+			UUID:        uuid.New().String(),
+			Content:     encrypted(t, key, recordContent{TemplateUUID: usedUUID, Name: "prod"}),
+			ContentType: entity.Record,
+		}}
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, usedUUID).Return(&entity.Data{UUID: usedUUID, ContentType: entity.Template}, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, unusedUUID).Return(&entity.Data{UUID: unusedUUID, ContentType: entity.Template}, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Record).Return(records(), nil).Once()
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Record).Return(records(), nil).Once()
+	mockRepo.On("Delete", mock.Anything, user, unusedUUID).Return(nil)
+
+	_, err := service.DeleteTemplate(ctx, handlers.DeleteTemplateRequest{UUID: usedUUID})
+	assert.EqualError(t, err, customerr.TEMPLATE_IN_USE)
+
+	response, err := service.DeleteTemplate(ctx, handlers.DeleteTemplateRequest{UUID: unusedUUID})
+	require.NoError(t, err)
+	assert.Equal(t, unusedUUID, response.UUID)
+	mockRepo.AssertNumberOfCalls(t, "Delete", 1)
+}
+
+func TestTemplateService_DeleteWrongType(t *testing.T) {
+	mockRepo := new(MockTemplateRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewTemplateService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	templateUUID := uuid.New().String()
+	recordUUID := uuid.New().String()
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, templateUUID).Return(&entity.Data{UUID: templateUUID, ContentType: entity.Template}, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, recordUUID).Return(&entity.Data{UUID: recordUUID, ContentType: entity.Record}, nil)
+
+	// template used by records can not be deleted as record
+	_, err := service.DeleteRecord(ctx, handlers.DeleteRecordRequest{UUID: templateUUID})
+	assert.EqualError(t, err, customerr.INVALID_RECORD)
+
+	_, err = service.DeleteTemplate(ctx, handlers.DeleteTemplateRequest{UUID: recordUUID})
+	assert.EqualError(t, err, customerr.TEMPLATE_NOT_FOUND)
+
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTemplateService_GetAllRecords(t *testing.T) {
+	mockRepo := new(MockTemplateRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewTemplateService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	serverUUID := uuid.New().String()
+	otherUUID := uuid.New().String()
+	records := func() []*entity.Data {
+		return []*entity.Data{
+			{UUID: uuid.New().String(), Content: encrypted(t, key, recordContent{TemplateUUID: serverUUID, Name: "prod"}), ContentType: entity.Record},
+			{UUID: uuid.New().String(), Content: encrypted(t, key, recordContent{TemplateUUID: otherUUID, Name: "wifi"}), ContentType: entity.Record},
+		}
+	}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Record).Return(records(), nil).Once()
+	mockRepo.On("GetByUser", mock.Anything, user, entity.Record).Return(records(), nil).Once()
+
+	response, err := service.GetAllRecords(ctx, handlers.GetAllRecordsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, response.Items, 2)
+
+	response, err = service.GetAllRecords(ctx, handlers.GetAllRecordsRequest{TemplateUUID: serverUUID})
+	require.NoError(t, err)
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, "prod", response.Items[0].Name)
+}
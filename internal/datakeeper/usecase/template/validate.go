@@ -0,0 +1,120 @@
+package template
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+)
+
+// FieldKind template field kind
+type FieldKind string
+
+const (
+	Text    FieldKind = "text"
+	Hidden  FieldKind = "hidden"
+	URL     FieldKind = "url"
+	Date    FieldKind = "date"
+	Number  FieldKind = "number"
+	Boolean FieldKind = "boolean"
+)
+
+// dateLayout date field format
+const dateLayout = time.DateOnly
+
+func invalidTemplate(format string, args ...interface{}) error {
+	return customerr.ErrorWithCode(customerr.INVALID_TEMPLATE+": "+fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
+
+func invalidRecord(format string, args ...interface{}) error {
+	return customerr.ErrorWithCode(customerr.INVALID_RECORD+": "+fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
+
+// validateTemplate check template name and fields
+func validateTemplate(content templateContent) error {
+	if content.Name == "" {
+		return invalidTemplate("name is required")
+	}
+	if len(content.Fields) == 0 {
+		return invalidTemplate("at least one field is required")
+	}
+	names := make(map[string]struct{}, len(content.Fields))
+	for _, field := range content.Fields {
+		if field.Name == "" {
+			return invalidTemplate("field name is required")
+		}
+		if _, ok := names[field.Name]; ok {
+			return invalidTemplate("duplicate field %q", field.Name)
+		}
+		names[field.Name] = struct{}{}
+		switch FieldKind(field.Kind) {
+		case Text, Hidden, URL, Date, Number, Boolean:
+		default:
+			return invalidTemplate("unknown kind %q of field %q", field.Kind, field.Name)
+		}
+	}
+	return nil
+}
+
+// validateRecord check record values against template fields
+func validateRecord(fields []handlers.TemplateField, values map[string]interface{}) error {
+	known := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		known[field.Name] = struct{}{}
+		value, ok := values[field.Name]
+		if !ok || value == nil || value == "" {
+			if field.Required {
+				return invalidRecord("field %q is required", field.Name)
+			}
+			continue
+		}
+		if err := validateValue(FieldKind(field.Kind), value); err != nil {
+			return invalidRecord("field %q: %s", field.Name, err.Error())
+		}
+	}
+	for name := range values {
+		if _, ok := known[name]; !ok {
+			return invalidRecord("unknown field %q", name)
+		}
+	}
+	return nil
+}
+
+// validateValue check json value matches field kind
+func validateValue(kind FieldKind, value interface{}) error {
+	switch kind {
+	case Text, Hidden:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("must be a string")
+		}
+	case URL:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a string")
+		}
+		u, err := url.ParseRequestURI(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("must be an absolute url")
+		}
+	case Date:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("must be a string")
+		}
+		if _, err := time.Parse(dateLayout, s); err != nil {
+			return fmt.Errorf("must be a date in YYYY-MM-DD format")
+		}
+	case Number:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("must be a number")
+		}
+	case Boolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("must be a boolean")
+		}
+	}
+	return nil
+}
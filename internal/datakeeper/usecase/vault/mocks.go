@@ -0,0 +1,48 @@
+package vault
+
+import (
+	"context"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockKeyParamsRepo is a mock implementation of KeyParamsRepo
+type MockKeyParamsRepo struct {
+	mock.Mock
+}
+
+func (m *MockKeyParamsRepo) GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error) {
+	args := m.Called(ctx, login)
+	return args.Get(0).(*entity.KeyParams), args.Error(1)
+}
+
+// MockKeyService is a mock implementation of KeyService
+type MockKeyService struct {
+	mock.Mock
+}
+
+func (m *MockKeyService) SetKeyForUser(user string, key string) error {
+	args := m.Called(user, key)
+	return args.Error(0)
+}
+
+func (m *MockKeyService) UnlockKey(masterPassword string, params *entity.KeyParams) (string, error) {
+	args := m.Called(masterPassword, params)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockKeyService) Lock(user string) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// MockAuthService is a mock implementation of AuthService
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) GetUserFromContext(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,77 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+)
+
+type KeyParamsRepo interface {
+	GetByLogin(ctx context.Context, login string) (*entity.KeyParams, error)
+}
+
+type KeyService interface {
+	SetKeyForUser(user string, key string) error
+	UnlockKey(masterPassword string, params *entity.KeyParams) (string, error)
+	Lock(user string) error
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type Service struct {
+	keyParamsRepo KeyParamsRepo
+	keyService    KeyService
+	authService   AuthService
+}
+
+func NewVaultService(keyParamsRepo KeyParamsRepo, keyService KeyService, authService AuthService) *Service {
+	return &Service{keyParamsRepo: keyParamsRepo, keyService: keyService, authService: authService}
+}
+
+// Lock lock vault, data can not be read until vault is unlocked
+func (s *Service) Lock(ctx context.Context, r handlers.LockVaultRequest) (*handlers.LockVaultResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.keyService.Lock(user); err != nil {
+		return nil, err
+	}
+
+	return &handlers.LockVaultResponse{Locked: true}, nil
+}
+
+// Unlock unlock vault with master password, accounts registered before master password use key
+func (s *Service) Unlock(ctx context.Context, r handlers.UnlockVaultRequest) (*handlers.UnlockVaultResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := s.keyParamsRepo.GetByLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	key := r.Key
+	if params != nil {
+		key, err = s.keyService.UnlockKey(r.MasterPassword, params)
+		if err != nil {
+			return nil, err
+		}
+	} else if key == "" {
+		return nil, customerr.ErrorWithCode(customerr.KEY_REQUIRED, http.StatusBadRequest)
+	}
+
+	if err = s.keyService.SetKeyForUser(user, key); err != nil {
+		return nil, err
+	}
+
+	return &handlers.UnlockVaultResponse{Locked: false}, nil
+}
@@ -0,0 +1,74 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultService_Lock(t *testing.T) {
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewVaultService(new(MockKeyParamsRepo), mockKeyService, mockAuthService)
+
+	user := "test_user"
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("Lock", user).Return(nil)
+
+	response, err := service.Lock(context.Background(), handlers.LockVaultRequest{})
+
+	require.NoError(t, err)
+	assert.True(t, response.Locked)
+	mockKeyService.AssertExpectations(t)
+}
+
+func TestVaultService_Unlock(t *testing.T) {
+	mockKeyParamsRepo := new(MockKeyParamsRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewVaultService(mockKeyParamsRepo, mockKeyService, mockAuthService)
+
+	user := "test_user"
+	params := &entity.KeyParams{Login: user}
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyParamsRepo.On("GetByLogin", mock.Anything, user).Return(params, nil)
+	mockKeyService.On("UnlockKey", "master password", params).Return("derived_key", nil)
+	mockKeyService.On("UnlockKey", "wrong password", params).
+		Return("", customerr.ErrorWithCode(customerr.INVALID_MASTER_PASSWORD, http.StatusUnauthorized))
+	mockKeyService.On("SetKeyForUser", user, "derived_key").Return(nil)
+
+	response, err := service.Unlock(context.Background(), handlers.UnlockVaultRequest{MasterPassword: "master password"})
+	require.NoError(t, err)
+	assert.False(t, response.Locked)
+	mockKeyService.AssertCalled(t, "SetKeyForUser", user, "derived_key")
+
+	_, err = service.Unlock(context.Background(), handlers.UnlockVaultRequest{MasterPassword: "wrong password"})
+	assert.EqualError(t, err, customerr.INVALID_MASTER_PASSWORD)
+	mockKeyService.AssertNumberOfCalls(t, "SetKeyForUser", 1)
+}
+
+func TestVaultService_UnlockWithoutMasterPassword(t *testing.T) {
+	mockKeyParamsRepo := new(MockKeyParamsRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewVaultService(mockKeyParamsRepo, mockKeyService, mockAuthService)
+
+	user := "test_user"
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyParamsRepo.On("GetByLogin", mock.Anything, user).Return((*entity.KeyParams)(nil), nil)
+	mockKeyService.On("SetKeyForUser", user, "some_key").Return(nil)
+
+	_, err := service.Unlock(context.Background(), handlers.UnlockVaultRequest{Key: "some_key"})
+	require.NoError(t, err)
+	mockKeyService.AssertCalled(t, "SetKeyForUser", user, "some_key")
+
+	_, err = service.Unlock(context.Background(), handlers.UnlockVaultRequest{})
+	assert.EqualError(t, err, customerr.KEY_REQUIRED)
+}
@@ -0,0 +1,102 @@
+package wifi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+)
+
+// Security wifi security mode as used in WIFI: payload
+type Security string
+
+const (
+	WPA    Security = "WPA"
+	WEP    Security = "WEP"
+	NoPass Security = "nopass"
+)
+
+func invalidWiFi(format string, args ...interface{}) error {
+	return customerr.ErrorWithCode(customerr.INVALID_WIFI+": "+fmt.Sprintf(format, args...), http.StatusBadRequest)
+}
+
+// normalizeSecurity accept security mode in any case, WPA by default
+func normalizeSecurity(security string) Security {
+	switch strings.ToUpper(security) {
+	case "", "WPA", "WPA2", "WPA3":
+		return WPA
+	case "WEP":
+		return WEP
+	case "NOPASS", "NONE", "OPEN":
+		return NoPass
+	}
+	return Security(security)
+}
+
+// validateWiFi check ssid and passphrase length for security mode
+func validateWiFi(content wifiContent) error {
+	if len(content.SSID) == 0 || len(content.SSID) > 32 {
+		return invalidWiFi("ssid must be 1 to 32 bytes")
+	}
+	switch content.Security {
+	case WPA:
+		if len(content.Passphrase) == 64 && isHex(content.Passphrase) {
+			return nil
+		}
+		if len(content.Passphrase) < 8 || len(content.Passphrase) > 63 {
+			return invalidWiFi("wpa passphrase must be 8 to 63 characters")
+		}
+	case WEP:
+		switch {
+		case len(content.Passphrase) == 5, len(content.Passphrase) == 13:
+		case (len(content.Passphrase) == 10 || len(content.Passphrase) == 26) && isHex(content.Passphrase):
+		default:
+			return invalidWiFi("wep key must be 5 or 13 characters or 10 or 26 hex digits")
+		}
+	case NoPass:
+		if content.Passphrase != "" {
+			return invalidWiFi("open network must not have passphrase")
+		}
+	default:
+		return invalidWiFi("unknown security mode %q", content.Security)
+	}
+	return nil
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// payload build WIFI:T:..;S:..;P:..;; string understood by phone cameras
+func payload(content wifiContent) string {
+	var b strings.Builder
+	b.WriteString("WIFI:T:")
+	b.WriteString(string(content.Security))
+	b.WriteString(";S:")
+	b.WriteString(escape(content.SSID))
+	b.WriteString(";")
+	if content.Security != NoPass {
+		b.WriteString("P:")
+		b.WriteString(escape(content.Passphrase))
+		b.WriteString(";")
+	}
+	if content.Hidden {
+		b.WriteString("H:true;")
+	}
+	b.WriteString(";")
+	return b.String()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`)
+
+// escape special characters of WIFI: payload with backslash
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,273 @@
+package wifi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRSize = 256
+	minQRSize     = 128
+	maxQRSize     = 1024
+)
+
+type Repo interface {
+	Insert(ctx context.Context, data entity.Data) error
+	Update(ctx context.Context, data entity.Data) error
+	Delete(ctx context.Context, user string, uuid string) error
+	GetByUUID(ctx context.Context, user string, uuid string) (*entity.Data, error)
+	GetByUser(ctx context.Context, user string, contentType entity.ContentType) ([]*entity.Data, error)
+}
+
+type KeyService interface {
+	GetKeyForUser(user string) (string, error)
+}
+
+type AuthService interface {
+	GetUserFromContext(ctx context.Context) (string, error)
+}
+
+type wifiContent struct {
+	SSID       string   `json:"ssid"`
+	Security   Security `json:"security"`
+	Passphrase string   `json:"passphrase"`
+	Hidden     bool     `json:"hidden"`
+}
+
+type Service struct {
+	repo        Repo
+	keyService  KeyService
+	authService AuthService
+}
+
+func NewWiFiService(repo Repo, keyService KeyService, authService AuthService) *Service {
+	return &Service{repo: repo, keyService: keyService, authService: authService}
+}
+
+// Create wifi credentials
+func (s *Service) Create(ctx context.Context, r handlers.CreateWiFiRequest) (*handlers.CreateWiFiResponse, error) {
+	content := wifiContent{
+		SSID:       r.SSID,
+		Security:   normalizeSecurity(r.Security),
+		Passphrase: r.Passphrase,
+		Hidden:     r.Hidden,
+	}
+	if err := validateWiFi(content); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	newDataToSave := entity.Data{
+		UUID:        uuid.New().String(),
+		ContentType: entity.WiFi,
+		CreatedAt:   time.Now(),
+		CreatedBy:   user,
+	}
+
+	newDataToSave.Content, newDataToSave.DataKey, err = lib.SealEnvelope(key, jsonData, newDataToSave.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Insert(ctx, newDataToSave)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.CreateWiFiResponse{UUID: newDataToSave.UUID}, nil
+}
+
+// Update update wifi credentials
+func (s *Service) Update(ctx context.Context, r handlers.UpdateWiFiRequest) (*handlers.UpdateWiFiResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB, content, err := s.get(ctx, user, key, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.setContentToUpdate(r, content)
+	if err = validateWiFi(*content); err != nil {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDB.Content, fromDB.DataKey, err = lib.SealEnvelope(key, jsonData, fromDB.AssociatedData())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Update(ctx, *fromDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.UpdateWiFiResponse{UUID: fromDB.UUID}, nil
+}
+
+func (s *Service) setContentToUpdate(r handlers.UpdateWiFiRequest, content *wifiContent) {
+	if r.SSID != nil {
+		content.SSID = *r.SSID
+	}
+	if r.Security != nil {
+		content.Security = normalizeSecurity(*r.Security)
+	}
+	if r.Passphrase != nil {
+		content.Passphrase = *r.Passphrase
+	}
+	if r.Hidden != nil {
+		content.Hidden = *r.Hidden
+	}
+}
+
+// Delete delete wifi credentials
+func (s *Service) Delete(ctx context.Context, r handlers.DeleteWiFiRequest) (*handlers.DeleteWiFiResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, err = s.get(ctx, user, key, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Delete(ctx, user, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.DeleteWiFiResponse{UUID: r.UUID}, nil
+}
+
+// GetAll get all wifi networks without passphrases
+func (s *Service) GetAll(ctx context.Context, r handlers.GetAllWiFiRequest) (*handlers.GetAllWiFiResponse, error) {
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByUser(ctx, user, entity.WiFi)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]handlers.GetAllWiFiResponseItem, 0, len(data))
+	for _, v := range data {
+		jsonDecrypted, err := lib.OpenEnvelope(key, v.DataKey, v.Content, v.AssociatedData())
+		if err != nil {
+			return nil, err
+		}
+		item := handlers.GetAllWiFiResponseItem{}
+		err = json.Unmarshal(jsonDecrypted, &item)
+		if err != nil {
+			return nil, err
+		}
+		item.UUID = v.UUID
+		items = append(items, item)
+	}
+
+	return &handlers.GetAllWiFiResponse{Items: items}, nil
+}
+
+// GetQRCode render wifi credentials as PNG QR code
+func (s *Service) GetQRCode(ctx context.Context, r handlers.GetWiFiQRCodeRequest) (*handlers.GetWiFiQRCodeResponse, error) {
+	size := r.Size
+	if size == 0 {
+		size = defaultQRSize
+	}
+	if size < minQRSize || size > maxQRSize {
+		return nil, customerr.ErrorWithCode(customerr.INVALID_QR_SIZE, http.StatusBadRequest)
+	}
+
+	user, err := s.authService.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyService.GetKeyForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	_, content, err := s.get(ctx, user, key, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	png, err := qrcode.Encode(payload(*content), qrcode.Medium, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handlers.GetWiFiQRCodeResponse{PNG: png}, nil
+}
+
+// get load and decrypt wifi credentials
+func (s *Service) get(ctx context.Context, user string, key string, uuid string) (*entity.Data, *wifiContent, error) {
+	fromDB, err := s.repo.GetByUUID(ctx, user, uuid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromDB.ContentType != entity.WiFi {
+		return nil, nil, invalidWiFi("%s is not a wifi network", uuid)
+	}
+
+	jsonDecrypted, err := lib.OpenEnvelope(key, fromDB.DataKey, fromDB.Content, fromDB.AssociatedData())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content := wifiContent{}
+	err = json.Unmarshal(jsonDecrypted, &content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fromDB, &content, nil
+}
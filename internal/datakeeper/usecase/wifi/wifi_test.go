@@ -0,0 +1,142 @@
+package wifi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/png"
+	"testing"
+
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/entity"
+	customerr "github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/error"
+	"github.com/GusevGrishaEm1/data-keeper/internal/datakeeper/infrastructure/controller/http/handlers"
+	"github.com/GusevGrishaEm1/data-keeper/internal/lib"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		content wifiContent
+		want    string
+	}{
+		{
+			name:    "wpa",
+			content: wifiContent{SSID: "Office", Security: WPA, Passphrase: "correct horse"},
+			want:    "WIFI:T:WPA;S:Office;P:correct horse;;",
+		},
+		{
+			name:    "escaped",
+			content: wifiContent{SSID: `Guest;5G`, Security: WPA, Passphrase: `a:b,c"d\e`, Hidden: true},
+			want:    `WIFI:T:WPA;S:Guest\;5G;P:a\:b\,c\"d\\e;H:true;;`,
+		},
+		{
+			name:    "open",
+			content: wifiContent{SSID: "Lobby", Security: NoPass},
+			want:    "WIFI:T:nopass;S:Lobby;;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, payload(tt.content))
+		})
+	}
+}
+
+func TestValidateWiFi(t *testing.T) {
+	assert.NoError(t, validateWiFi(wifiContent{SSID: "Office", Security: WPA, Passphrase: "12345678"}))
+	assert.NoError(t, validateWiFi(wifiContent{SSID: "Legacy", Security: WEP, Passphrase: "0123456789"}))
+	assert.NoError(t, validateWiFi(wifiContent{SSID: "Lobby", Security: NoPass}))
+
+	assert.ErrorContains(t, validateWiFi(wifiContent{Security: WPA, Passphrase: "12345678"}), "ssid")
+	assert.ErrorContains(t, validateWiFi(wifiContent{SSID: "Office", Security: WPA, Passphrase: "short"}), "8 to 63")
+	assert.ErrorContains(t, validateWiFi(wifiContent{SSID: "Legacy", Security: WEP, Passphrase: "abcdef"}), "wep key")
+	assert.ErrorContains(t, validateWiFi(wifiContent{SSID: "Lobby", Security: NoPass, Passphrase: "x"}), "open network")
+	assert.ErrorContains(t, validateWiFi(wifiContent{SSID: "Lobby", Security: "WPA-EAP"}), customerr.INVALID_WIFI)
+}
+
+func TestWiFiService_Create(t *testing.T) {
+	mockRepo := new(MockWiFiRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewWiFiService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("entity.Data")).Return(nil)
+
+	response, err := service.Create(ctx, handlers.CreateWiFiRequest{SSID: "Office", Security: "wpa2", Passphrase: "correct horse"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.UUID)
+
+	_, err = service.Create(ctx, handlers.CreateWiFiRequest{SSID: "Office", Passphrase: "short"})
+	assert.ErrorContains(t, err, customerr.INVALID_WIFI)
+
+	mockRepo.AssertNumberOfCalls(t, "Insert", 1)
+}
+
+func TestWiFiService_GetQRCode(t *testing.T) {
+	mockRepo := new(MockWiFiRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewWiFiService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	jsonContent, _ := json.Marshal(&wifiContent{SSID: "Office", Security: WPA, Passphrase: "correct horse"})
+	encryptedContent, _ := lib.Encrypt(key, jsonContent)
+	data := entity.Data{UUID: uuidStr, Content: encryptedContent, ContentType: entity.WiFi}
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+
+	response, err := service.GetQRCode(ctx, handlers.GetWiFiQRCodeRequest{UUID: uuidStr})
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(response.PNG))
+	require.NoError(t, err)
+	assert.Equal(t, defaultQRSize, img.Bounds().Dx())
+
+	_, err = service.GetQRCode(ctx, handlers.GetWiFiQRCodeRequest{UUID: uuidStr, Size: 4096})
+	assert.EqualError(t, err, customerr.INVALID_QR_SIZE)
+}
+
+func TestWiFiService_Delete(t *testing.T) {
+	mockRepo := new(MockWiFiRepo)
+	mockKeyService := new(MockKeyService)
+	mockAuthService := new(MockAuthService)
+	service := NewWiFiService(mockRepo, mockKeyService, mockAuthService)
+
+	ctx := context.Background()
+	user := "test_user"
+	key := "1234567890123456"
+	uuidStr := uuid.New().String()
+	noteUUID := uuid.New().String()
+	data := entity.Data{UUID: uuidStr, ContentType: entity.WiFi, CreatedBy: user}
+	jsonContent, _ := json.Marshal(&wifiContent{SSID: "Office", Security: WPA, Passphrase: "correct horse"})
+	data.Content, data.DataKey, _ = lib.SealEnvelope(key, jsonContent, data.AssociatedData())
+
+	mockAuthService.On("GetUserFromContext", mock.Anything).Return(user, nil)
+	mockKeyService.On("GetKeyForUser", user).Return(key, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, uuidStr).Return(&data, nil)
+	mockRepo.On("GetByUUID", mock.Anything, user, noteUUID).Return(&entity.Data{UUID: noteUUID, ContentType: entity.Note}, nil)
+	mockRepo.On("Delete", mock.Anything, user, uuidStr).Return(nil)
+
+	response, err := service.Delete(ctx, handlers.DeleteWiFiRequest{UUID: uuidStr})
+	require.NoError(t, err)
+	assert.Equal(t, uuidStr, response.UUID)
+
+	_, err = service.Delete(ctx, handlers.DeleteWiFiRequest{UUID: noteUUID})
+	assert.ErrorContains(t, err, customerr.INVALID_WIFI)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, user, noteUUID)
+}
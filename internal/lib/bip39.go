@@ -0,0 +1,58 @@
+package lib
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//go:embed bip39_english.txt
+var bip39English string
+
+// bip39Words BIP-39 english wordlist index
+var bip39Words = func() map[string]int {
+	words := strings.Fields(bip39English)
+	index := make(map[string]int, len(words))
+	for i, w := range words {
+		index[w] = i
+	}
+	return index
+}()
+
+// ValidateMnemonic checks BIP-39 mnemonic length, words and checksum
+func ValidateMnemonic(words []string) error {
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return errors.New("mnemonic must have 12, 15, 18, 21 or 24 words")
+	}
+
+	// every word carries 11 bits, last len/3 bits are checksum
+	bits := make([]byte, 0, len(words)*11)
+	for i, w := range words {
+		index, ok := bip39Words[w]
+		if !ok {
+			return fmt.Errorf("word %d is not in BIP-39 wordlist", i+1)
+		}
+		for b := 10; b >= 0; b-- {
+			bits = append(bits, byte(index>>b)&1)
+		}
+	}
+
+	checksumBits := len(words) / 3
+	entropyBits := len(bits) - checksumBits
+	entropy := make([]byte, entropyBits/8)
+	for i := 0; i < entropyBits; i++ {
+		entropy[i/8] |= bits[i] << (7 - i%8)
+	}
+
+	sum := sha256.Sum256(entropy)
+	for i := 0; i < checksumBits; i++ {
+		if bits[entropyBits+i] != (sum[0]>>(7-i))&1 {
+			return errors.New("invalid mnemonic checksum")
+		}
+	}
+	return nil
+}
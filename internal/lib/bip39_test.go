@@ -0,0 +1,36 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMnemonic(t *testing.T) {
+	// test vectors from BIP-39 reference implementation
+	valid := []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent",
+		"gravity machine north sort system female filter attitude volume fold club stay feature office ecology stable narrow fog",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"scheme spot photo card baby mountain device kick cradle pact join borrow",
+		"void come effort suffer camp survey warrior heavy shoot primary clutch crush open amazing screen patrol group space point ten exist slush involve unfold",
+	}
+	for _, m := range valid {
+		assert.NoError(t, ValidateMnemonic(strings.Fields(m)), m)
+	}
+
+	assert.EqualError(t,
+		ValidateMnemonic(strings.Fields("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")),
+		"invalid mnemonic checksum")
+	assert.EqualError(t,
+		ValidateMnemonic(strings.Fields("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")),
+		"mnemonic must have 12, 15, 18, 21 or 24 words")
+	assert.EqualError(t,
+		ValidateMnemonic(strings.Fields("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandonn")),
+		"word 12 is not in BIP-39 wordlist")
+}
@@ -5,36 +5,235 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"sync/atomic"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Ciphertext format
+//
+//	v1:     "DKE" | suite (1) | nonce | AEAD ciphertext and tag
+//	bound:  "DKB" | suite (1) | nonce | AEAD ciphertext and tag
+//	legacy: iv (16) | AES-CBC ciphertext with PKCS#7 padding
+//
+// Header is authenticated as additional data, so suite can not be swapped.
+// Bound ciphertext also authenticates associated data of record it belongs to.
+// Legacy ciphertext starts with random iv and is told apart by missing magic
+// and known suite, chance of legacy row starting with valid header is 2^-30.
+const (
+	magic      = "DKE"
+	boundMagic = "DKB"
+	headerSize = len(magic) + 1
+)
+
+// Suite cipher suite, recorded in ciphertext header
+type Suite byte
+
+const (
+	// SuiteAES256GCM AES-256-GCM, default
+	SuiteAES256GCM Suite = 0x01
+	// SuiteXChaCha20Poly1305 XChaCha20-Poly1305, fast without AES hardware acceleration
+	SuiteXChaCha20Poly1305 Suite = 0x02
 )
 
-// Encrypt encrypts data using the provided key
+// suiteKeyInfo HKDF info of suite, separates suite key from user key used by legacy format
+var suiteKeyInfo = map[Suite][]byte{
+	SuiteAES256GCM:         []byte("data-keeper aes-256-gcm v1"),
+	SuiteXChaCha20Poly1305: []byte("data-keeper xchacha20-poly1305 v1"),
+}
+
+var suiteNames = map[Suite]string{
+	SuiteAES256GCM:         "aes-256-gcm",
+	SuiteXChaCha20Poly1305: "xchacha20-poly1305",
+}
+
+// ParseSuite suite by name
+func ParseSuite(name string) (Suite, error) {
+	for suite, suiteName := range suiteNames {
+		if suiteName == name {
+			return suite, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown cipher suite %q", name)
+}
+
+func (s Suite) String() string {
+	return suiteNames[s]
+}
+
+// defaultSuite suite of new ciphertext, zero is AES-256-GCM
+var defaultSuite atomic.Uint32
+
+// SetDefaultSuite suite used by Encrypt, existing ciphertext is decrypted with suite from its header
+func SetDefaultSuite(suite Suite) error {
+	if _, ok := suiteKeyInfo[suite]; !ok {
+		return fmt.Errorf("unknown cipher suite %d", suite)
+	}
+	defaultSuite.Store(uint32(suite))
+	return nil
+}
+
+// DefaultSuite suite used by Encrypt
+func DefaultSuite() Suite {
+	if suite := Suite(defaultSuite.Load()); suite != 0 {
+		return suite
+	}
+	return SuiteAES256GCM
+}
+
+// ErrIntegrity ciphertext was modified or encrypted with another key
+var ErrIntegrity = errors.New("ciphertext integrity check failed")
+
+// ErrUnbound ciphertext is not bound to associated data where binding is required, it is ErrIntegrity too
+var ErrUnbound = fmt.Errorf("%w: ciphertext is not bound to record", ErrIntegrity)
+
+// Encrypt encrypts data using the provided key with default suite
 func Encrypt(key string, data []byte) ([]byte, error) {
-	block, err := aes.NewCipher([]byte(key))
+	return EncryptWith(DefaultSuite(), key, data)
+}
+
+// EncryptWith encrypts data using the provided key and suite
+func EncryptWith(suite Suite, key string, data []byte) ([]byte, error) {
+	return seal(suite, magic, key, data, nil)
+}
+
+// EncryptBound encrypts data using the provided key with default suite and binds it to associated data,
+// ciphertext is decrypted only by DecryptBound with same associated data
+func EncryptBound(key string, data []byte, associatedData []byte) ([]byte, error) {
+	return seal(DefaultSuite(), boundMagic, key, data, associatedData)
+}
+
+func seal(suite Suite, m string, key string, data []byte, associatedData []byte) ([]byte, error) {
+	aead, err := newAEAD(suite, key)
 	if err != nil {
 		return nil, err
 	}
-	blockSize := block.BlockSize()
-	padding := blockSize - len(data)%blockSize
-	padtext := bytes.Repeat([]byte{byte(padding)}, padding)
-	data = append(data, padtext...)
 
-	ciphertext := make([]byte, blockSize+len(data))
-	iv := ciphertext[:blockSize]
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+	ciphertext := make([]byte, headerSize+aead.NonceSize(), headerSize+aead.NonceSize()+len(data)+aead.Overhead())
+	copy(ciphertext, m)
+	ciphertext[len(m)] = byte(suite)
+	nonce := ciphertext[headerSize:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
 
-	mode := cipher.NewCBCEncrypter(block, iv)
-	mode.CryptBlocks(ciphertext[blockSize:], data)
-
-	return ciphertext, nil
+	return aead.Seal(ciphertext, nonce, data, additionalData(ciphertext[:headerSize], associatedData)), nil
 }
 
-// Decrypt decrypts ciphertext using the provided key
+// Decrypt decrypts ciphertext using the provided key and suite from header, legacy CBC ciphertext is supported.
+// Bound ciphertext fails with ErrIntegrity
 func Decrypt(key string, ciphertext []byte) ([]byte, error) {
+	if IsLegacy(ciphertext) {
+		return decryptCBC(key, ciphertext)
+	}
+	return open(key, ciphertext, nil)
+}
+
+// DecryptBound decrypts ciphertext bound to associated data, ErrIntegrity if it was bound to another one.
+// Ciphertext encrypted without binding or in legacy format fails with ErrUnbound
+func DecryptBound(key string, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	if !IsBound(ciphertext) {
+		return nil, ErrUnbound
+	}
+	return open(key, ciphertext, associatedData)
+}
+
+// decryptCompat decrypts ciphertext written before binding as is, bound ciphertext is checked by DecryptBound.
+// Used only for rows which are not required to be bound until they are upgraded
+func decryptCompat(key string, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	if IsBound(ciphertext) {
+		return DecryptBound(key, ciphertext, associatedData)
+	}
+	return Decrypt(key, ciphertext)
+}
+
+// open decrypts AEAD ciphertext, associated data is authenticated only for bound ciphertext
+func open(key string, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	aead, err := newAEAD(Suite(ciphertext[len(magic)]), key)
+	if err != nil {
+		return nil, err
+	}
+	header := ciphertext[:headerSize]
+	body := ciphertext[headerSize:]
+	if len(body) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+	if !IsBound(ciphertext) {
+		associatedData = nil
+	}
+	plaintext, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], additionalData(header, associatedData))
+	if err != nil {
+		return nil, ErrIntegrity
+	}
+	return plaintext, nil
+}
+
+// IsLegacy ciphertext is in legacy CBC format and should be re-encrypted
+func IsLegacy(ciphertext []byte) bool {
+	if len(ciphertext) < headerSize {
+		return true
+	}
+	if m := string(ciphertext[:len(magic)]); m != magic && m != boundMagic {
+		return true
+	}
+	_, ok := suiteKeyInfo[Suite(ciphertext[len(magic)])]
+	return !ok
+}
+
+// IsBound ciphertext is bound to associated data
+func IsBound(ciphertext []byte) bool {
+	return !IsLegacy(ciphertext) && string(ciphertext[:len(boundMagic)]) == boundMagic
+}
+
+// AssociatedData encode fields unambiguously, every field is prefixed with its length
+func AssociatedData(fields ...string) []byte {
+	var buf []byte
+	for _, field := range fields {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(field)))
+		buf = append(buf, field...)
+	}
+	return buf
+}
+
+// additionalData header and associated data authenticated by AEAD
+func additionalData(header []byte, associatedData []byte) []byte {
+	return append(bytes.Clone(header), associatedData...)
+}
+
+// newAEAD create AEAD of suite with key derived from user key
+func newAEAD(suite Suite, key string) (cipher.AEAD, error) {
+	info, ok := suiteKeyInfo[suite]
+	if !ok {
+		return nil, fmt.Errorf("unknown cipher suite %d", suite)
+	}
+	return deriveAEAD(suite, key, nil, info)
+}
+
+// deriveAEAD create AEAD of suite with HKDF key derived from user key, salt and info
+func deriveAEAD(suite Suite, key string, salt []byte, info []byte) (cipher.AEAD, error) {
+	derived := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(key), salt, info), derived); err != nil {
+		return nil, err
+	}
+
+	if suite == SuiteXChaCha20Poly1305 {
+		return chacha20poly1305.NewX(derived)
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
 
+// decryptCBC decrypts legacy AES-CBC ciphertext, ciphertext is not modified
+func decryptCBC(key string, ciphertext []byte) ([]byte, error) {
 	block, err := aes.NewCipher([]byte(key))
 	if err != nil {
 		return nil, err
@@ -47,17 +246,18 @@ func Decrypt(key string, ciphertext []byte) ([]byte, error) {
 	iv := ciphertext[:aes.BlockSize]
 	ciphertext = ciphertext[aes.BlockSize:]
 
-	if len(ciphertext)%aes.BlockSize != 0 {
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
 		return nil, errors.New("ciphertext is not a multiple of the block size")
 	}
 
+	plaintext := make([]byte, len(ciphertext))
 	mode := cipher.NewCBCDecrypter(block, iv)
-	mode.CryptBlocks(ciphertext, ciphertext)
+	mode.CryptBlocks(plaintext, ciphertext)
 
-	padding := int(ciphertext[len(ciphertext)-1])
+	padding := int(plaintext[len(plaintext)-1])
 	if padding > aes.BlockSize || padding == 0 {
 		return nil, errors.New("invalid padding")
 	}
 
-	return ciphertext[:len(ciphertext)-padding], nil
+	return plaintext[:len(plaintext)-padding], nil
 }
@@ -1,9 +1,15 @@
 package lib
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCypher(t *testing.T) {
@@ -19,3 +25,176 @@ func TestCypher(t *testing.T) {
 	}
 	assert.Equal(t, data, decrypted)
 }
+
+// encryptCBC legacy format written before versioned header
+func encryptCBC(t *testing.T, key string, data []byte) []byte {
+	block, err := aes.NewCipher([]byte(key))
+	require.NoError(t, err)
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	data = append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	ciphertext := make([]byte, aes.BlockSize+len(data))
+	_, err = io.ReadFull(rand.Reader, ciphertext[:aes.BlockSize])
+	require.NoError(t, err)
+	cipher.NewCBCEncrypter(block, ciphertext[:aes.BlockSize]).CryptBlocks(ciphertext[aes.BlockSize:], data)
+	return ciphertext
+}
+
+func TestCypherFormat(t *testing.T) {
+	key := "abcdefghijklmnopqrstuvwxyzABCDEF"
+	data := []byte(`{"login":"user","password":"secret"}`)
+
+	encrypted, err := Encrypt(key, data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("DKE\x01"), encrypted[:headerSize])
+	assert.False(t, IsLegacy(encrypted))
+
+	legacy := encryptCBC(t, key, data)
+	assert.True(t, IsLegacy(legacy))
+	decrypted, err := Decrypt(key, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// decrypt must not modify stored ciphertext
+	again, err := Decrypt(key, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestCypherIntegrity(t *testing.T) {
+	key := "1234567890123456"
+	encrypted, err := Encrypt(key, []byte("test"))
+	require.NoError(t, err)
+
+	tampered := bytes.Clone(encrypted)
+	tampered[len(tampered)-1] ^= 0x01
+	_, err = Decrypt(key, tampered)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	_, err = Decrypt("6543210987654321", encrypted)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// unknown suite is read as legacy ciphertext and fails
+	unknown := bytes.Clone(encrypted)
+	unknown[len(magic)] = 0x7f
+	assert.True(t, IsLegacy(unknown))
+	_, err = Decrypt(key, unknown)
+	assert.Error(t, err)
+}
+
+func TestCypherBound(t *testing.T) {
+	key := "1234567890123456"
+	data := []byte("test")
+	aad := AssociatedData("uuid", "user", "NOTE")
+
+	encrypted, err := EncryptBound(key, data, aad)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("DKB"), encrypted[:len(boundMagic)])
+	assert.True(t, IsBound(encrypted))
+	assert.False(t, IsLegacy(encrypted))
+
+	decrypted, err := DecryptBound(key, encrypted, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// moved or relabeled record
+	_, err = DecryptBound(key, encrypted, AssociatedData("another-uuid", "user", "NOTE"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+	_, err = DecryptBound(key, encrypted, AssociatedData("uuid", "user", "CARD"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+	_, err = Decrypt(key, encrypted)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// binding can not be stripped by rewriting magic
+	unbound := bytes.Clone(encrypted)
+	copy(unbound, magic)
+	_, err = Decrypt(key, unbound)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// unbound ciphertext is rejected where binding is required
+	plain, err := Encrypt(key, data)
+	require.NoError(t, err)
+	assert.False(t, IsBound(plain))
+	_, err = DecryptBound(key, plain, aad)
+	assert.ErrorIs(t, err, ErrUnbound)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// rows written before binding are opened until they are upgraded
+	decrypted, err = decryptCompat(key, plain, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+	decrypted, err = decryptCompat(key, encrypted, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+	_, err = decryptCompat(key, encrypted, AssociatedData("another-uuid", "user", "NOTE"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
+
+func TestAssociatedData(t *testing.T) {
+	// fields are length prefixed, so shifting bytes between fields changes encoding
+	assert.NotEqual(t, AssociatedData("ab", "c"), AssociatedData("a", "bc"))
+	assert.NotEqual(t, AssociatedData("a", ""), AssociatedData("a"))
+	assert.Equal(t, []byte{0, 0, 0, 1, 'a', 0, 0, 0, 0}, AssociatedData("a", ""))
+}
+
+func TestCypherSuites(t *testing.T) {
+	key := "1234567890123456"
+	data := []byte("test")
+
+	for _, suite := range []Suite{SuiteAES256GCM, SuiteXChaCha20Poly1305} {
+		t.Run(suite.String(), func(t *testing.T) {
+			encrypted, err := EncryptWith(suite, key, data)
+			require.NoError(t, err)
+			assert.Equal(t, byte(suite), encrypted[len(magic)])
+			assert.False(t, IsLegacy(encrypted))
+
+			decrypted, err := Decrypt(key, encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, data, decrypted)
+
+			// suite is authenticated, relabeled ciphertext fails
+			relabeled := bytes.Clone(encrypted)
+			relabeled[len(magic)] = byte(SuiteAES256GCM + SuiteXChaCha20Poly1305 - suite)
+			_, err = Decrypt(key, relabeled)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDefaultSuite(t *testing.T) {
+	defer func() {
+		require.NoError(t, SetDefaultSuite(SuiteAES256GCM))
+	}()
+	key := "1234567890123456"
+
+	assert.Equal(t, SuiteAES256GCM, DefaultSuite())
+	aesEncrypted, err := Encrypt(key, []byte("aes"))
+	require.NoError(t, err)
+
+	require.NoError(t, SetDefaultSuite(SuiteXChaCha20Poly1305))
+	chachaEncrypted, err := Encrypt(key, []byte("chacha"))
+	require.NoError(t, err)
+	assert.Equal(t, byte(SuiteXChaCha20Poly1305), chachaEncrypted[len(magic)])
+
+	// mixed vault decrypts by suite from header
+	decrypted, err := Decrypt(key, aesEncrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "aes", string(decrypted))
+	decrypted, err = Decrypt(key, chachaEncrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "chacha", string(decrypted))
+
+	assert.Error(t, SetDefaultSuite(0x7f))
+}
+
+func TestParseSuite(t *testing.T) {
+	suite, err := ParseSuite("aes-256-gcm")
+	require.NoError(t, err)
+	assert.Equal(t, SuiteAES256GCM, suite)
+
+	suite, err = ParseSuite("xchacha20-poly1305")
+	require.NoError(t, err)
+	assert.Equal(t, SuiteXChaCha20Poly1305, suite)
+
+	_, err = ParseSuite("aes-128-cbc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package lib
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// dataKeySize size of random per-record data encryption key
+const dataKeySize = 32
+
+// SealEnvelope encrypts data with new random data key and wraps data key with master key,
+// wrapped key must be stored next to ciphertext. Both are bound to associated data of record
+func SealEnvelope(masterKey string, data []byte, associatedData []byte) (ciphertext []byte, wrappedKey []byte, err error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err = EncryptBound(string(dataKey), data, associatedData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey, err = EncryptBound(masterKey, dataKey, associatedData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ciphertext, wrappedKey, nil
+}
+
+// OpenEnvelope decrypts ciphertext with data key unwrapped by master key,
+// ciphertext without wrapped key is encrypted with master key directly.
+// Ciphertext of envelope with bound data key must be bound too, ErrUnbound otherwise.
+// Rows written before binding are opened without it until they are upgraded.
+// ErrIntegrity if envelope was bound to another record
+func OpenEnvelope(masterKey string, wrappedKey []byte, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	if len(wrappedKey) == 0 {
+		return decryptCompat(masterKey, ciphertext, associatedData)
+	}
+
+	dataKey, err := UnwrapKey(masterKey, wrappedKey, associatedData)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsBound(wrappedKey) {
+		return DecryptBound(string(dataKey), ciphertext, associatedData)
+	}
+	return decryptCompat(string(dataKey), ciphertext, associatedData)
+}
+
+// UnwrapKey data key wrapped by master key, key wrapped before binding is unwrapped as is
+func UnwrapKey(masterKey string, wrappedKey []byte, associatedData []byte) ([]byte, error) {
+	return decryptCompat(masterKey, wrappedKey, associatedData)
+}
+
+// RewrapKey wraps data key with new master key, ciphertext encrypted with data key stays untouched.
+// Data key is always bound by re-wrap, so key wrapped before binding must be re-wrapped
+// only if ciphertext is bound or is stream ciphertext
+func RewrapKey(oldMasterKey string, newMasterKey string, wrappedKey []byte, associatedData []byte) ([]byte, error) {
+	dataKey, err := UnwrapKey(oldMasterKey, wrappedKey, associatedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return EncryptBound(newMasterKey, dataKey, associatedData)
+}
+
+// SealEnvelopeStream writer encrypting data to w with new random data key and data key wrapped with master key,
+// writer must be closed to write final chunk, wrapped key must be stored next to ciphertext.
+// Wrapped key is bound to associated data of record, data key is never reused for another stream
+func SealEnvelopeStream(masterKey string, w io.Writer, associatedData []byte) (io.WriteCloser, []byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	wrappedKey, err := EncryptBound(masterKey, dataKey, associatedData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	writer, err := NewEncryptWriter(string(dataKey), w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return writer, wrappedKey, nil
+}
+
+// OpenEnvelopeStream reader decrypting stream ciphertext from r with data key unwrapped by master key,
+// wrong master key or another record is reported before anything is read from r
+func OpenEnvelopeStream(masterKey string, wrappedKey []byte, r io.Reader, associatedData []byte) (io.Reader, error) {
+	dataKey, err := UnwrapKey(masterKey, wrappedKey, associatedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDecryptReader(string(dataKey), r)
+}
@@ -0,0 +1,126 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	masterKey := "1234567890123456"
+	data := []byte("test")
+
+	aad := AssociatedData("uuid", "user", "NOTE")
+
+	ciphertext, wrappedKey, err := SealEnvelope(masterKey, data, aad)
+	require.NoError(t, err)
+	assert.NotEmpty(t, wrappedKey)
+
+	// ciphertext is not encrypted with master key
+	_, err = Decrypt(masterKey, ciphertext)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	decrypted, err := OpenEnvelope(masterKey, wrappedKey, ciphertext, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, err = OpenEnvelope("6543210987654321", wrappedKey, ciphertext, aad)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// envelope moved to another record
+	_, err = OpenEnvelope(masterKey, wrappedKey, ciphertext, AssociatedData("uuid", "another-user", "NOTE"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+	_, err = OpenEnvelope(masterKey, wrappedKey, ciphertext, nil)
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
+
+func TestEnvelopeWithoutDataKey(t *testing.T) {
+	masterKey := "1234567890123456"
+	data := []byte("test")
+
+	ciphertext, err := Encrypt(masterKey, data)
+	require.NoError(t, err)
+
+	// ciphertext encrypted without binding opens with any associated data
+	decrypted, err := OpenEnvelope(masterKey, nil, ciphertext, AssociatedData("uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// bound ciphertext is checked
+	bound, err := EncryptBound(masterKey, data, AssociatedData("uuid"))
+	require.NoError(t, err)
+	decrypted, err = OpenEnvelope(masterKey, nil, bound, AssociatedData("uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+	_, err = OpenEnvelope(masterKey, nil, bound, AssociatedData("another-uuid"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
+
+func TestEnvelopeUnboundCiphertext(t *testing.T) {
+	masterKey := "1234567890123456"
+	dataKey := "12345678901234567890123456789012"
+	data := []byte("test")
+	aad := AssociatedData("uuid", "user", "NOTE")
+
+	ciphertext, err := Encrypt(dataKey, data)
+	require.NoError(t, err)
+
+	// envelope written before binding
+	wrappedKey, err := Encrypt(masterKey, []byte(dataKey))
+	require.NoError(t, err)
+	decrypted, err := OpenEnvelope(masterKey, wrappedKey, ciphertext, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// unbound ciphertext can not be put under bound data key
+	wrappedKey, err = EncryptBound(masterKey, []byte(dataKey), aad)
+	require.NoError(t, err)
+	_, err = OpenEnvelope(masterKey, wrappedKey, ciphertext, aad)
+	assert.ErrorIs(t, err, ErrUnbound)
+}
+
+func TestRewrapKey(t *testing.T) {
+	oldMasterKey := "1234567890123456"
+	newMasterKey := "6543210987654321"
+	data := []byte("test")
+
+	aad := AssociatedData("uuid", "user", "NOTE")
+
+	ciphertext, wrappedKey, err := SealEnvelope(oldMasterKey, data, aad)
+	require.NoError(t, err)
+
+	rewrapped, err := RewrapKey(oldMasterKey, newMasterKey, wrappedKey, aad)
+	require.NoError(t, err)
+	assert.True(t, IsBound(rewrapped))
+
+	decrypted, err := OpenEnvelope(newMasterKey, rewrapped, ciphertext, aad)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, err = OpenEnvelope(oldMasterKey, rewrapped, ciphertext, aad)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	_, err = RewrapKey(oldMasterKey, newMasterKey, wrappedKey, AssociatedData("another-uuid", "user", "NOTE"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
+
+func TestRewrapUnboundKey(t *testing.T) {
+	oldMasterKey := "1234567890123456"
+	newMasterKey := "6543210987654321"
+	dataKey := []byte("12345678901234567890123456789012")
+
+	wrappedKey, err := Encrypt(oldMasterKey, dataKey)
+	require.NoError(t, err)
+
+	// key wrapped before binding is bound by re-wrap
+	rewrapped, err := RewrapKey(oldMasterKey, newMasterKey, wrappedKey, AssociatedData("uuid"))
+	require.NoError(t, err)
+	assert.True(t, IsBound(rewrapped))
+
+	unwrapped, err := UnwrapKey(newMasterKey, rewrapped, AssociatedData("uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+	_, err = UnwrapKey(newMasterKey, rewrapped, AssociatedData("another-uuid"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+}
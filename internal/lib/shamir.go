@@ -0,0 +1,113 @@
+package lib
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// Shamir secret sharing over GF(2^8), every byte of secret is shared by own random polynomial of degree threshold-1.
+//
+//	share: x (1) | y (len of secret)
+//
+// Any threshold shares rebuild secret, fewer shares reveal nothing about it.
+// Field arithmetic does not use lookup tables, so it does not leak secret through cache timing.
+const maxShares = 255
+
+// SplitSecret split secret into n shares, any k of them rebuild secret
+func SplitSecret(secret []byte, n int, k int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret is empty")
+	}
+	if k < 2 || k > n || n > maxShares {
+		return nil, errors.New("threshold must be between 2 and number of shares, at most 255 shares")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, k)
+	for j, b := range secret {
+		coefficients[0] = b
+		if _, err := io.ReadFull(rand.Reader, coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			share[j+1] = evaluate(coefficients, share[0])
+		}
+	}
+	clear(coefficients)
+
+	return shares, nil
+}
+
+// CombineShares rebuild secret from shares, all shares are used.
+// Wrong shares rebuild wrong secret, it must be verified by caller
+func CombineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least 2 shares are required")
+	}
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("share is too short")
+	}
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("shares have different length")
+		}
+		if share[0] == 0 || seen[share[0]] {
+			return nil, errors.New("shares have invalid or repeated index")
+		}
+		seen[share[0]] = true
+	}
+
+	// Lagrange interpolation at x = 0
+	secret := make([]byte, size-1)
+	for i, share := range shares {
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				// subtraction is xor in GF(2^8)
+				basis = gfMul(basis, gfMul(other[0], gfInv(other[0]^share[0])))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(share[b+1], basis)
+		}
+	}
+
+	return secret, nil
+}
+
+// evaluate polynomial with coefficients at x by Horner's method
+func evaluate(coefficients []byte, x byte) byte {
+	result := byte(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+	return result
+}
+
+// gfMul multiply in GF(2^8) with AES polynomial x^8 + x^4 + x^3 + x + 1 in constant time
+func gfMul(a byte, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv multiplicative inverse in GF(2^8), a^254 = a^-1
+func gfInv(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 254; i++ {
+		result = gfMul(result, a)
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShamir(t *testing.T) {
+	secret := []byte("abcdefghijklmnopqrstuvwxyzABCDEF0123456789abcdefghijklmnopqrstuv")
+
+	shares, err := SplitSecret(secret, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+	for i, share := range shares {
+		assert.Equal(t, byte(i+1), share[0])
+		assert.Len(t, share, len(secret)+1)
+	}
+
+	// any threshold shares rebuild secret
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var picked [][]byte
+		for _, i := range subset {
+			picked = append(picked, shares[i])
+		}
+		combined, err := CombineShares(picked)
+		require.NoError(t, err)
+		assert.Equal(t, secret, combined, "shares %v", subset)
+	}
+
+	// fewer shares rebuild something else
+	combined, err := CombineShares(shares[:2])
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, combined)
+}
+
+func TestShamirInvalid(t *testing.T) {
+	_, err := SplitSecret(nil, 3, 2)
+	assert.Error(t, err)
+	_, err = SplitSecret([]byte("secret"), 3, 1)
+	assert.Error(t, err)
+	_, err = SplitSecret([]byte("secret"), 2, 3)
+	assert.Error(t, err)
+	_, err = SplitSecret([]byte("secret"), 256, 2)
+	assert.Error(t, err)
+
+	shares, err := SplitSecret([]byte("secret"), 3, 2)
+	require.NoError(t, err)
+
+	_, err = CombineShares(shares[:1])
+	assert.Error(t, err)
+	_, err = CombineShares([][]byte{shares[0], shares[0]})
+	assert.Error(t, err)
+	_, err = CombineShares([][]byte{shares[0], shares[1][:3]})
+	assert.Error(t, err)
+}
+
+func TestGF(t *testing.T) {
+	// known product from FIPS-197
+	assert.Equal(t, byte(0xc1), gfMul(0x57, 0x83))
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), gfMul(byte(a), gfInv(byte(a))), "a %d", a)
+	}
+}
@@ -0,0 +1,229 @@
+package lib
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Stream ciphertext format
+//
+//	"DKS" | suite (1) | salt (32) | chunk | ... | final chunk
+//
+// Every chunk holds StreamChunkSize bytes of plaintext, final chunk holds the rest and may be empty.
+// Chunk key is derived from key and random salt, so nonce is never reused with same key.
+// Nonce is zero padded chunk counter (4) | final flag (1), so chunks can not be reordered,
+// dropped or appended after final chunk (STREAM construction).
+const (
+	streamMagic      = "DKS"
+	streamSaltSize   = 32
+	streamHeaderSize = len(streamMagic) + 1 + streamSaltSize
+	// StreamChunkSize size of plaintext chunk, memory used by stream does not depend on data size
+	StreamChunkSize = 64 * 1024
+)
+
+// streamKeyInfo HKDF info of suite for stream key, separates stream key from key of Encrypt
+var streamKeyInfo = map[Suite][]byte{
+	SuiteAES256GCM:         []byte("data-keeper aes-256-gcm stream v1"),
+	SuiteXChaCha20Poly1305: []byte("data-keeper xchacha20-poly1305 stream v1"),
+}
+
+// NewEncryptWriter writer encrypting data to w with key and default suite,
+// writer must be closed to write final chunk, w is not closed
+func NewEncryptWriter(key string, w io.Writer) (io.WriteCloser, error) {
+	return NewEncryptWriterWith(DefaultSuite(), key, w)
+}
+
+// NewEncryptWriterWith writer encrypting data to w with key and suite
+func NewEncryptWriterWith(suite Suite, key string, w io.Writer) (io.WriteCloser, error) {
+	info, ok := streamKeyInfo[suite]
+	if !ok {
+		return nil, fmt.Errorf("unknown cipher suite %d", suite)
+	}
+
+	header := make([]byte, streamHeaderSize)
+	copy(header, streamMagic)
+	header[len(streamMagic)] = byte(suite)
+	salt := header[len(streamMagic)+1:]
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := deriveAEAD(suite, key, salt, info)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		aead:  aead,
+		w:     w,
+		nonce: make([]byte, aead.NonceSize()),
+		buf:   make([]byte, 0, StreamChunkSize),
+		out:   make([]byte, 0, StreamChunkSize+aead.Overhead()),
+	}, nil
+}
+
+type encryptWriter struct {
+	aead    cipher.AEAD
+	w       io.Writer
+	nonce   []byte
+	buf     []byte
+	out     []byte
+	counter uint32
+	closed  bool
+}
+
+// Write buffers data, full chunk is sealed only when more data comes, so last chunk is sealed as final by Close
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed stream")
+	}
+
+	n := 0
+	for len(p) > 0 {
+		if len(e.buf) == StreamChunkSize {
+			if err := e.seal(false); err != nil {
+				return n, err
+			}
+		}
+		m := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// Close seal final chunk
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(final bool) error {
+	if err := streamNonce(e.nonce, e.counter, final); err != nil {
+		return err
+	}
+	e.out = e.aead.Seal(e.out[:0], e.nonce, e.buf, nil)
+	if _, err := e.w.Write(e.out); err != nil {
+		return err
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// NewDecryptReader reader decrypting stream ciphertext from r with key and suite from header,
+// reader returns ErrIntegrity if any chunk was modified, reordered or stream was truncated
+func NewDecryptReader(key string, r io.Reader) (io.Reader, error) {
+	header := make([]byte, streamHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrIntegrity
+	}
+	if string(header[:len(streamMagic)]) != streamMagic {
+		return nil, errors.New("not a stream ciphertext")
+	}
+	suite := Suite(header[len(streamMagic)])
+	info, ok := streamKeyInfo[suite]
+	if !ok {
+		return nil, fmt.Errorf("unknown cipher suite %d", suite)
+	}
+
+	aead, err := deriveAEAD(suite, key, header[len(streamMagic)+1:], info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		aead:  aead,
+		r:     bufio.NewReader(r),
+		nonce: make([]byte, aead.NonceSize()),
+		in:    make([]byte, StreamChunkSize+aead.Overhead()),
+		out:   make([]byte, 0, StreamChunkSize),
+	}, nil
+}
+
+type decryptReader struct {
+	aead    cipher.AEAD
+	r       *bufio.Reader
+	nonce   []byte
+	in      []byte
+	out     []byte
+	plain   []byte
+	counter uint32
+	final   bool
+	err     error
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.final {
+			return 0, io.EOF
+		}
+		d.err = d.open()
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// open next chunk, chunk is final if it is short or nothing follows it
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.in)
+	final := false
+	switch {
+	case errors.Is(err, io.EOF):
+		// stream ended without final chunk
+		return ErrIntegrity
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		final = true
+	case err != nil:
+		return err
+	default:
+		if _, err = d.r.Peek(1); errors.Is(err, io.EOF) {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if err = streamNonce(d.nonce, d.counter, final); err != nil {
+		return err
+	}
+	d.out, err = d.aead.Open(d.out[:0], d.nonce, d.in[:n], nil)
+	if err != nil {
+		return ErrIntegrity
+	}
+	d.plain = d.out
+	d.counter++
+	d.final = final
+	return nil
+}
+
+// streamNonce fill nonce with chunk counter and final flag
+func streamNonce(nonce []byte, counter uint32, final bool) error {
+	if counter == ^uint32(0) {
+		return errors.New("stream is too long")
+	}
+	clear(nonce)
+	binary.BigEndian.PutUint32(nonce[len(nonce)-5:], counter)
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package lib
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encryptStream(t *testing.T, suite Suite, key string, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewEncryptWriterWith(suite, key, &buf)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func decryptStream(key string, ciphertext []byte) ([]byte, error) {
+	r, err := NewDecryptReader(key, bytes.NewReader(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestStream(t *testing.T) {
+	key := "1234567890123456"
+	sizes := []int{0, 1, StreamChunkSize - 1, StreamChunkSize, StreamChunkSize + 1, 3*StreamChunkSize + 123}
+
+	for _, suite := range []Suite{SuiteAES256GCM, SuiteXChaCha20Poly1305} {
+		for _, size := range sizes {
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			require.NoError(t, err)
+
+			ciphertext := encryptStream(t, suite, key, data)
+			chunks := max(1, (size+StreamChunkSize-1)/StreamChunkSize)
+			assert.Len(t, ciphertext, streamHeaderSize+size+chunks*16, "suite %s size %d", suite, size)
+
+			decrypted, err := decryptStream(key, ciphertext)
+			require.NoError(t, err, "suite %s size %d", suite, size)
+			assert.True(t, bytes.Equal(data, decrypted), "suite %s size %d", suite, size)
+		}
+	}
+}
+
+func TestStreamSmallWrites(t *testing.T) {
+	key := "1234567890123456"
+	data := bytes.Repeat([]byte("0123456789"), StreamChunkSize/5)
+
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(key, &buf)
+	require.NoError(t, err)
+	for i := 0; i < len(data); i += 7 {
+		_, err = w.Write(data[i:min(i+7, len(data))])
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	r, err := NewDecryptReader(key, &buf)
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(iotest.OneByteReader(r))
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+}
+
+func TestStreamIntegrity(t *testing.T) {
+	key := "1234567890123456"
+	data := make([]byte, 2*StreamChunkSize+10)
+	ciphertext := encryptStream(t, SuiteAES256GCM, key, data)
+	chunk := StreamChunkSize + 16
+
+	// wrong key
+	_, err := decryptStream("6543210987654321", ciphertext)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// modified chunk
+	modified := bytes.Clone(ciphertext)
+	modified[streamHeaderSize+chunk+5] ^= 0x01
+	_, err = decryptStream(key, modified)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// final chunk dropped
+	_, err = decryptStream(key, ciphertext[:streamHeaderSize+2*chunk])
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// truncated inside chunk
+	_, err = decryptStream(key, ciphertext[:len(ciphertext)-1])
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// chunks swapped
+	swapped := bytes.Clone(ciphertext[:streamHeaderSize])
+	swapped = append(swapped, ciphertext[streamHeaderSize+chunk:streamHeaderSize+2*chunk]...)
+	swapped = append(swapped, ciphertext[streamHeaderSize:streamHeaderSize+chunk]...)
+	swapped = append(swapped, ciphertext[streamHeaderSize+2*chunk:]...)
+	_, err = decryptStream(key, swapped)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// data appended after final chunk
+	_, err = decryptStream(key, append(bytes.Clone(ciphertext), 0x00))
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// not a stream
+	encrypted, err := Encrypt(key, data)
+	require.NoError(t, err)
+	_, err = decryptStream(key, encrypted)
+	assert.Error(t, err)
+}
+
+func TestEnvelopeStream(t *testing.T) {
+	masterKey := "1234567890123456"
+	data := make([]byte, StreamChunkSize+1)
+	aad := AssociatedData("uuid", "user", "FILE_CONTENT")
+
+	var buf bytes.Buffer
+	w, wrappedKey, err := SealEnvelopeStream(masterKey, &buf, aad)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = OpenEnvelopeStream("6543210987654321", wrappedKey, bytes.NewReader(buf.Bytes()), aad)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// stream moved to another record
+	_, err = OpenEnvelopeStream(masterKey, wrappedKey, bytes.NewReader(buf.Bytes()), AssociatedData("uuid", "another-user", "FILE_CONTENT"))
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	r, err := OpenEnvelopeStream(masterKey, wrappedKey, bytes.NewReader(buf.Bytes()), aad)
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	// data key is still re-wrapped without touching stream
+	rewrapped, err := RewrapKey(masterKey, "6543210987654321", wrappedKey, aad)
+	require.NoError(t, err)
+	r, err = OpenEnvelopeStream("6543210987654321", rewrapped, bytes.NewReader(buf.Bytes()), aad)
+	require.NoError(t, err)
+	decrypted, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+}
@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"time"
+)
+
+// HOTP generates HMAC-based one-time password (RFC 4226)
+func HOTP(secret []byte, algorithm string, digits int, counter uint64) (string, error) {
+	var h func() hash.Hash
+	switch algorithm {
+	case "SHA1":
+		h = sha1.New
+	case "SHA256":
+		h = sha256.New
+	case "SHA512":
+		h = sha512.New
+	default:
+		return "", errors.New("unsupported algorithm")
+	}
+	if digits < 6 || digits > 8 {
+		return "", errors.New("digits must be between 6 and 8")
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+	mac := hmac.New(h, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%mod), nil
+}
+
+// TOTP generates time-based one-time password (RFC 6238)
+func TOTP(secret []byte, algorithm string, digits int, period int, t time.Time) (string, error) {
+	if period <= 0 {
+		return "", errors.New("period must be positive")
+	}
+	return HOTP(secret, algorithm, digits, uint64(t.Unix())/uint64(period))
+}
@@ -0,0 +1,55 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 Appendix B test vectors
+func TestTOTP(t *testing.T) {
+	seeds := map[string][]byte{
+		"SHA1":   []byte("12345678901234567890"),
+		"SHA256": []byte("12345678901234567890123456789012"),
+		"SHA512": []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	tests := []struct {
+		time      int64
+		algorithm string
+		code      string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111109, "SHA256", "68084774"},
+		{1111111109, "SHA512", "25091201"},
+		{1111111111, "SHA1", "14050471"},
+		{1111111111, "SHA256", "67062674"},
+		{1111111111, "SHA512", "99943326"},
+		{1234567890, "SHA1", "89005924"},
+		{1234567890, "SHA256", "91819424"},
+		{1234567890, "SHA512", "93441116"},
+		{2000000000, "SHA1", "69279037"},
+		{2000000000, "SHA256", "90698825"},
+		{2000000000, "SHA512", "38618901"},
+		{20000000000, "SHA1", "65353130"},
+		{20000000000, "SHA256", "77737706"},
+		{20000000000, "SHA512", "47863826"},
+	}
+	for _, tt := range tests {
+		code, err := TOTP(seeds[tt.algorithm], tt.algorithm, 8, 30, time.Unix(tt.time, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.code, code, "time %d algorithm %s", tt.time, tt.algorithm)
+	}
+}
+
+func TestTOTP_Invalid(t *testing.T) {
+	_, err := TOTP([]byte("secret"), "MD5", 6, 30, time.Now())
+	assert.Error(t, err)
+	_, err = TOTP([]byte("secret"), "SHA1", 10, 30, time.Now())
+	assert.Error(t, err)
+	_, err = TOTP([]byte("secret"), "SHA1", 6, 0, time.Now())
+	assert.Error(t, err)
+}
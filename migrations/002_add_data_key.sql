@@ -0,0 +1,8 @@
+-- +goose Up
+alter table user_data add column if not exists data_key bytea;
+
+alter table file_repository add column if not exists data_key bytea;
+
+-- +goose Down
+ALTER TABLE file_repository DROP COLUMN IF EXISTS data_key;
+ALTER TABLE user_data DROP COLUMN IF EXISTS data_key;
@@ -0,0 +1,13 @@
+-- +goose Up
+create table if not exists user_key (
+    login varchar(255) primary key,
+    salt bytea not null,
+    time integer not null,
+    memory integer not null,
+    threads smallint not null,
+    check_value bytea not null,
+    created_at timestamp not null
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_key;
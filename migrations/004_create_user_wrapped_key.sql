@@ -0,0 +1,9 @@
+-- +goose Up
+create table if not exists user_wrapped_key (
+    login varchar(255) primary key,
+    wrapped_key bytea not null,
+    updated_at timestamp not null
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_wrapped_key;
@@ -0,0 +1,13 @@
+-- +goose Up
+alter table file_repository add column if not exists chunked boolean not null default false;
+
+create table if not exists file_chunk (
+    uuid uuid not null references file_repository (uuid) on delete cascade,
+    seq integer not null,
+    content bytea not null,
+    primary key (uuid, seq)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS file_chunk;
+ALTER TABLE file_repository DROP COLUMN IF EXISTS chunked;
@@ -0,0 +1,5 @@
+-- +goose Up
+alter table user_data add column if not exists metadata bytea;
+
+-- +goose Down
+ALTER TABLE user_data DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,12 @@
+-- +goose Up
+create table if not exists recovery_kit (
+    login varchar(255) primary key,
+    kit_id bytea not null,
+    threshold smallint not null,
+    shares smallint not null,
+    check_value bytea not null,
+    created_at timestamp not null
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS recovery_kit;
@@ -0,0 +1,22 @@
+-- +goose Up
+create table if not exists token_family (
+    id varchar(36) primary key,
+    login varchar(255) not null,
+    created_at timestamp not null,
+    revoked_at timestamp
+);
+
+create table if not exists refresh_token (
+    hash bytea primary key,
+    family_id varchar(36) not null references token_family (id) on delete cascade,
+    login varchar(255) not null,
+    expires_at timestamp not null,
+    created_at timestamp not null,
+    used_at timestamp
+);
+
+create index if not exists refresh_token_family_id_idx on refresh_token (family_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS refresh_token;
+DROP TABLE IF EXISTS token_family;
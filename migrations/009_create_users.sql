@@ -0,0 +1,9 @@
+-- +goose Up
+create table if not exists users (
+    login varchar(255) primary key,
+    password_hash varchar(255) not null,
+    created_at timestamp not null
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,133 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// blobsPath path of blob endpoints
+const blobsPath = "/api/blobs"
+
+// Client of blob endpoints, payload is encrypted before it is sent and decrypted after it is received
+type Client struct {
+	baseURL    string
+	token      string
+	key        []byte
+	httpClient *http.Client
+}
+
+// Item decrypted blob
+type Item struct {
+	UUID      string
+	Metadata  json.RawMessage
+	Plaintext []byte
+}
+
+// NewClient create client of server at baseURL, token is JWT returned by login, it is sent as Bearer token
+func NewClient(baseURL string, token string, key []byte) (*Client, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes", KeySize)
+	}
+	return &Client{baseURL: baseURL, token: token, key: key, httpClient: http.DefaultClient}, nil
+}
+
+type blobRequest struct {
+	UUID     string          `json:"uuid"`
+	Content  []byte          `json:"content,omitempty"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+type blobsResponse struct {
+	Items []blobRequest `json:"items"`
+}
+
+// Create encrypt and store plaintext, uuid is chosen by client to bind ciphertext to it
+func (c *Client) Create(ctx context.Context, metadata json.RawMessage, plaintext []byte) (string, error) {
+	id := uuid.New().String()
+	content, err := Seal(c.key, id, plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.do(ctx, http.MethodPost, blobRequest{UUID: id, Content: content, Metadata: metadata}, nil)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Update encrypt and replace plaintext and metadata of blob
+func (c *Client) Update(ctx context.Context, id string, metadata json.RawMessage, plaintext []byte) error {
+	content, err := Seal(c.key, id, plaintext)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPatch, blobRequest{UUID: id, Content: content, Metadata: metadata}, nil)
+}
+
+// Delete delete blob
+func (c *Client) Delete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, blobRequest{UUID: id}, nil)
+}
+
+// GetAll load and decrypt all blobs, blob that fails integrity check is reported as error
+func (c *Client) GetAll(ctx context.Context) ([]Item, error) {
+	res := &blobsResponse{}
+	if err := c.do(ctx, http.MethodGet, nil, res); err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(res.Items))
+	for _, v := range res.Items {
+		plaintext, err := Open(c.key, v.UUID, v.Content)
+		if err != nil {
+			return nil, fmt.Errorf("blob %s: %w", v.UUID, err)
+		}
+		items = append(items, Item{UUID: v.UUID, Metadata: v.Metadata, Plaintext: plaintext})
+	}
+	return items, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, body interface{}, out interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+blobsPath, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		message := struct {
+			Message string `json:"message"`
+		}{}
+		_ = json.NewDecoder(res.Body).Decode(&message)
+		if message.Message == "" {
+			message.Message = res.Status
+		}
+		return errors.New(message.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
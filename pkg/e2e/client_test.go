@@ -0,0 +1,106 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blobServer stores blobs as server does, without access to key
+type blobServer struct {
+	mu    sync.Mutex
+	blobs map[string]blobRequest
+	order []string
+}
+
+func (s *blobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid token"}`))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req := blobRequest{}
+	if r.Method != http.MethodGet {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.order = append(s.order, req.UUID)
+		s.blobs[req.UUID] = req
+	case http.MethodPatch:
+		s.blobs[req.UUID] = req
+	case http.MethodDelete:
+		delete(s.blobs, req.UUID)
+	case http.MethodGet:
+		res := blobsResponse{Items: []blobRequest{}}
+		for _, id := range s.order {
+			if blob, ok := s.blobs[id]; ok {
+				res.Items = append(res.Items, blob)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"uuid": req.UUID})
+}
+
+func TestClient(t *testing.T) {
+	store := &blobServer{blobs: map[string]blobRequest{}}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewClient(server.URL, "token", DeriveKey("user", "passphrase"))
+	require.NoError(t, err)
+
+	loginUUID, err := client.Create(ctx, json.RawMessage(`{"kind":"login"}`), []byte(`{"login":"alice","password":"p@ss"}`))
+	require.NoError(t, err)
+	noteUUID, err := client.Create(ctx, json.RawMessage(`{"kind":"note"}`), []byte("note text"))
+	require.NoError(t, err)
+
+	// server sees only ciphertext
+	assert.NotContains(t, string(store.blobs[loginUUID].Content), "p@ss")
+
+	err = client.Update(ctx, noteUUID, json.RawMessage(`{"kind":"note"}`), []byte("new note text"))
+	require.NoError(t, err)
+
+	items, err := client.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, loginUUID, items[0].UUID)
+	assert.JSONEq(t, `{"kind":"login"}`, string(items[0].Metadata))
+	assert.Equal(t, `{"login":"alice","password":"p@ss"}`, string(items[0].Plaintext))
+	assert.Equal(t, "new note text", string(items[1].Plaintext))
+
+	// server swaps content of blobs
+	login, note := store.blobs[loginUUID], store.blobs[noteUUID]
+	login.Content, note.Content = note.Content, login.Content
+	store.blobs[loginUUID], store.blobs[noteUUID] = login, note
+	_, err = client.GetAll(ctx)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	require.NoError(t, client.Delete(ctx, loginUUID))
+	require.NoError(t, client.Delete(ctx, noteUUID))
+	items, err = client.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	client, err = NewClient(server.URL, "wrong", DeriveKey("user", "passphrase"))
+	require.NoError(t, err)
+	_, err = client.GetAll(ctx)
+	assert.EqualError(t, err, "invalid token")
+}
@@ -0,0 +1,89 @@
+// Package e2e is reference client of zero-knowledge mode. Data is encrypted on client
+// with key derived from passphrase, server stores ciphertext as opaque blob and never sees the key.
+package e2e
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Blob format
+//
+//	"DKC" | version (1) | nonce (24) | XChaCha20-Poly1305 ciphertext and tag
+//
+// Header and blob uuid are authenticated as additional data, so server can not move
+// ciphertext to another blob. Metadata is sent in clear and is not authenticated,
+// anything sensitive belongs to payload.
+const (
+	magic      = "DKC"
+	version    = 0x01
+	headerSize = len(magic) + 1
+	// KeySize size of client key
+	KeySize = chacha20poly1305.KeySize
+)
+
+// Argon2id parameters of client key
+const (
+	kdfTime    = 3
+	kdfMemory  = 64 * 1024
+	kdfThreads = 4
+)
+
+// ErrIntegrity blob was modified, moved to another uuid or encrypted with another key
+var ErrIntegrity = errors.New("blob integrity check failed")
+
+// DeriveKey derive client key from passphrase with Argon2id. Salt is derived from login,
+// so every device derives same key without asking server.
+// Passphrase must differ from master password, master password is sent to server at login
+func DeriveKey(login string, passphrase string) []byte {
+	salt := sha256.Sum256([]byte("data-keeper e2e salt " + login))
+	return argon2.IDKey([]byte(passphrase), salt[:], kdfTime, kdfMemory, kdfThreads, KeySize)
+}
+
+// Seal encrypt plaintext of blob with uuid
+func Seal(key []byte, uuid string, plaintext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, headerSize+aead.NonceSize(), headerSize+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	copy(blob, magic)
+	blob[len(magic)] = version
+	nonce := blob[headerSize:]
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(blob, nonce, plaintext, additionalData(blob[:headerSize], uuid)), nil
+}
+
+// Open decrypt blob with uuid
+func Open(key []byte, uuid string, blob []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blob) < headerSize+aead.NonceSize()+aead.Overhead() ||
+		string(blob[:len(magic)]) != magic || blob[len(magic)] != version {
+		return nil, errors.New("unknown blob format")
+	}
+
+	header := blob[:headerSize]
+	nonce := blob[headerSize : headerSize+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, blob[headerSize+aead.NonceSize():], additionalData(header, uuid))
+	if err != nil {
+		return nil, ErrIntegrity
+	}
+	return plaintext, nil
+}
+
+func additionalData(header []byte, uuid string) []byte {
+	return append(append([]byte{}, header...), uuid...)
+}
@@ -0,0 +1,48 @@
+package e2e
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, KeySize)
+	id := uuid.NewString()
+
+	blob, err := Seal(key, id, []byte("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("DKC\x01"), blob[:headerSize])
+	assert.NotContains(t, string(blob), "secret")
+
+	plaintext, err := Open(key, id, blob)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	// blob moved to another uuid
+	_, err = Open(key, uuid.NewString(), blob)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// another key
+	_, err = Open(bytes.Repeat([]byte{0x02}, KeySize), id, blob)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	// modified blob
+	blob[len(blob)-1] ^= 0x01
+	_, err = Open(key, id, blob)
+	assert.ErrorIs(t, err, ErrIntegrity)
+
+	_, err = Open(key, id, []byte("not a blob"))
+	assert.Error(t, err)
+}
+
+func TestDeriveKey(t *testing.T) {
+	key := DeriveKey("user", "passphrase")
+	assert.Len(t, key, KeySize)
+	assert.Equal(t, key, DeriveKey("user", "passphrase"))
+	assert.NotEqual(t, key, DeriveKey("another-user", "passphrase"))
+	assert.NotEqual(t, key, DeriveKey("user", "another-passphrase"))
+}